@@ -0,0 +1,313 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Cache build: setiap keluaran (workbook, tiap grafik, laporan, tiap profil)
+// hanya dibuat ulang bila kunci inputnya berubah. Kunci adalah SHA-256 dari
+// isi CSV, konfigurasi yang sudah dimuat, flag yang relevan, bahasa dan biner
+// program itu sendiri, sehingga perubahan kode juga membatalkan cache. CSV
+// yang sudah di-parse disimpan sebagai gob agar run berikutnya tidak perlu
+// mem-parse ulang. Manifest mencatat ukuran dan waktu modifikasi tiap file
+// keluaran; file yang hilang atau diubah dianggap basi. Tanggal pembuatan di
+// laporan tidak termasuk kunci, jadi laporan tidak dibuat ulang hanya karena
+// hari berganti (pakai -rebuild).
+const (
+	defaultCacheDir   = ".tet-cache"
+	cacheManifestFile = "manifest.json"
+	cacheDataDir      = "data"
+	cacheChartDir     = "grafik"
+)
+
+type cacheOutput struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mod_time"`
+}
+
+type cacheEntry struct {
+	Key     string        `json:"key"`
+	Outputs []cacheOutput `json:"outputs"`
+}
+
+// BuildCache menyimpan manifest artefak. Inputs adalah kunci bersama semua
+// artefak analisis (data, konfigurasi skor/skenario, mode sensitivitas dan
+// bahasa) yang diisi main setelah data dibaca; Outputs mengumpulkan semua
+// file yang dibuat atau dipakai ulang pada run ini. Tahap pipeline memakai
+// cache bersamaan, jadi Entries dan Outputs dijaga mutex.
+type BuildCache struct {
+	Dir      string
+	Disabled bool
+	Inputs   string
+	Entries  map[string]cacheEntry
+	Outputs  []string
+	Rebuild  bool
+	binary   string
+	mutex    sync.Mutex
+}
+
+var buildCache = &BuildCache{Disabled: true}
+
+// openBuildCache membuka cache di dir; dir kosong mematikan cache. Dengan
+// rebuild manifest dan data lama diabaikan lalu ditulis ulang.
+func openBuildCache(dir string, rebuild bool) *BuildCache {
+	cache := &BuildCache{Dir: dir, Disabled: dir == "", Rebuild: rebuild, Entries: make(map[string]cacheEntry)}
+	if cache.Disabled {
+		return cache
+	}
+
+	executable, err := os.Executable()
+	if err == nil {
+		cache.binary, err = hashFile(executable)
+	}
+	if err != nil {
+		fmt.Fprintf(progressOutput, "⚠️  Cache dimatikan, biner program tidak bisa di-hash: %v\n", err)
+		cache.Disabled = true
+		return cache
+	}
+
+	if rebuild {
+		return cache
+	}
+	content, err := os.ReadFile(filepath.Join(dir, cacheManifestFile))
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(content, &cache.Entries); err != nil {
+		fmt.Fprintf(progressOutput, "⚠️  Manifest cache rusak, semua keluaran dibuat ulang: %v\n", err)
+		cache.Entries = make(map[string]cacheEntry)
+	}
+	return cache
+}
+
+// key menghitung hash dari bagian-bagian input (di-encode sebagai JSON)
+// ditambah hash biner program.
+func (c *BuildCache) key(parts ...any) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.binary))
+	encoder := json.NewEncoder(hash)
+	for _, part := range parts {
+		if err := encoder.Encode(part); err != nil {
+			panic(fmt.Sprintf("kunci cache tidak bisa di-encode: %v", err))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// build menjalankan create kecuali artefak name dengan kunci key masih
+// mutakhir dan semua outputs-nya utuh; artefak yang dilewati dicetak.
+func (c *BuildCache) build(name, key string, outputs []string, create func() error) error {
+	created, err := c.update(name, key, outputs, create)
+	if err == nil && !created {
+		fmt.Fprintf(progressOutput, "♻️  %s tidak berubah, memakai hasil sebelumnya\n", name)
+	}
+	return err
+}
+
+// update sama dengan build tanpa pesan; nilai kembali true bila artefak
+// dibuat ulang. Artefak yang gagal dibuat tidak dicatat.
+func (c *BuildCache) update(name, key string, outputs []string, create func() error) (bool, error) {
+	c.mutex.Lock()
+	c.Outputs = append(c.Outputs, outputs...)
+	fresh := c.fresh(name, key, outputs)
+	c.mutex.Unlock()
+	if fresh {
+		return false, nil
+	}
+	if err := create(); err != nil {
+		return false, err
+	}
+	c.record(name, key, outputs)
+	return true, nil
+}
+
+func (c *BuildCache) fresh(name, key string, outputs []string) bool {
+	entry, ok := c.Entries[name]
+	if c.Disabled || !ok || entry.Key != key || len(entry.Outputs) != len(outputs) {
+		return false
+	}
+	for i, output := range entry.Outputs {
+		if output.Path != outputs[i] || statOutput(output.Path) != output {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *BuildCache) record(name, key string, outputs []string) {
+	if c.Disabled {
+		return
+	}
+	entry := cacheEntry{Key: key}
+	for _, path := range outputs {
+		entry.Outputs = append(entry.Outputs, statOutput(path))
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.Entries[name] = entry
+	c.save()
+}
+
+func (c *BuildCache) entry(name string) cacheEntry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.Entries[name]
+}
+
+func (c *BuildCache) save() {
+	content, err := json.MarshalIndent(c.Entries, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(c.Dir, 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(c.Dir, cacheManifestFile), content, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(progressOutput, "⚠️  Manifest cache tidak bisa disimpan: %v\n", err)
+	}
+}
+
+// upToDate memeriksa artefak gabungan "run" yang mencakup semua keluaran run
+// sebelumnya; bila mutakhir, pemodelan bisa dilewati seluruhnya.
+func (c *BuildCache) upToDate(key string) ([]string, bool) {
+	entry := c.entry("run")
+	if entry.Key == "" {
+		return nil, false
+	}
+	var outputs []string
+	for _, output := range entry.Outputs {
+		outputs = append(outputs, output.Path)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return outputs, c.fresh("run", key, outputs)
+}
+
+// finish mencatat artefak "run" dari semua keluaran yang disentuh run ini.
+func (c *BuildCache) finish(key string) {
+	c.mutex.Lock()
+	outputs := slices.Clone(c.Outputs)
+	c.mutex.Unlock()
+	slices.Sort(outputs)
+	c.record("run", key, slices.Compact(outputs))
+}
+
+func statOutput(path string) cacheOutput {
+	info, err := os.Stat(path)
+	if err != nil {
+		return cacheOutput{Path: path, Size: -1}
+	}
+	return cacheOutput{Path: path, Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+}
+
+// loadRawData membaca CSV lewat cache gob yang dikunci hash isi file, dan
+// mengembalikan data beserta hash tersebut.
+func loadRawData(path string) ([]RawPalmOilData, string, error) {
+	hash, err := hashFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("error membuka file CSV: %w", err)
+	}
+	if buildCache.Disabled {
+		data, err := readCSVData(path, activeMetrics)
+		return data, hash, err
+	}
+
+	cached := filepath.Join(buildCache.Dir, cacheDataDir, buildCache.key(hash, activeMetrics)+".gob")
+	if !buildCache.Rebuild {
+		if data, err := readCachedData(cached); err == nil {
+			fmt.Fprintf(progressOutput, "📊 Data dibaca dari cache: %d records (2003-2022) dari %s\n", len(data), path)
+			return data, hash, nil
+		}
+	}
+
+	data, err := readCSVData(path, activeMetrics)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := writeCachedData(cached, data); err != nil {
+		fmt.Fprintf(progressOutput, "⚠️  Cache data tidak bisa disimpan: %v\n", err)
+		return data, hash, nil
+	}
+	// Hapus gob versi lama file yang sama agar cache tidak terus membesar.
+	name := "data:" + path
+	for _, output := range buildCache.entry(name).Outputs {
+		if output.Path != cached {
+			os.Remove(output.Path)
+		}
+	}
+	buildCache.record(name, hash, []string{cached})
+	return data, hash, nil
+}
+
+func readCachedData(path string) ([]RawPalmOilData, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var data []RawPalmOilData
+	err = gob.NewDecoder(file).Decode(&data)
+	return data, err
+}
+
+func writeCachedData(path string, data []RawPalmOilData) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(file).Encode(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashDirectory menghitung hash isi semua file di dir (rekursif), mis.
+// direktori template; direktori yang tidak ada menghasilkan string kosong.
+func hashDirectory(dir string) string {
+	hash := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		fileHash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s %s\n", filepath.ToSlash(path), fileHash)
+		return nil
+	})
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
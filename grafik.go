@@ -0,0 +1,407 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+)
+
+// Semua grafik disimpan lewat saveChart. Format, preset ukuran/DPI, tema
+// warna, direktori, nama file, mode label scatter dan sumbu Y grid panel
+// dibaca dari chartConfigFile lalu bisa ditimpa flag -chart-*, -label-* dan
+// -panel-y. Preset dengan lebar 0 memakai ukuran bawaan masing-masing
+// grafik; preset lain menskalakan lebar dan menjaga rasio.
+const chartConfigFile = "grafik.json"
+
+type ChartConfig struct {
+	Formats    []string               `json:"formats"`
+	Preset     string                 `json:"preset"`
+	DPI        int                    `json:"dpi"`
+	Theme      string                 `json:"theme"`
+	OutputDir  string                 `json:"output_dir"`
+	FileNames  map[string]string      `json:"file_names"`
+	Presets    map[string]ChartPreset `json:"presets"`
+	LabelMode  string                 `json:"label_mode"`
+	LabelTop   int                    `json:"label_top"`
+	PanelYAxis string                 `json:"panel_y_axis"`
+}
+
+type ChartPreset struct {
+	WidthInch float64 `json:"width_inch"`
+	DPI       int     `json:"dpi"`
+}
+
+// ChartTheme berisi warna yang dipakai ulang oleh beberapa grafik.
+// Growth berurutan dari pertumbuhan >200% sampai negatif; Low/High dipakai
+// untuk skala divergen (tornado -/+, klaster LISA LOW/HIGH).
+type ChartTheme struct {
+	Growth     [5]color.RGBA
+	Investment map[string]color.RGBA
+	Series     []color.RGBA
+	Scenarios  []color.RGBA
+	Low        color.RGBA
+	High       color.RGBA
+	LowLight   color.RGBA
+	HighLight  color.RGBA
+	Before     color.RGBA
+	After      color.RGBA
+}
+
+var chartFormats = []string{"png", "svg", "pdf", "eps"}
+
+var chartPresets = map[string]ChartPreset{
+	"default": {WidthInch: 0, DPI: 96},
+	"slide":   {WidthInch: 13.33, DPI: 150},
+	"print":   {WidthInch: 10, DPI: 300},
+}
+
+var okabeIto = []color.RGBA{
+	{R: 0, G: 114, B: 178, A: 255}, {R: 230, G: 159, B: 0, A: 255}, {R: 0, G: 158, B: 115, A: 255},
+	{R: 213, G: 94, B: 0, A: 255}, {R: 86, G: 180, B: 233, A: 255}, {R: 204, G: 121, B: 167, A: 255},
+	{R: 240, G: 228, B: 66, A: 255}, {R: 0, G: 0, B: 0, A: 255},
+}
+
+var viridis = []color.RGBA{
+	{R: 68, G: 1, B: 84, A: 255}, {R: 70, G: 50, B: 127, A: 255}, {R: 54, G: 92, B: 141, A: 255},
+	{R: 39, G: 127, B: 142, A: 255}, {R: 31, G: 161, B: 135, A: 255}, {R: 74, G: 194, B: 109, A: 255},
+	{R: 159, G: 218, B: 58, A: 255}, {R: 253, G: 231, B: 37, A: 255},
+}
+
+var chartThemes = map[string]ChartTheme{
+	"klasik": {
+		Growth: [5]color.RGBA{
+			{R: 0, G: 100, B: 0, A: 255}, {R: 34, G: 139, B: 34, A: 255}, {R: 173, G: 255, B: 47, A: 255},
+			{R: 255, G: 255, B: 0, A: 255}, {R: 255, G: 0, B: 0, A: 255},
+		},
+		Investment: map[string]color.RGBA{
+			"VERY HIGH": {R: 0, G: 100, B: 0, A: 255},
+			"HIGH":      {R: 34, G: 139, B: 34, A: 255},
+			"MEDIUM":    {R: 255, G: 165, B: 0, A: 255},
+			"LOW":       {R: 255, G: 69, B: 0, A: 255},
+			"VERY LOW":  {R: 220, G: 20, B: 60, A: 255},
+			"":          {R: 65, G: 105, B: 225, A: 255},
+		},
+		Series: []color.RGBA{
+			{R: 31, G: 119, B: 180, A: 255}, {R: 255, G: 127, B: 14, A: 255}, {R: 44, G: 160, B: 44, A: 255},
+			{R: 214, G: 39, B: 40, A: 255}, {R: 148, G: 103, B: 189, A: 255}, {R: 140, G: 86, B: 75, A: 255},
+			{R: 227, G: 119, B: 194, A: 255}, {R: 127, G: 127, B: 127, A: 255}, {R: 188, G: 189, B: 34, A: 255},
+			{R: 23, G: 190, B: 207, A: 255},
+		},
+		Scenarios: []color.RGBA{
+			{R: 0, G: 100, B: 0, A: 255}, {R: 178, G: 34, B: 34, A: 255}, {R: 30, G: 144, B: 255, A: 255},
+			{R: 255, G: 140, B: 0, A: 255}, {R: 128, G: 0, B: 128, A: 255}, {R: 0, G: 139, B: 139, A: 255},
+			{R: 139, G: 69, B: 19, A: 255},
+		},
+		Low:       color.RGBA{R: 33, G: 102, B: 172, A: 255},
+		High:      color.RGBA{R: 178, G: 24, B: 43, A: 255},
+		LowLight:  color.RGBA{R: 146, G: 197, B: 222, A: 255},
+		HighLight: color.RGBA{R: 244, G: 165, B: 130, A: 255},
+		Before:    color.RGBA{R: 255, G: 140, B: 0, A: 255},
+		After:     color.RGBA{R: 0, G: 100, B: 0, A: 255},
+	},
+	"okabe-ito": {
+		Growth: [5]color.RGBA{
+			{R: 0, G: 114, B: 178, A: 255}, {R: 86, G: 180, B: 233, A: 255}, {R: 0, G: 158, B: 115, A: 255},
+			{R: 240, G: 228, B: 66, A: 255}, {R: 213, G: 94, B: 0, A: 255},
+		},
+		Investment: map[string]color.RGBA{
+			"VERY HIGH": {R: 0, G: 114, B: 178, A: 255},
+			"HIGH":      {R: 86, G: 180, B: 233, A: 255},
+			"MEDIUM":    {R: 240, G: 228, B: 66, A: 255},
+			"LOW":       {R: 230, G: 159, B: 0, A: 255},
+			"VERY LOW":  {R: 213, G: 94, B: 0, A: 255},
+			"":          {R: 204, G: 121, B: 167, A: 255},
+		},
+		Series:    okabeIto,
+		Scenarios: okabeIto,
+		Low:       color.RGBA{R: 0, G: 114, B: 178, A: 255},
+		High:      color.RGBA{R: 213, G: 94, B: 0, A: 255},
+		LowLight:  color.RGBA{R: 86, G: 180, B: 233, A: 255},
+		HighLight: color.RGBA{R: 230, G: 159, B: 0, A: 255},
+		Before:    color.RGBA{R: 230, G: 159, B: 0, A: 255},
+		After:     color.RGBA{R: 0, G: 114, B: 178, A: 255},
+	},
+	"viridis": {
+		Growth: [5]color.RGBA{viridis[0], viridis[2], viridis[4], viridis[6], viridis[7]},
+		Investment: map[string]color.RGBA{
+			"VERY HIGH": viridis[0],
+			"HIGH":      viridis[2],
+			"MEDIUM":    viridis[4],
+			"LOW":       viridis[6],
+			"VERY LOW":  viridis[7],
+			"":          {R: 150, G: 150, B: 150, A: 255},
+		},
+		Series:    viridis,
+		Scenarios: viridis,
+		Low:       viridis[1],
+		High:      viridis[6],
+		LowLight:  viridis[3],
+		HighLight: viridis[7],
+		Before:    viridis[6],
+		After:     viridis[1],
+	},
+}
+
+var (
+	chartSettings = defaultChartConfig()
+	chartTheme    = chartThemes["klasik"]
+	savedCharts   []string
+
+	// reportImageDir diisi prepareReportImages; kosong berarti laporan PDF
+	// memakai PNG keluaran biasa.
+	reportImageDir string
+
+	savedChartsMutex sync.Mutex
+)
+
+func defaultChartConfig() ChartConfig {
+	return ChartConfig{
+		Formats:    []string{"png"},
+		Preset:     "default",
+		Theme:      "klasik",
+		OutputDir:  ".",
+		LabelMode:  "semua",
+		LabelTop:   15,
+		PanelYAxis: "bersama",
+	}
+}
+
+// loadChartConfig membaca chartConfigFile (bila ada) lalu menerapkan flag
+// yang diisi; string kosong atau 0 berarti flag tidak dipakai.
+func loadChartConfig(formats, preset, theme, dir string, dpi int, labelMode string, labelTop int, panelYAxis string) ChartConfig {
+	config := defaultChartConfig()
+
+	content, err := os.ReadFile(chartConfigFile)
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatal("Error membaca konfigurasi grafik:", err)
+	} else if err == nil {
+		if err := json.Unmarshal(content, &config); err != nil {
+			log.Fatal("Error parsing konfigurasi grafik:", err)
+		}
+	}
+
+	if formats != "" {
+		config.Formats = strings.Split(formats, ",")
+	}
+	if preset != "" {
+		config.Preset = preset
+	}
+	if theme != "" {
+		config.Theme = theme
+	}
+	if dir != "" {
+		config.OutputDir = dir
+	}
+	if dpi > 0 {
+		config.DPI = dpi
+	}
+	if labelMode != "" {
+		config.LabelMode = labelMode
+	}
+	if labelTop > 0 {
+		config.LabelTop = labelTop
+	}
+	if panelYAxis != "" {
+		config.PanelYAxis = panelYAxis
+	}
+
+	for i, format := range config.Formats {
+		config.Formats[i] = strings.ToLower(strings.TrimSpace(format))
+		if !slices.Contains(chartFormats, config.Formats[i]) {
+			log.Fatalf("Format grafik tidak dikenal: %q (gunakan %s)", format, strings.Join(chartFormats, ", "))
+		}
+	}
+	if len(config.Formats) == 0 {
+		log.Fatal("Konfigurasi grafik: minimal satu format keluaran")
+	}
+	for name, custom := range config.Presets {
+		chartPresets[name] = custom
+	}
+	if _, ok := chartPresets[config.Preset]; !ok {
+		log.Fatalf("Preset grafik tidak dikenal: %q", config.Preset)
+	}
+	if _, ok := chartThemes[config.Theme]; !ok {
+		log.Fatalf("Tema grafik tidak dikenal: %q (gunakan klasik, okabe-ito atau viridis)", config.Theme)
+	}
+	if !slices.Contains(labelModes, config.LabelMode) {
+		log.Fatalf("Mode label grafik tidak dikenal: %q (gunakan %s)", config.LabelMode, strings.Join(labelModes, ", "))
+	}
+	if config.LabelMode == "top" && config.LabelTop <= 0 {
+		log.Fatal("Konfigurasi grafik: label_top harus lebih dari 0")
+	}
+	if !slices.Contains(panelYAxisModes, config.PanelYAxis) {
+		log.Fatalf("Sumbu Y panel tidak dikenal: %q (gunakan %s)", config.PanelYAxis, strings.Join(panelYAxisModes, ", "))
+	}
+	if err := os.MkdirAll(config.OutputDir, 0o755); err != nil {
+		log.Fatal("Error membuat direktori grafik:", err)
+	}
+
+	return config
+}
+
+func setChartConfig(config ChartConfig) {
+	chartSettings = config
+	chartTheme = chartThemes[config.Theme]
+}
+
+func (t ChartTheme) growthColor(growthRate float64) color.RGBA {
+	switch {
+	case growthRate > 200:
+		return t.Growth[0]
+	case growthRate > 100:
+		return t.Growth[1]
+	case growthRate > 50:
+		return t.Growth[2]
+	case growthRate > 0:
+		return t.Growth[3]
+	}
+	return t.Growth[4]
+}
+
+func (t ChartTheme) investmentColor(level string) color.RGBA {
+	if c, ok := t.Investment[level]; ok {
+		return c
+	}
+	return t.Investment[""]
+}
+
+func chartBaseName(name string) string {
+	if custom, ok := chartSettings.FileNames[name]; ok && custom != "" {
+		return custom
+	}
+	return name
+}
+
+// chartImagePath mengembalikan path PNG grafik untuk disisipkan ke laporan
+// PDF: PNG keluaran biasa, atau salinan internal di reportImageDir bila png
+// tidak termasuk format keluaran.
+func chartImagePath(name string) string {
+	if reportImageDir != "" {
+		return filepath.Join(reportImageDir, chartBaseName(name)+".png")
+	}
+	return filepath.Join(chartSettings.OutputDir, chartBaseName(name)+".png")
+}
+
+// prepareReportImages mengisi reportImageDir bila -chart-format tidak memuat
+// png, karena laporan PDF hanya bisa menyisipkan PNG. Dengan cache aktif PNG
+// internal disimpan di direktori cache agar ikut dipakai ulang; tanpa cache
+// dipakai direktori sementara yang dihapus oleh fungsi cleanup.
+func prepareReportImages() (cleanup func(), err error) {
+	cleanup = func() {}
+	if slices.Contains(chartSettings.Formats, "png") {
+		return cleanup, nil
+	}
+	if !buildCache.Disabled {
+		reportImageDir = filepath.Join(buildCache.Dir, cacheChartDir)
+		return cleanup, os.MkdirAll(reportImageDir, 0o755)
+	}
+	dir, err := os.MkdirTemp("", "tet-grafik-")
+	if err != nil {
+		return cleanup, err
+	}
+	reportImageDir = dir
+	return func() { os.RemoveAll(dir) }, nil
+}
+
+// saveChart menyimpan plot dengan ukuran bawaan width x height dalam semua
+// format yang dikonfigurasi; name adalah nama file tanpa ekstensi.
+func saveChart(p *plot.Plot, width, height vg.Length, name string) error {
+	return saveChartCanvas(p.Draw, width, height, name)
+}
+
+// saveChartCanvas sama dengan saveChart untuk grafik yang tidak terdiri dari
+// satu plot saja (mis. grid small multiples); render menggambar seluruh
+// kanvas.
+func saveChartCanvas(render func(draw.Canvas), width, height vg.Length, name string) error {
+	preset := chartPresets[chartSettings.Preset]
+	if preset.WidthInch > 0 {
+		height = height * vg.Length(preset.WidthInch) * vg.Inch / width
+		width = vg.Length(preset.WidthInch) * vg.Inch
+	}
+	dpi := chartDPI()
+
+	formats := chartSettings.Formats
+	var paths []string
+	for _, format := range formats {
+		paths = append(paths, filepath.Join(chartSettings.OutputDir, chartBaseName(name)+"."+format))
+	}
+	savedChartsMutex.Lock()
+	savedCharts = append(savedCharts, paths...)
+	savedChartsMutex.Unlock()
+
+	// PNG internal untuk laporan PDF tidak dicetak sebagai keluaran grafik,
+	// tetapi tetap dicatat cache agar run berikutnya tahu file itu ada.
+	if reportImageDir != "" && isReportChart(name) {
+		formats = append(slices.Clone(formats), "png")
+		paths = append(slices.Clone(paths), chartImagePath(name))
+	}
+
+	key := buildCache.key(buildCache.Inputs, name, chartSettings, width, height)
+	return buildCache.build(name, key, paths, func() error {
+		for i, format := range formats {
+			writer, err := chartWriter(render, width, height, format, dpi)
+			if err != nil {
+				return err
+			}
+			if err := writeChartFile(paths[i], writer); err != nil {
+				return fmt.Errorf("error menyimpan grafik %s: %w", paths[i], err)
+			}
+		}
+		return nil
+	})
+}
+
+// chartDPI adalah DPI PNG dari preset aktif, kecuali ditimpa -chart-dpi.
+func chartDPI() int {
+	if chartSettings.DPI > 0 {
+		return chartSettings.DPI
+	}
+	return chartPresets[chartSettings.Preset].DPI
+}
+
+func chartWriter(render func(draw.Canvas), width, height vg.Length, format string, dpi int) (io.WriterTo, error) {
+	if format == "png" {
+		canvas := vgimg.NewWith(vgimg.UseWH(width, height), vgimg.UseDPI(dpi))
+		render(draw.New(canvas))
+		return vgimg.PngCanvas{Canvas: canvas}, nil
+	}
+	canvas, err := draw.NewFormattedCanvas(width, height, format)
+	if err != nil {
+		return nil, err
+	}
+	render(draw.New(canvas))
+	return canvas, nil
+}
+
+func writeChartFile(path string, writer io.WriterTo) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// printSavedCharts mencetak grafik berurutan nama karena grafik dibuat
+// paralel.
+func printSavedCharts() {
+	slices.Sort(savedCharts)
+	for _, path := range savedCharts {
+		fmt.Fprintf(progressOutput, "   - %s\n", path)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Grid small multiples: satu panel per provinsi, atau per kabupaten bila
+// -panel-province diisi. Tiap panel memuat data tahunan 2003-2022, titik
+// puncak, tren linear hasil regresi dan perpanjangan proyeksi sampai 2030
+// dengan laju yang sama seperti Projection2030. Sumbu Y bisa dibagi semua
+// panel ("bersama", mudah membandingkan skala) atau per panel ("bebas",
+// bentuk kurva provinsi kecil tetap terlihat).
+const (
+	panelWidth       = 3.6 * vg.Inch
+	panelHeight      = 2.6 * vg.Inch
+	panelTitleHeight = 0.6 * vg.Inch
+	panelEndYear     = 2030
+)
+
+var panelYAxisModes = []string{"bersama", "bebas"}
+
+func createTrajectoryGrid(ctx context.Context, models []ProvinceModel, cube *DataCube, province string) error {
+	panels := models
+	title := tr("TRAJEKTORI {{METRIC}} KELAPA SAWIT PER PROVINSI 2003-2022")
+	name := "panel_trajektori_provinsi"
+	panelTitle := getShortProvinceName
+	if province != "" {
+		panelTitle = strings.TrimSpace
+		var err error
+		panels, province, err = buildRegencyTrajectories(ctx, cube, province)
+		if err != nil {
+			return err
+		}
+		title = trf("TRAJEKTORI {{METRIC}} KELAPA SAWIT PER KABUPATEN %s 2003-2022", province)
+		name = "panel_trajektori_kabupaten_" + fileSlug(province)
+	}
+
+	sharedMax := 0.0
+	for _, model := range panels {
+		sharedMax = math.Max(sharedMax, trajectoryMax(model))
+	}
+
+	columns := int(math.Ceil(math.Sqrt(float64(len(panels)))))
+	rows := (len(panels) + columns - 1) / columns
+	grid := make([][]*plot.Plot, rows)
+	for row := range grid {
+		grid[row] = make([]*plot.Plot, columns)
+	}
+	for i, model := range panels {
+		p, err := createTrajectoryPanel(model, panelTitle(model.Province), i == 0)
+		if err != nil {
+			return err
+		}
+		if chartSettings.PanelYAxis == "bersama" {
+			p.Y.Max = sharedMax * 1.05
+		}
+		grid[i/columns][i%columns] = p
+	}
+
+	titleStyle := text.Style{
+		Color:   color.Black,
+		Font:    font.From(plot.DefaultFont, vg.Points(18)),
+		XAlign:  draw.XCenter,
+		YAlign:  draw.YCenter,
+		Handler: plot.DefaultTextHandler,
+	}
+
+	render := func(c draw.Canvas) {
+		c.FillText(titleStyle, vg.Point{X: c.Center().X, Y: c.Max.Y - panelTitleHeight/2}, title)
+		body := draw.Crop(c, 0, 0, 0, -panelTitleHeight)
+		tiles := draw.Tiles{Rows: rows, Cols: columns, PadX: vg.Points(8), PadY: vg.Points(8), PadLeft: vg.Points(4), PadRight: vg.Points(8), PadBottom: vg.Points(4)}
+		canvases := plot.Align(grid, tiles, body)
+		for row := range grid {
+			for column, p := range grid[row] {
+				if p != nil {
+					p.Draw(canvases[row][column])
+				}
+			}
+		}
+	}
+
+	return saveChartCanvas(render, vg.Length(columns)*panelWidth, vg.Length(rows)*panelHeight+panelTitleHeight, name)
+}
+
+// buildRegencyTrajectories menyusun model per kabupaten dalam satu provinsi
+// dari seri kubus, dengan perhitungan pertumbuhan, puncak dan proyeksi yang
+// sama seperti model provinsi, lewat worker pool. Nama provinsi dicocokkan
+// tanpa membedakan huruf besar/kecil dan dikembalikan dengan ejaan dari data.
+func buildRegencyTrajectories(ctx context.Context, cube *DataCube, province string) ([]ProvinceModel, string, error) {
+	matched, ok := cube.FindProvince(province)
+	if !ok {
+		return nil, "", fmt.Errorf("provinsi %q tidak ditemukan di data kabupaten", province)
+	}
+	series, err := cube.Series(LevelRegency, CubeFilter{Provinces: []string{matched}})
+	if err != nil {
+		return nil, "", err
+	}
+
+	models := make([]ProvinceModel, len(series))
+	err = parallelFor(ctx, len(series), func(i int) error {
+		regency, yearlyData := series[i].Name, series[i].Values
+		model := ProvinceModel{
+			Province:      regency,
+			TotalArea2003: yearlyData[2003],
+			TotalArea2022: yearlyData[2022],
+			YearlyData:    yearlyData,
+		}
+		if model.TotalArea2003 > 0 {
+			model.GrowthRate20Years = ((model.TotalArea2022 - model.TotalArea2003) / model.TotalArea2003) * 100
+			model.AnnualGrowthRate = model.GrowthRate20Years / 20
+		}
+		model.PeakYear, model.PeakArea = findPeakYearAndArea(yearlyData)
+		model.Projection2030 = calculateProjection2030(model)
+		models[i] = model
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].TotalArea2022 != models[j].TotalArea2022 {
+			return models[i].TotalArea2022 > models[j].TotalArea2022
+		}
+		return models[i].Province < models[j].Province
+	})
+	return models, matched, nil
+}
+
+func createTrajectoryPanel(model ProvinceModel, title string, legend bool) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = title
+	p.Title.TextStyle.Font.Size = vg.Points(10)
+	p.X.Tick.Label.Font.Size = vg.Points(7)
+	p.Y.Tick.Label.Font.Size = vg.Points(7)
+	p.X.Tick.Marker = plot.ConstantTicks([]plot.Tick{
+		{Value: 2005, Label: "2005"}, {Value: 2010, Label: "2010"}, {Value: 2015, Label: "2015"},
+		{Value: 2020, Label: "2020"}, {Value: 2025, Label: "2025"}, {Value: 2030, Label: "2030"},
+	})
+	p.Y.Label.Text = tr("Ribu {{unit}}")
+	p.Y.Label.TextStyle.Font.Size = vg.Points(7)
+
+	var actual plotter.XYs
+	for _, year := range getSortedYears(model.YearlyData) {
+		actual = append(actual, plotter.XY{X: float64(year), Y: model.YearlyData[year] / 1000})
+	}
+	line, err := plotter.NewLine(actual)
+	if err != nil {
+		return nil, err
+	}
+	line.Color = chartTheme.Series[0]
+	line.Width = vg.Points(1.5)
+
+	slope, intercept := linearTrend(model.YearlyData)
+	trend, err := plotter.NewLine(plotter.XYs{
+		{X: 2003, Y: (intercept + slope*2003) / 1000},
+		{X: 2022, Y: (intercept + slope*2022) / 1000},
+	})
+	if err != nil {
+		return nil, err
+	}
+	trend.Color = color.Gray{Y: 140}
+	trend.Width = vg.Points(1)
+	trend.Dashes = []vg.Length{vg.Points(3), vg.Points(2)}
+
+	annualGrowth := baselineAnnualGrowth(model)
+	var extension plotter.XYs
+	for year := 2022; year <= panelEndYear; year++ {
+		area := model.TotalArea2022 * math.Pow(1+annualGrowth/100, float64(year-2022))
+		extension = append(extension, plotter.XY{X: float64(year), Y: area / 1000})
+	}
+	projection, err := plotter.NewLine(extension)
+	if err != nil {
+		return nil, err
+	}
+	projection.Color = chartTheme.Series[1]
+	projection.Width = vg.Points(1.5)
+	projection.Dashes = []vg.Length{vg.Points(4), vg.Points(2)}
+
+	peak, err := plotter.NewScatter(plotter.XYs{{X: float64(model.PeakYear), Y: model.PeakArea / 1000}})
+	if err != nil {
+		return nil, err
+	}
+	peak.GlyphStyle.Color = chartTheme.High
+	peak.GlyphStyle.Shape = draw.TriangleGlyph{}
+	peak.GlyphStyle.Radius = vg.Points(4)
+
+	p.Add(plotter.NewGrid(), trend, line, projection)
+	if model.PeakArea > 0 {
+		p.Add(peak)
+	}
+
+	if legend {
+		p.Legend.Add(tr("Aktual"), line)
+		p.Legend.Add(tr("Tren linear"), trend)
+		p.Legend.Add(trf("Proyeksi %d", panelEndYear), projection)
+		p.Legend.Add(tr("Puncak"), peak)
+		p.Legend.Top = true
+		p.Legend.Left = true
+		p.Legend.TextStyle.Font.Size = vg.Points(7)
+		p.Legend.ThumbnailWidth = vg.Points(14)
+	}
+
+	p.X.Min = 2002
+	p.X.Max = panelEndYear + 1
+	p.Y.Min = 0
+	p.Y.Max = trajectoryMax(model) * 1.05
+	if p.Y.Max == 0 {
+		p.Y.Max = 1
+	}
+	return p, nil
+}
+
+// trajectoryMax adalah nilai tertinggi (ribu ha) yang digambar di panel:
+// data, proyeksi 2030 atau ujung garis tren.
+func trajectoryMax(model ProvinceModel) float64 {
+	slope, intercept := linearTrend(model.YearlyData)
+	highest := math.Max(model.PeakArea, intercept+slope*2022)
+	highest = math.Max(highest, model.TotalArea2022*math.Pow(1+baselineAnnualGrowth(model)/100, panelEndYear-2022))
+	return highest / 1000
+}
+
+// linearTrend menghitung regresi kuadrat terkecil area terhadap tahun.
+func linearTrend(yearlyData map[int]float64) (slope, intercept float64) {
+	n := float64(len(yearlyData))
+	if n < 2 {
+		for _, area := range yearlyData {
+			return 0, area
+		}
+		return 0, 0
+	}
+
+	sumX, sumY, sumXY, sumXX := 0.0, 0.0, 0.0, 0.0
+	for _, year := range getSortedYears(yearlyData) {
+		x, area := float64(year), yearlyData[year]
+		sumX += x
+		sumY += area
+		sumXY += x * area
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, sumY / n
+	}
+	slope = (n*sumXY - sumX*sumY) / denominator
+	intercept = (sumY - slope*sumX) / n
+	return slope, intercept
+}
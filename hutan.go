@@ -0,0 +1,378 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Data kehilangan hutan opsional per kabupaten per tahun. Kolom wajib
+// region_trase_id, year dan forest_loss_ha; primary_forest_ha (luas hutan
+// primer tersisa) opsional. Ekspansi tahunan adalah kenaikan area tertanam
+// dari tahun sebelumnya; area yang bertepatan dengan kehilangan hutan adalah
+// min(ekspansi, kehilangan hutan) pada kabupaten dan tahun yang sama, sehingga
+// merupakan batas atas konversi hutan, bukan bukti konversi.
+const forestLossFile = "kehilangan_hutan.csv"
+
+// Ambang risiko deforestasi: pangsa ekspansi yang bertepatan dengan
+// kehilangan hutan (%) dan luas minimum yang bertepatan (ha) agar kabupaten
+// kecil tidak ikut ditandai.
+const (
+	deforestationHighShare   = 50.0
+	deforestationMediumShare = 25.0
+	deforestationMinArea     = 1000.0
+)
+
+type ForestRecord struct {
+	Loss          float64
+	PrimaryForest float64
+}
+
+// DeforestationStats berlaku untuk kabupaten maupun provinsi. Correlation
+// adalah korelasi Pearson ekspansi tahunan dengan kehilangan hutan tahunan.
+type DeforestationStats struct {
+	Expansion       float64
+	ForestLoss      float64
+	Coinciding      float64
+	CoincidingShare float64
+	PrimaryForest   float64
+	Correlation     float64
+	Risk            string
+}
+
+type RegencyDeforestation struct {
+	RegionID string
+	Regency  string
+	Province string
+	DeforestationStats
+}
+
+type ProvinceDeforestation struct {
+	DeforestationStats
+	FlaggedRegencies int
+}
+
+// DeforestationAnalysis juga memuat korelasi gabungan semua pasangan
+// kabupaten-tahun (Pearson dan Spearman) dan total nasional.
+type DeforestationAnalysis struct {
+	Regencies    []RegencyDeforestation
+	Provinces    map[string]*ProvinceDeforestation
+	National     DeforestationStats
+	Pearson      float64
+	Spearman     float64
+	Observations int
+	FirstYear    int
+	LastYear     int
+}
+
+// loadForestLoss mengembalikan nil tanpa error bila file tidak ada.
+func loadForestLoss() (map[string]map[int]ForestRecord, error) {
+	records, err := readOptionalCSV(forestLossFile)
+	if err != nil || records == nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%s tidak memiliki baris data", forestLossFile)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"region_trase_id", "year", "forest_loss_ha"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("kolom %q tidak ada di header %s", required, forestLossFile)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	forest := make(map[string]map[int]ForestRecord)
+	for line, record := range records[1:] {
+		year, err := strconv.Atoi(field(record, "year"))
+		if err != nil {
+			return nil, fmt.Errorf("%s baris %d: tahun tidak valid %q", forestLossFile, line+2, field(record, "year"))
+		}
+		loss, err := strconv.ParseFloat(field(record, "forest_loss_ha"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s baris %d: forest_loss_ha tidak valid %q", forestLossFile, line+2, field(record, "forest_loss_ha"))
+		}
+		primary, _ := strconv.ParseFloat(field(record, "primary_forest_ha"), 64)
+
+		id := field(record, "region_trase_id")
+		if forest[id] == nil {
+			forest[id] = make(map[int]ForestRecord)
+		}
+		forest[id][year] = ForestRecord{Loss: loss, PrimaryForest: primary}
+	}
+
+	fmt.Fprintf(progressOutput, "🌳 Data kehilangan hutan dibaca: %d kabupaten dari %s\n", len(forest), forestLossFile)
+	return forest, nil
+}
+
+// analyzeDeforestation menggabungkan ekspansi tahunan kabupaten dari kubus
+// dengan kehilangan hutan; nil bila file tidak ada atau metrik utama bukan
+// luas.
+func analyzeDeforestation(cube *DataCube, forest map[string]map[int]ForestRecord) (*DeforestationAnalysis, error) {
+	if forest == nil {
+		fmt.Fprintf(progressOutput, "🌳 Analisis deforestasi dilewati: %s tidak ditemukan\n", forestLossFile)
+		return nil, nil
+	}
+	if metricUnit() != defaultMetric.Unit {
+		fmt.Fprintf(progressOutput, "⚠️  Analisis deforestasi dilewati: metrik utama %s bukan luas (%s)\n", primaryMetric().Name, defaultMetric.Unit)
+		return nil, nil
+	}
+
+	series, err := cube.Series(LevelRegency, CubeFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &DeforestationAnalysis{Provinces: make(map[string]*ProvinceDeforestation)}
+	var pooledExpansion, pooledLoss []float64
+	provinceExpansion := make(map[string]map[int]float64)
+	provinceLoss := make(map[string]map[int]float64)
+	for _, regency := range series {
+		years, ok := forest[regency.Key]
+		if !ok {
+			continue
+		}
+
+		var expansions, losses []float64
+		var stats DeforestationStats
+		latestForestYear := 0
+		for _, year := range getSortedYears(regency.Values) {
+			record, hasForest := years[year]
+			previous, hasPrevious := regency.Values[year-1]
+			if !hasForest || !hasPrevious {
+				continue
+			}
+			expansion := math.Max(regency.Values[year]-previous, 0)
+			stats.Expansion += expansion
+			stats.ForestLoss += record.Loss
+			stats.Coinciding += math.Min(expansion, record.Loss)
+			if year > latestForestYear {
+				latestForestYear = year
+				stats.PrimaryForest = record.PrimaryForest
+			}
+			expansions = append(expansions, expansion)
+			losses = append(losses, record.Loss)
+
+			if provinceExpansion[regency.Province] == nil {
+				provinceExpansion[regency.Province] = make(map[int]float64)
+				provinceLoss[regency.Province] = make(map[int]float64)
+			}
+			provinceExpansion[regency.Province][year] += expansion
+			provinceLoss[regency.Province][year] += record.Loss
+			if analysis.FirstYear == 0 || year < analysis.FirstYear {
+				analysis.FirstYear = year
+			}
+			analysis.LastYear = max(analysis.LastYear, year)
+		}
+		if len(expansions) == 0 {
+			continue
+		}
+		stats.Correlation = pearsonCorrelation(expansions, losses)
+		classifyDeforestation(&stats)
+		pooledExpansion = append(pooledExpansion, expansions...)
+		pooledLoss = append(pooledLoss, losses...)
+
+		analysis.Regencies = append(analysis.Regencies, RegencyDeforestation{
+			RegionID:           regency.Key,
+			Regency:            regency.Name,
+			Province:           regency.Province,
+			DeforestationStats: stats,
+		})
+
+		province := analysis.Provinces[regency.Province]
+		if province == nil {
+			province = &ProvinceDeforestation{}
+			analysis.Provinces[regency.Province] = province
+		}
+		province.Expansion += stats.Expansion
+		province.ForestLoss += stats.ForestLoss
+		province.Coinciding += stats.Coinciding
+		province.PrimaryForest += stats.PrimaryForest
+		if stats.Risk == "HIGH" {
+			province.FlaggedRegencies++
+		}
+		analysis.National.Expansion += stats.Expansion
+		analysis.National.ForestLoss += stats.ForestLoss
+		analysis.National.Coinciding += stats.Coinciding
+		analysis.National.PrimaryForest += stats.PrimaryForest
+	}
+	if len(analysis.Regencies) == 0 {
+		fmt.Fprintf(progressOutput, "🌳 Analisis deforestasi dilewati: tidak ada kabupaten di %s yang cocok dengan data area\n", forestLossFile)
+		return nil, nil
+	}
+
+	for name, province := range analysis.Provinces {
+		var expansions, losses []float64
+		for _, year := range getSortedYears(provinceExpansion[name]) {
+			expansions = append(expansions, provinceExpansion[name][year])
+			losses = append(losses, provinceLoss[name][year])
+		}
+		province.Correlation = pearsonCorrelation(expansions, losses)
+		classifyDeforestation(&province.DeforestationStats)
+	}
+	classifyDeforestation(&analysis.National)
+	analysis.Pearson = pearsonCorrelation(pooledExpansion, pooledLoss)
+	analysis.Spearman = spearmanCorrelation(pooledExpansion, pooledLoss)
+	analysis.Observations = len(pooledExpansion)
+
+	sort.Slice(analysis.Regencies, func(i, j int) bool {
+		if analysis.Regencies[i].Coinciding != analysis.Regencies[j].Coinciding {
+			return analysis.Regencies[i].Coinciding > analysis.Regencies[j].Coinciding
+		}
+		return analysis.Regencies[i].RegionID < analysis.Regencies[j].RegionID
+	})
+
+	fmt.Fprintf(progressOutput, "🌳 Ekspansi vs kehilangan hutan dihitung: %d kabupaten, %d provinsi\n", len(analysis.Regencies), len(analysis.Provinces))
+	return analysis, nil
+}
+
+func classifyDeforestation(stats *DeforestationStats) {
+	if stats.Expansion > 0 {
+		stats.CoincidingShare = stats.Coinciding / stats.Expansion * 100
+	}
+	switch {
+	case stats.Coinciding < deforestationMinArea:
+		stats.Risk = "LOW"
+	case stats.CoincidingShare >= deforestationHighShare:
+		stats.Risk = "HIGH"
+	case stats.CoincidingShare >= deforestationMediumShare:
+		stats.Risk = "MEDIUM"
+	default:
+		stats.Risk = "LOW"
+	}
+}
+
+// province mengembalikan indikator provinsi atau nil bila tidak ada data.
+func (a *DeforestationAnalysis) province(name string) *ProvinceDeforestation {
+	if a == nil {
+		return nil
+	}
+	return a.Provinces[name]
+}
+
+func (a *DeforestationAnalysis) flaggedRegencies() []RegencyDeforestation {
+	var flagged []RegencyDeforestation
+	for _, regency := range a.Regencies {
+		if regency.Risk == "HIGH" {
+			flagged = append(flagged, regency)
+		}
+	}
+	return flagged
+}
+
+// sortedProvinces mengurutkan provinsi berdasarkan area yang bertepatan
+// dengan kehilangan hutan.
+func (a *DeforestationAnalysis) sortedProvinces() []string {
+	var names []string
+	for name := range a.Provinces {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if a.Provinces[names[i]].Coinciding != a.Provinces[names[j]].Coinciding {
+			return a.Provinces[names[i]].Coinciding > a.Provinces[names[j]].Coinciding
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func writeDeforestationSheets(f *excelize.File, analysis *DeforestationAnalysis) {
+	if analysis == nil {
+		return
+	}
+
+	sheet := tr("Deforestasi_Provinsi")
+	f.NewSheet(sheet)
+	headers := []string{"Provinsi", "Ekspansi (ha)", "Kehilangan Hutan (ha)", "Bertepatan (ha)", "Pangsa Ekspansi Bertepatan (%)",
+		"Hutan Primer Tersisa (ha)", "Korelasi Ekspansi-Kehilangan", "Kabupaten Ditandai", "Risiko Deforestasi"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+	}
+	f.SetColWidth(sheet, "A", "A", 24)
+	f.SetColWidth(sheet, "B", "I", 20)
+	for i, name := range analysis.sortedProvinces() {
+		row := i + 2
+		province := analysis.Provinces[name]
+		values := []interface{}{name, math.Round(province.Expansion), math.Round(province.ForestLoss), math.Round(province.Coinciding),
+			math.Round(province.CoincidingShare*10) / 10, math.Round(province.PrimaryForest), math.Round(province.Correlation*1000) / 1000,
+			province.FlaggedRegencies, tr(province.Risk)}
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, row)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+
+	sheet = tr("Deforestasi_Kabupaten")
+	f.NewSheet(sheet)
+	headers = []string{"Region ID", "Kabupaten", "Provinsi", "Ekspansi (ha)", "Kehilangan Hutan (ha)", "Bertepatan (ha)",
+		"Pangsa Ekspansi Bertepatan (%)", "Hutan Primer Tersisa (ha)", "Korelasi Ekspansi-Kehilangan", "Risiko Deforestasi"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+	}
+	f.SetColWidth(sheet, "A", "C", 22)
+	f.SetColWidth(sheet, "D", "J", 20)
+	for i, regency := range analysis.Regencies {
+		row := i + 2
+		values := []interface{}{regency.RegionID, regency.Regency, regency.Province, math.Round(regency.Expansion), math.Round(regency.ForestLoss),
+			math.Round(regency.Coinciding), math.Round(regency.CoincidingShare*10) / 10, math.Round(regency.PrimaryForest),
+			math.Round(regency.Correlation*1000) / 1000, tr(regency.Risk)}
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, row)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+}
+
+func buildDeforestationReport(analysis *DeforestationAnalysis) string {
+	if analysis == nil {
+		return ""
+	}
+
+	report := tr("\n### 🌳 EKSPANSI DAN KEHILANGAN HUTAN\n\n")
+	report += trf("Dari %s ha ekspansi kelapa sawit di %d kabupaten (%d-%d), %s ha (%.1f%%) terjadi pada kabupaten dan tahun yang sama dengan kehilangan hutan. Angka ini adalah batas atas konversi hutan, bukan bukti konversi. Korelasi ekspansi dengan kehilangan hutan per kabupaten-tahun: Pearson %.2f, Spearman %.2f (n = %d).\n\n",
+		formatNumber(analysis.National.Expansion), len(analysis.Regencies), analysis.FirstYear, analysis.LastYear,
+		formatNumber(analysis.National.Coinciding), analysis.National.CoincidingShare, analysis.Pearson, analysis.Spearman, analysis.Observations)
+	report += trf("Risiko deforestasi TINGGI bila ≥ %.0f%% ekspansi bertepatan dengan kehilangan hutan dan luasnya ≥ %.0f ha, SEDANG mulai %.0f%%. Risiko TINGGI pada provinsi langsung menaikkan tingkat risikonya menjadi TINGGI.\n\n",
+		deforestationHighShare, deforestationMinArea, deforestationMediumShare)
+
+	report += tr("| Provinsi | Ekspansi (ha) | Bertepatan (ha) | Pangsa Bertepatan | Korelasi | Kabupaten Ditandai | Risiko |\n")
+	report += "|----------|---------------|-----------------|-------------------|----------|--------------------|--------|\n"
+	for _, name := range analysis.sortedProvinces() {
+		province := analysis.Provinces[name]
+		report += fmt.Sprintf("| %s | %s | %s | %.1f%% | %.2f | %d | %s |\n", name, formatNumber(province.Expansion),
+			formatNumber(province.Coinciding), province.CoincidingShare, province.Correlation, province.FlaggedRegencies, tr(province.Risk))
+	}
+
+	flagged := analysis.flaggedRegencies()
+	if len(flagged) == 0 {
+		report += tr("\nTidak ada kabupaten yang ditandai berisiko deforestasi tinggi.\n")
+		return report
+	}
+	report += trf("\n**⚠️ Kabupaten ditandai berisiko deforestasi tinggi (%d):**\n\n", len(flagged))
+	report += tr("| Kabupaten | Provinsi | Ekspansi (ha) | Kehilangan Hutan (ha) | Bertepatan (ha) | Pangsa Bertepatan | Hutan Primer Tersisa (ha) |\n")
+	report += "|-----------|----------|---------------|-----------------------|-----------------|-------------------|---------------------------|\n"
+	for _, regency := range flagged {
+		primary := "-"
+		if regency.PrimaryForest > 0 {
+			primary = formatNumber(regency.PrimaryForest)
+		}
+		report += fmt.Sprintf("| %s | %s | %s | %s | %s | %.1f%% | %s |\n", regency.Regency, regency.Province, formatNumber(regency.Expansion),
+			formatNumber(regency.ForestLoss), formatNumber(regency.Coinciding), regency.CoincidingShare, primary)
+	}
+	return report
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClassifyDeforestation(t *testing.T) {
+	tests := []struct {
+		name       string
+		expansion  float64
+		coinciding float64
+		wantRisk   string
+	}{
+		{"tepat ambang tinggi", 2000, 1000, "HIGH"},
+		{"tepat ambang sedang", 4000, 1000, "MEDIUM"},
+		{"di bawah ambang sedang", 4001, 1000, "LOW"},
+		// Pangsa 99,9% tetapi luasnya di bawah deforestationMinArea.
+		{"area kecil", 1000, 999, "LOW"},
+		{"tanpa ekspansi", 0, 0, "LOW"},
+	}
+	for _, test := range tests {
+		stats := DeforestationStats{Expansion: test.expansion, Coinciding: test.coinciding}
+		classifyDeforestation(&stats)
+		if stats.Risk != test.wantRisk {
+			t.Errorf("%s: risiko %s (pangsa %.2f%%), seharusnya %s", test.name, stats.Risk, stats.CoincidingShare, test.wantRisk)
+		}
+	}
+}
+
+// deforestationTestData: dua kabupaten di KALIMANTAN BARAT, dua di RIAU;
+// ID-1402 tidak memiliki data kehilangan hutan.
+func deforestationTestData() ([]RawPalmOilData, map[string]map[int]ForestRecord) {
+	area := map[string][3]float64{
+		"ID-6101": {1000, 4000, 6000},
+		"ID-6102": {10000, 12000, 11000},
+		"ID-1401": {1000, 5000, 9000},
+		"ID-1402": {500, 900, 1500},
+	}
+	provinces := map[string]string{"ID-6101": "KALIMANTAN BARAT", "ID-6102": "KALIMANTAN BARAT", "ID-1401": "RIAU", "ID-1402": "RIAU"}
+	var data []RawPalmOilData
+	for id, values := range area {
+		for i, value := range values {
+			data = append(data, RawPalmOilData{Year: 2020 + i, Region: "KAB " + id, RegionID: id, ParentRegion: provinces[id], Values: []float64{value}})
+		}
+	}
+	forest := map[string]map[int]ForestRecord{
+		// Kehilangan 2020 tidak dihitung karena tidak ada area 2019.
+		"ID-6101": {2020: {Loss: 9999}, 2021: {Loss: 2500}, 2022: {Loss: 3000, PrimaryForest: 700}},
+		// 2022 menyusut, jadi ekspansinya 0 walaupun kehilangan hutan besar.
+		"ID-6102": {2021: {Loss: 600}, 2022: {Loss: 5000}},
+		"ID-1401": {2021: {Loss: 1200}, 2022: {Loss: 1000}},
+	}
+	return data, forest
+}
+
+func TestAnalyzeDeforestation(t *testing.T) {
+	data, forest := deforestationTestData()
+	analysis, err := analyzeDeforestation(newDataCube(data, 0), forest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analysis.Regencies) != 3 {
+		t.Fatalf("%d kabupaten dianalisis, seharusnya 3", len(analysis.Regencies))
+	}
+	if analysis.FirstYear != 2021 || analysis.LastYear != 2022 || analysis.Observations != 6 {
+		t.Errorf("periode %d-%d dengan %d observasi, seharusnya 2021-2022 dengan 6", analysis.FirstYear, analysis.LastYear, analysis.Observations)
+	}
+
+	regencies := make(map[string]RegencyDeforestation)
+	for _, regency := range analysis.Regencies {
+		regencies[regency.RegionID] = regency
+	}
+	tests := []struct {
+		name                  string
+		got                   DeforestationStats
+		expansion, coinciding float64
+		risk                  string
+	}{
+		// min(3000, 2500) + min(2000, 3000) = 4500 dari ekspansi 5000.
+		{"ID-6101", regencies["ID-6101"].DeforestationStats, 5000, 4500, "HIGH"},
+		// min(2000, 600) + min(0, 5000) = 600, di bawah luas minimum.
+		{"ID-6102", regencies["ID-6102"].DeforestationStats, 2000, 600, "LOW"},
+		// min(4000, 1200) + min(4000, 1000) = 2200 dari 8000 (27,5%).
+		{"ID-1401", regencies["ID-1401"].DeforestationStats, 8000, 2200, "MEDIUM"},
+		{"KALIMANTAN BARAT", analysis.Provinces["KALIMANTAN BARAT"].DeforestationStats, 7000, 5100, "HIGH"},
+		{"RIAU", analysis.Provinces["RIAU"].DeforestationStats, 8000, 2200, "MEDIUM"},
+		{"nasional", analysis.National, 15000, 7300, "MEDIUM"},
+	}
+	for _, test := range tests {
+		if math.Abs(test.got.Expansion-test.expansion) > 1e-9 || math.Abs(test.got.Coinciding-test.coinciding) > 1e-9 {
+			t.Errorf("%s: ekspansi %v, bertepatan %v, seharusnya %v dan %v", test.name, test.got.Expansion, test.got.Coinciding, test.expansion, test.coinciding)
+		}
+		if test.got.Risk != test.risk {
+			t.Errorf("%s: risiko %s, seharusnya %s", test.name, test.got.Risk, test.risk)
+		}
+	}
+	if got := regencies["ID-6101"].PrimaryForest; got != 700 {
+		t.Errorf("hutan primer ID-6101 = %v, seharusnya 700 (tahun terakhir)", got)
+	}
+	if got := analysis.Provinces["KALIMANTAN BARAT"].FlaggedRegencies; got != 1 {
+		t.Errorf("kabupaten berisiko tinggi di KALIMANTAN BARAT = %d, seharusnya 1", got)
+	}
+	if analysis.Regencies[0].RegionID != "ID-6101" {
+		t.Errorf("kabupaten pertama %s, seharusnya ID-6101 (area bertepatan terbesar)", analysis.Regencies[0].RegionID)
+	}
+
+	// Risiko deforestasi provinsi HIGH menaikkan tingkat risiko investasi;
+	// MEDIUM tidak.
+	model := ProvinceModel{GrowthRate20Years: 100, StabilityIndex: 8, MarketShare2022: 5}
+	for _, test := range []struct {
+		province string
+		want     string
+	}{
+		{"", "LOW-MEDIUM"},
+		{"RIAU", "LOW-MEDIUM"},
+		{"KALIMANTAN BARAT", "HIGH"},
+	} {
+		model.Deforestation = analysis.province(test.province)
+		if got := assessRiskLevel(model); got != test.want {
+			t.Errorf("risiko provinsi %q = %s, seharusnya %s", test.province, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Teks keluaran (sheet dan header Excel, judul dan sumbu grafik, laporan,
+// rekomendasi, deskripsi fase) ditulis sekali di kode lalu diterjemahkan
+// lewat tr(). Kunci katalog adalah teks sumber; teks yang tidak ada di
+// katalog bahasa aktif ditampilkan apa adanya. Kode kategori (VERY HIGH,
+// PRIME, EXPLOSIVE_GROWTH, ...) tetap disimpan di model dan baru
+// diterjemahkan saat ditampilkan.
+const defaultLanguage = "id"
+
+//go:embed pesan/*.json
+var messageCatalogFiles embed.FS
+
+var (
+	outputLanguage = defaultLanguage
+	messageCatalog = map[string]string{}
+)
+
+func setOutputLanguage(lang string) {
+	content, err := messageCatalogFiles.ReadFile("pesan/" + lang + ".json")
+	if err != nil {
+		log.Fatalf("Bahasa tidak didukung: %q (gunakan id atau en)", lang)
+	}
+
+	catalog := make(map[string]string)
+	if err := json.Unmarshal(content, &catalog); err != nil {
+		log.Fatalf("Error parsing katalog pesan %s: %v", lang, err)
+	}
+
+	outputLanguage = lang
+	messageCatalog = catalog
+}
+
+// tr juga mengisi placeholder label dan satuan metrik utama (lihat
+// metricText).
+func tr(text string) string {
+	if translated, ok := messageCatalog[text]; ok {
+		return metricText(translated)
+	}
+	return metricText(text)
+}
+
+func trf(format string, args ...interface{}) string {
+	return fmt.Sprintf(tr(format), args...)
+}
+
+// trList menerjemahkan daftar kode yang digabung dengan sep, mis. "PRIME,GROWTH".
+func trList(codes, sep string) string {
+	if codes == "" {
+		return ""
+	}
+	parts := strings.Split(codes, sep)
+	for i, code := range parts {
+		parts[i] = tr(code)
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+type ConcentrationMetrics struct {
+	Year   int
+	Level  string
+	Units  int
+	HHI    float64
+	Gini   float64
+	CR4    float64
+	CR8    float64
+	Theil  float64
+	Lorenz []LorenzPoint
+}
+
+type LorenzPoint struct {
+	PopulationShare float64
+	AreaShare       float64
+}
+
+func analyzeMarketConcentration(cube *DataCube) []ConcentrationMetrics {
+	var metrics []ConcentrationMetrics
+	for year := 2003; year <= 2022; year++ {
+		regencyAreas := cube.RegencyAreas(year)
+		if len(regencyAreas) == 0 {
+			continue
+		}
+		metrics = append(metrics, calculateConcentration(year, "PROVINSI", mapValues(cube.ProvinceAreas(year))))
+		metrics = append(metrics, calculateConcentration(year, "KABUPATEN", mapValues(regencyAreas)))
+	}
+
+	fmt.Fprintf(progressOutput, "🏭 Konsentrasi pasar dihitung: %d tahun x 2 level\n", len(metrics)/2)
+	return metrics
+}
+
+func calculateConcentration(year int, level string, areas []float64) ConcentrationMetrics {
+	metrics := ConcentrationMetrics{
+		Year:  year,
+		Level: level,
+		Units: len(areas),
+	}
+
+	total := 0.0
+	for _, area := range areas {
+		total += area
+	}
+	if total <= 0 || len(areas) == 0 {
+		return metrics
+	}
+
+	sorted := make([]float64, len(areas))
+	copy(sorted, areas)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+
+	mean := total / float64(len(sorted))
+	for i, area := range sorted {
+		share := area / total
+		metrics.HHI += math.Pow(share*100, 2)
+		if i < 4 {
+			metrics.CR4 += share * 100
+		}
+		if i < 8 {
+			metrics.CR8 += share * 100
+		}
+		if area > 0 {
+			ratio := area / mean
+			metrics.Theil += ratio * math.Log(ratio)
+		}
+	}
+	metrics.Theil /= float64(len(sorted))
+
+	metrics.Lorenz = buildLorenzCurve(sorted, total)
+	metrics.Gini = calculateGiniFromLorenz(metrics.Lorenz)
+
+	return metrics
+}
+
+func buildLorenzCurve(areas []float64, total float64) []LorenzPoint {
+	ascending := make([]float64, len(areas))
+	copy(ascending, areas)
+	sort.Float64s(ascending)
+
+	curve := []LorenzPoint{{PopulationShare: 0, AreaShare: 0}}
+	cumulative := 0.0
+	for i, area := range ascending {
+		cumulative += area
+		curve = append(curve, LorenzPoint{
+			PopulationShare: float64(i+1) / float64(len(ascending)),
+			AreaShare:       cumulative / total,
+		})
+	}
+	return curve
+}
+
+func calculateGiniFromLorenz(curve []LorenzPoint) float64 {
+	area := 0.0
+	for i := 1; i < len(curve); i++ {
+		width := curve[i].PopulationShare - curve[i-1].PopulationShare
+		area += width * (curve[i].AreaShare + curve[i-1].AreaShare) / 2
+	}
+	return 1 - 2*area
+}
+
+func findConcentration(metrics []ConcentrationMetrics, year int, level string) *ConcentrationMetrics {
+	for i := range metrics {
+		if metrics[i].Year == year && metrics[i].Level == level {
+			return &metrics[i]
+		}
+	}
+	return nil
+}
+
+func describeConcentrationShift(start, end *ConcentrationMetrics) string {
+	if start == nil || end == nil {
+		return tr("tidak dapat ditentukan")
+	}
+	hhiChange := end.HHI - start.HHI
+	giniChange := end.Gini - start.Gini
+
+	if hhiChange < 0 && giniChange < 0 {
+		return tr("lebih tersebar (kurang terkonsentrasi)")
+	} else if hhiChange > 0 && giniChange > 0 {
+		return tr("lebih terkonsentrasi")
+	}
+	return tr("campuran (HHI dan Gini bergerak berlawanan arah)")
+}
+
+func classifyHHI(hhi float64) string {
+	if hhi >= 2500 {
+		return tr("sangat terkonsentrasi")
+	} else if hhi >= 1500 {
+		return tr("cukup terkonsentrasi")
+	}
+	return tr("tidak terkonsentrasi")
+}
+
+func writeConcentrationSheet(f *excelize.File, metrics []ConcentrationMetrics) {
+	sheet := tr("Konsentrasi_Pasar")
+	f.NewSheet(sheet)
+
+	headers := []string{"Tahun", "Level", "Jumlah Unit", "HHI", "Gini", "CR4 (%)", "CR8 (%)", "Indeks Theil"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 16)
+	}
+
+	for i, m := range metrics {
+		row := i + 2
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), m.Year)
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), tr(m.Level))
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), m.Units)
+		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), math.Round(m.HHI))
+		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), fmt.Sprintf("%.3f", m.Gini))
+		f.SetCellValue(sheet, fmt.Sprintf("F%d", row), fmt.Sprintf("%.1f%%", m.CR4))
+		f.SetCellValue(sheet, fmt.Sprintf("G%d", row), fmt.Sprintf("%.1f%%", m.CR8))
+		f.SetCellValue(sheet, fmt.Sprintf("H%d", row), fmt.Sprintf("%.3f", m.Theil))
+	}
+}
+
+func createLorenzChart(metrics []ConcentrationMetrics) error {
+	p := plot.New()
+	p.Title.Text = tr("KURVA LORENZ {{METRIC}} KELAPA SAWIT 2003 vs 2022")
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Proporsi Kumulatif Wilayah")
+	p.Y.Label.Text = tr("Proporsi Kumulatif {{Metric}}")
+	p.X.Min, p.X.Max = 0, 1
+	p.Y.Min, p.Y.Max = 0, 1
+
+	equality := plotter.NewFunction(func(x float64) float64 { return x })
+	equality.Color = color.RGBA{R: 0, G: 0, B: 0, A: 255}
+	equality.Dashes = []vg.Length{vg.Points(5), vg.Points(5)}
+	p.Add(equality)
+	p.Legend.Add(tr("Pemerataan sempurna"), equality)
+
+	series := []struct {
+		year   int
+		level  string
+		color  color.RGBA
+		dashed bool
+	}{
+		{2003, "PROVINSI", chartTheme.Before, false},
+		{2022, "PROVINSI", chartTheme.After, false},
+		{2003, "KABUPATEN", chartTheme.Before, true},
+		{2022, "KABUPATEN", chartTheme.After, true},
+	}
+
+	for _, s := range series {
+		m := findConcentration(metrics, s.year, s.level)
+		if m == nil {
+			continue
+		}
+
+		points := make(plotter.XYs, len(m.Lorenz))
+		for i, point := range m.Lorenz {
+			points[i].X = point.PopulationShare
+			points[i].Y = point.AreaShare
+		}
+
+		line, err := plotter.NewLine(points)
+		if err != nil {
+			return err
+		}
+		line.Color = s.color
+		line.Width = vg.Points(2)
+		if s.dashed {
+			line.Dashes = []vg.Length{vg.Points(3), vg.Points(3)}
+		}
+
+		p.Add(line)
+		p.Legend.Add(trf("%s %d (Gini %.2f)", tr(s.level), s.year, m.Gini), line)
+	}
+
+	p.Legend.Top = true
+	p.Legend.Left = true
+	p.Add(plotter.NewGrid())
+
+	return saveChart(p, 12*vg.Inch, 12*vg.Inch, "kurva_lorenz_2003_vs_2022")
+}
+
+func buildConcentrationReport(metrics []ConcentrationMetrics) string {
+	report := tr("\n### 🏭 KONSENTRASI PASAR 2003-2022\n\n")
+	report += tr("| Level | HHI 2003 | HHI 2022 | Gini 2003 | Gini 2022 | CR4 2003 | CR4 2022 | Theil 2003 | Theil 2022 |\n")
+	report += "|-------|----------|----------|-----------|-----------|----------|----------|------------|------------|\n"
+
+	for _, level := range []string{"PROVINSI", "KABUPATEN"} {
+		start := findConcentration(metrics, 2003, level)
+		end := findConcentration(metrics, 2022, level)
+		if start == nil || end == nil {
+			continue
+		}
+		report += fmt.Sprintf("| %s | %.0f | %.0f | %.3f | %.3f | %.1f%% | %.1f%% | %.3f | %.3f |\n",
+			tr(level), start.HHI, end.HHI, start.Gini, end.Gini, start.CR4, end.CR4, start.Theil, end.Theil)
+	}
+
+	provinceStart := findConcentration(metrics, 2003, "PROVINSI")
+	provinceEnd := findConcentration(metrics, 2022, "PROVINSI")
+	regencyStart := findConcentration(metrics, 2003, "KABUPATEN")
+	regencyEnd := findConcentration(metrics, 2022, "KABUPATEN")
+
+	if provinceStart != nil && provinceEnd != nil {
+		report += trf("\nDi tingkat provinsi, industri menjadi **%s** selama 2003-2022: HHI bergerak dari %.0f ke %.0f (%s) "+
+			"dan Gini dari %.3f ke %.3f. Pangsa empat provinsi terbesar (CR4) berubah dari %.1f%% menjadi %.1f%%.",
+			describeConcentrationShift(provinceStart, provinceEnd),
+			provinceStart.HHI, provinceEnd.HHI, classifyHHI(provinceEnd.HHI),
+			provinceStart.Gini, provinceEnd.Gini,
+			provinceStart.CR4, provinceEnd.CR4)
+	}
+	if regencyStart != nil && regencyEnd != nil {
+		report += trf(" Di tingkat kabupaten, distribusi {{metric}} menjadi **%s** (Gini %.3f → %.3f, Theil %.3f → %.3f).\n",
+			describeConcentrationShift(regencyStart, regencyEnd),
+			regencyStart.Gini, regencyEnd.Gini, regencyStart.Theil, regencyEnd.Theil)
+	}
+
+	return report
+}
+
+func mapValues(values map[string]float64) []float64 {
+	result := make([]float64, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+	return result
+}
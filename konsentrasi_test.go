@@ -0,0 +1,59 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateConcentration(t *testing.T) {
+	tests := []struct {
+		name                       string
+		areas                      []float64
+		hhi, gini, theil, cr4, cr8 float64
+	}{
+		{"merata", []float64{10, 10, 10, 10}, 2500, 0, 0, 100, 100},
+		// Satu unit menguasai semua area: Gini (n-1)/n dan Theil ln(n).
+		{"monopoli", []float64{0, 0, 0, 10}, 10000, 0.75, math.Log(4), 100, 100},
+		// Gini = Σ|xi-xj| / (2n²μ) = 20 / 80.
+		{"bertingkat", []float64{3, 1, 4, 2}, 3000, 0.25, 0.10644013528622318, 100, 100},
+		// HHI = 10000·Σv² / (Σv)² = 10000·204 / 1296; CR4 = (8+7+6+5) / 36.
+		{"delapan unit", []float64{1, 2, 3, 4, 5, 6, 7, 8}, 2040000.0 / 1296, 0.2916666666666667, 0.14264367061173966, 26.0 / 36 * 100, 100},
+	}
+	for _, test := range tests {
+		got := calculateConcentration(2022, "PROVINSI", test.areas)
+		for _, check := range []struct {
+			metric    string
+			got, want float64
+		}{
+			{"HHI", got.HHI, test.hhi},
+			{"Gini", got.Gini, test.gini},
+			{"Theil", got.Theil, test.theil},
+			{"CR4", got.CR4, test.cr4},
+			{"CR8", got.CR8, test.cr8},
+		} {
+			if math.Abs(check.got-check.want) > 1e-9 {
+				t.Errorf("%s: %s = %.12f, seharusnya %.12f", test.name, check.metric, check.got, check.want)
+			}
+		}
+	}
+}
+
+func TestCalculateConcentrationZeroTotal(t *testing.T) {
+	got := calculateConcentration(2022, "KABUPATEN", []float64{0, 0})
+	if got.Units != 2 || got.HHI != 0 || got.Gini != 0 || got.Theil != 0 || got.Lorenz != nil {
+		t.Errorf("total area nol seharusnya menghasilkan metrik kosong, didapat %+v", got)
+	}
+}
+
+func TestBuildLorenzCurve(t *testing.T) {
+	curve := buildLorenzCurve([]float64{4, 1, 3, 2}, 10)
+	want := []LorenzPoint{{0, 0}, {0.25, 0.1}, {0.5, 0.3}, {0.75, 0.6}, {1, 1}}
+	if len(curve) != len(want) {
+		t.Fatalf("jumlah titik Lorenz = %d, seharusnya %d", len(curve), len(want))
+	}
+	for i := range want {
+		if math.Abs(curve[i].PopulationShare-want[i].PopulationShare) > 1e-12 || math.Abs(curve[i].AreaShare-want[i].AreaShare) > 1e-12 {
+			t.Errorf("titik %d = %+v, seharusnya %+v", i, curve[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// DataCube mengindeks data mentah sekali menjadi matriks tahun × kabupaten
+// (jumlah nilai dan jumlah baris per sel) beserta roll-up tahun × provinsi dan
+// nasional, sehingga analisis tidak perlu memindai ulang semua baris untuk
+// setiap tahun atau provinsi. Kubus dibangun untuk satu metrik; nama "Area"
+// pada method berarti nilai metrik tersebut, dijumlahkan atau dirata-ratakan
+// per baris sesuai aturan agregasinya. Kabupaten diidentifikasi RegionID; nama dan
+// provinsinya diambil dari tahun terakhir yang memuatnya. Baris tanpa
+// provinsi tetap dihitung di tingkat nasional dan kabupaten, seperti
+// sebelumnya.
+type DataCube struct {
+	Years     []int
+	Provinces []string
+	Regencies []CubeRegency
+	Metric    Metric
+
+	yearIndex     map[int]int
+	provinceIndex map[string]int
+	regencyIndex  map[string]int
+
+	// regencyArea[t][r] dan regencyRows[t][r]: area dan jumlah baris
+	// kabupaten r pada Years[t]; provinceArea/provinceRows serupa per
+	// provinsi, national per tahun.
+	regencyArea  [][]float64
+	regencyRows  [][]int
+	provinceArea [][]float64
+	provinceRows [][]int
+	national     []float64
+	nationalRows []int
+
+	provinceRegencies [][]int
+}
+
+type CubeRegency struct {
+	ID       string
+	Name     string
+	Province string
+}
+
+type CubeLevel string
+
+const (
+	LevelNational CubeLevel = "nasional"
+	LevelProvince CubeLevel = "provinsi"
+	LevelRegency  CubeLevel = "kabupaten"
+)
+
+// nationalKey adalah Key satu-satunya seri tingkat nasional.
+const nationalKey = "INDONESIA"
+
+// CubeFilter membatasi sel yang masuk ke seri. Nilai kosong berarti tanpa
+// batas; nama provinsi dicocokkan tanpa membedakan huruf besar/kecil dan
+// Regencies berisi RegionID.
+type CubeFilter struct {
+	FromYear  int
+	ToYear    int
+	Provinces []string
+	Regencies []string
+}
+
+// CubeSeries adalah hasil group-by: area per tahun untuk satu provinsi,
+// kabupaten atau nasional. Values hanya memuat tahun yang memiliki data.
+type CubeSeries struct {
+	Level    CubeLevel
+	Key      string
+	Name     string
+	Province string
+	Values   map[int]float64
+}
+
+// newDataCube membangun kubus untuk metrik activeMetrics[metric].
+func newDataCube(rawData []RawPalmOilData, metric int) *DataCube {
+	cube := &DataCube{
+		Metric:        activeMetrics[metric],
+		yearIndex:     make(map[int]int),
+		provinceIndex: make(map[string]int),
+		regencyIndex:  make(map[string]int),
+	}
+
+	lastYear := make(map[string]int)
+	regencies := make(map[string]CubeRegency)
+	provinces := make(map[string]bool)
+	years := make(map[int]bool)
+	for _, data := range rawData {
+		years[data.Year] = true
+		if data.ParentRegion != "" {
+			provinces[data.ParentRegion] = true
+		}
+		if _, seen := regencies[data.RegionID]; !seen || data.Year >= lastYear[data.RegionID] {
+			regencies[data.RegionID] = CubeRegency{ID: data.RegionID, Name: data.Region, Province: data.ParentRegion}
+			lastYear[data.RegionID] = data.Year
+		}
+	}
+
+	for year := range years {
+		cube.Years = append(cube.Years, year)
+	}
+	slices.Sort(cube.Years)
+	for province := range provinces {
+		cube.Provinces = append(cube.Provinces, province)
+	}
+	slices.Sort(cube.Provinces)
+	for _, regency := range regencies {
+		cube.Regencies = append(cube.Regencies, regency)
+	}
+	slices.SortFunc(cube.Regencies, func(a, b CubeRegency) int { return strings.Compare(a.ID, b.ID) })
+
+	for t, year := range cube.Years {
+		cube.yearIndex[year] = t
+	}
+	for p, province := range cube.Provinces {
+		cube.provinceIndex[province] = p
+	}
+	cube.provinceRegencies = make([][]int, len(cube.Provinces))
+	for r, regency := range cube.Regencies {
+		cube.regencyIndex[regency.ID] = r
+		if p, ok := cube.provinceIndex[regency.Province]; ok {
+			cube.provinceRegencies[p] = append(cube.provinceRegencies[p], r)
+		}
+	}
+
+	cube.regencyArea = newMatrix[float64](len(cube.Years), len(cube.Regencies))
+	cube.regencyRows = newMatrix[int](len(cube.Years), len(cube.Regencies))
+	cube.provinceArea = newMatrix[float64](len(cube.Years), len(cube.Provinces))
+	cube.provinceRows = newMatrix[int](len(cube.Years), len(cube.Provinces))
+	cube.national = make([]float64, len(cube.Years))
+	cube.nationalRows = make([]int, len(cube.Years))
+
+	for _, data := range rawData {
+		t, r := cube.yearIndex[data.Year], cube.regencyIndex[data.RegionID]
+		value := data.Values[metric]
+		cube.regencyArea[t][r] += value
+		cube.regencyRows[t][r]++
+		cube.national[t] += value
+		cube.nationalRows[t]++
+		if p, ok := cube.provinceIndex[data.ParentRegion]; ok {
+			cube.provinceArea[t][p] += value
+			cube.provinceRows[t][p]++
+		}
+	}
+
+	fmt.Fprintf(progressOutput, "🧊 Kubus data dibangun (%s): %d tahun × %d provinsi × %d kabupaten\n",
+		cube.Metric.Name, len(cube.Years), len(cube.Provinces), len(cube.Regencies))
+	return cube
+}
+
+func newMatrix[T any](rows, columns int) [][]T {
+	cells := make([]T, rows*columns)
+	matrix := make([][]T, rows)
+	for i := range matrix {
+		matrix[i] = cells[i*columns : (i+1)*columns]
+	}
+	return matrix
+}
+
+// value menerapkan aturan agregasi metrik pada jumlah nilai sebuah sel.
+func (c *DataCube) value(sum float64, rows int) float64 {
+	if c.Metric.Aggregation == "mean" && rows > 0 {
+		return sum / float64(rows)
+	}
+	return sum
+}
+
+// NationalArea adalah total area nasional pada year (0 bila tidak ada data).
+func (c *DataCube) NationalArea(year int) float64 {
+	if t, ok := c.yearIndex[year]; ok {
+		return c.value(c.national[t], c.nationalRows[t])
+	}
+	return 0
+}
+
+// RegencyArea adalah area kabupaten id pada year (0 bila tidak ada data).
+func (c *DataCube) RegencyArea(id string, year int) float64 {
+	t, okYear := c.yearIndex[year]
+	r, okRegency := c.regencyIndex[id]
+	if !okYear || !okRegency {
+		return 0
+	}
+	return c.value(c.regencyArea[t][r], c.regencyRows[t][r])
+}
+
+// ProvinceAreas mengembalikan area tiap provinsi yang memiliki data pada
+// year, dengan nama provinsi sebagai kunci.
+func (c *DataCube) ProvinceAreas(year int) map[string]float64 {
+	areas := make(map[string]float64)
+	if t, ok := c.yearIndex[year]; ok {
+		for p, province := range c.Provinces {
+			if c.provinceRows[t][p] > 0 {
+				areas[province] = c.value(c.provinceArea[t][p], c.provinceRows[t][p])
+			}
+		}
+	}
+	return areas
+}
+
+// RegencyAreas sama dengan ProvinceAreas per RegionID.
+func (c *DataCube) RegencyAreas(year int) map[string]float64 {
+	areas := make(map[string]float64)
+	if t, ok := c.yearIndex[year]; ok {
+		for r, regency := range c.Regencies {
+			if c.regencyRows[t][r] > 0 {
+				areas[regency.ID] = c.value(c.regencyArea[t][r], c.regencyRows[t][r])
+			}
+		}
+	}
+	return areas
+}
+
+// FindProvince mencari provinsi tanpa membedakan huruf besar/kecil dan
+// mengembalikan ejaannya di data.
+func (c *DataCube) FindProvince(name string) (string, bool) {
+	for _, province := range c.Provinces {
+		if strings.EqualFold(province, strings.TrimSpace(name)) {
+			return province, true
+		}
+	}
+	return "", false
+}
+
+// Series mengelompokkan sel yang lolos filter pada level yang diminta dan
+// meringkas nilai per tahun sesuai aturan agregasi metrik. Tanpa filter
+// kabupaten, level provinsi dan nasional dibaca langsung dari roll-up.
+func (c *DataCube) Series(level CubeLevel, filter CubeFilter) ([]CubeSeries, error) {
+	years := c.filterYears(filter)
+	provinces, err := c.filterProvinces(filter.Provinces)
+	if err != nil {
+		return nil, err
+	}
+	regencies := c.filterRegencies(provinces, filter.Regencies)
+
+	switch level {
+	case LevelRegency:
+		series := make([]CubeSeries, 0, len(regencies))
+		for _, r := range regencies {
+			regency := c.Regencies[r]
+			values := c.collect(years, func(t int) (float64, int) { return c.regencyArea[t][r], c.regencyRows[t][r] })
+			series = append(series, CubeSeries{Level: level, Key: regency.ID, Name: regency.Name, Province: regency.Province, Values: values})
+		}
+		return series, nil
+
+	case LevelProvince:
+		if len(filter.Regencies) > 0 {
+			return c.sumRegencies(level, years, regencies, func(r int) string { return c.Regencies[r].Province }), nil
+		}
+		if provinces == nil {
+			provinces = make([]int, len(c.Provinces))
+			for p := range provinces {
+				provinces[p] = p
+			}
+		}
+		series := make([]CubeSeries, 0, len(provinces))
+		for _, p := range provinces {
+			values := c.collect(years, func(t int) (float64, int) { return c.provinceArea[t][p], c.provinceRows[t][p] })
+			series = append(series, CubeSeries{Level: level, Key: c.Provinces[p], Name: c.Provinces[p], Province: c.Provinces[p], Values: values})
+		}
+		return series, nil
+
+	case LevelNational:
+		if provinces != nil || len(filter.Regencies) > 0 {
+			return c.sumRegencies(level, years, regencies, func(int) string { return nationalKey }), nil
+		}
+		values := c.collect(years, func(t int) (float64, int) { return c.national[t], c.nationalRows[t] })
+		return []CubeSeries{{Level: level, Key: nationalKey, Name: nationalKey, Values: values}}, nil
+	}
+	return nil, fmt.Errorf("level kubus tidak dikenal: %q (gunakan %s, %s atau %s)", level, LevelNational, LevelProvince, LevelRegency)
+}
+
+func (c *DataCube) filterYears(filter CubeFilter) []int {
+	var years []int
+	for t, year := range c.Years {
+		if (filter.FromYear == 0 || year >= filter.FromYear) && (filter.ToYear == 0 || year <= filter.ToYear) {
+			years = append(years, t)
+		}
+	}
+	return years
+}
+
+// filterProvinces mengembalikan indeks provinsi yang diminta, atau nil bila
+// filter kosong.
+func (c *DataCube) filterProvinces(names []string) ([]int, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var provinces []int
+	for _, name := range names {
+		province, ok := c.FindProvince(name)
+		if !ok {
+			return nil, fmt.Errorf("provinsi %q tidak ditemukan di data kabupaten", name)
+		}
+		provinces = append(provinces, c.provinceIndex[province])
+	}
+	return provinces, nil
+}
+
+func (c *DataCube) filterRegencies(provinces []int, ids []string) []int {
+	var regencies []int
+	if provinces == nil {
+		for r := range c.Regencies {
+			regencies = append(regencies, r)
+		}
+	} else {
+		for _, p := range provinces {
+			regencies = append(regencies, c.provinceRegencies[p]...)
+		}
+		slices.Sort(regencies)
+	}
+	if len(ids) == 0 {
+		return regencies
+	}
+	return slices.DeleteFunc(regencies, func(r int) bool {
+		return !slices.Contains(ids, c.Regencies[r].ID)
+	})
+}
+
+func (c *DataCube) collect(years []int, cell func(t int) (float64, int)) map[int]float64 {
+	values := make(map[int]float64)
+	for _, t := range years {
+		if sum, rows := cell(t); rows > 0 {
+			values[c.Years[t]] = c.value(sum, rows)
+		}
+	}
+	return values
+}
+
+func (c *DataCube) sumRegencies(level CubeLevel, years, regencies []int, group func(r int) string) []CubeSeries {
+	var series []CubeSeries
+	var rows []map[int]int
+	position := make(map[string]int)
+	for _, r := range regencies {
+		key := group(r)
+		i, ok := position[key]
+		if !ok {
+			i = len(series)
+			position[key] = i
+			series = append(series, CubeSeries{Level: level, Key: key, Name: key, Province: c.Regencies[r].Province, Values: make(map[int]float64)})
+			rows = append(rows, make(map[int]int))
+			if level == LevelNational {
+				series[i].Province = ""
+			}
+		}
+		for _, t := range years {
+			if c.regencyRows[t][r] > 0 {
+				series[i].Values[c.Years[t]] += c.regencyArea[t][r]
+				rows[i][c.Years[t]] += c.regencyRows[t][r]
+			}
+		}
+	}
+	for i := range series {
+		for year, sum := range series[i].Values {
+			series[i].Values[year] = c.value(sum, rows[i][year])
+		}
+	}
+	return series
+}
+
+// aggregateFunctions adalah agregasi yang didukung aggregateValues.
+var aggregateFunctions = []string{"sum", "mean", "min", "max", "count"}
+
+// Aggregate meringkas Values seri dengan aggregateValues.
+func (s CubeSeries) Aggregate(function string) (float64, error) {
+	values := make([]float64, 0, len(s.Values))
+	for _, year := range getSortedYears(s.Values) {
+		values = append(values, s.Values[year])
+	}
+	return aggregateValues(values, function)
+}
+
+func aggregateValues(values []float64, function string) (float64, error) {
+	switch function {
+	case "count":
+		return float64(len(values)), nil
+	case "sum", "mean":
+		total := 0.0
+		for _, value := range values {
+			total += value
+		}
+		if function == "mean" && len(values) > 0 {
+			return total / float64(len(values)), nil
+		}
+		return total, nil
+	case "min", "max":
+		if len(values) == 0 {
+			return 0, nil
+		}
+		result := values[0]
+		for _, value := range values[1:] {
+			if function == "min" {
+				result = math.Min(result, value)
+			} else {
+				result = math.Max(result, value)
+			}
+		}
+		return result, nil
+	}
+	return 0, fmt.Errorf("agregasi tidak dikenal: %q (gunakan %s)", function, strings.Join(aggregateFunctions, ", "))
+}
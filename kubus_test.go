@@ -0,0 +1,112 @@
+package main
+
+import "testing"
+
+// cubeTestData: dua provinsi, tiga kabupaten, dua tahun. ID-1102 berganti
+// nama dan baru muncul pada 2021.
+func cubeTestData() []RawPalmOilData {
+	return []RawPalmOilData{
+		{Year: 2020, Region: "KAB A", RegionID: "ID-1101", ParentRegion: "ACEH", Values: []float64{100}},
+		{Year: 2021, Region: "KAB A", RegionID: "ID-1101", ParentRegion: "ACEH", Values: []float64{150}},
+		{Year: 2021, Region: "KAB B BARU", RegionID: "ID-1102", ParentRegion: "ACEH", Values: []float64{50}},
+		{Year: 2020, Region: "KAB C", RegionID: "ID-1401", ParentRegion: "RIAU", Values: []float64{300}},
+		{Year: 2021, Region: "KAB C", RegionID: "ID-1401", ParentRegion: "RIAU", Values: []float64{200}},
+	}
+}
+
+func TestDataCubeRollUps(t *testing.T) {
+	cube := newDataCube(cubeTestData(), 0)
+
+	if got := cube.NationalArea(2021); got != 400 {
+		t.Errorf("area nasional 2021 = %v, seharusnya 400", got)
+	}
+	if got := cube.NationalArea(1999); got != 0 {
+		t.Errorf("area nasional tahun tanpa data = %v, seharusnya 0", got)
+	}
+	if got := cube.RegencyArea("ID-1102", 2020); got != 0 {
+		t.Errorf("area kabupaten sebelum muncul = %v, seharusnya 0", got)
+	}
+	provinces := cube.ProvinceAreas(2021)
+	if provinces["ACEH"] != 200 || provinces["RIAU"] != 200 || len(provinces) != 2 {
+		t.Errorf("area provinsi 2021 = %v", provinces)
+	}
+	if regencies := cube.RegencyAreas(2020); len(regencies) != 2 || regencies["ID-1101"] != 100 {
+		t.Errorf("area kabupaten 2020 = %v, seharusnya hanya kabupaten yang memiliki data", regencies)
+	}
+	if cube.Regencies[1].Name != "KAB B BARU" {
+		t.Errorf("nama kabupaten seharusnya dari tahun terakhir, didapat %q", cube.Regencies[1].Name)
+	}
+}
+
+func TestDataCubeSeries(t *testing.T) {
+	cube := newDataCube(cubeTestData(), 0)
+
+	// Provinsi dari roll-up dan dari penjumlahan kabupaten harus sama.
+	rollUp, err := cube.Series(LevelProvince, CubeFilter{Provinces: []string{"aceh"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summed, err := cube.Series(LevelProvince, CubeFilter{Regencies: []string{"ID-1101", "ID-1102"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rollUp) != 1 || len(summed) != 1 || rollUp[0].Values[2021] != 200 || summed[0].Values[2021] != 200 || summed[0].Values[2020] != 100 {
+		t.Errorf("seri ACEH: roll-up %v, jumlah kabupaten %v", rollUp, summed)
+	}
+
+	national, err := cube.Series(LevelNational, CubeFilter{Provinces: []string{"RIAU"}, FromYear: 2021})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(national) != 1 || len(national[0].Values) != 1 || national[0].Values[2021] != 200 {
+		t.Errorf("seri nasional RIAU mulai 2021 = %v", national)
+	}
+
+	if _, err := cube.Series(LevelProvince, CubeFilter{Provinces: []string{"JAMBI"}}); err == nil {
+		t.Error("provinsi yang tidak ada seharusnya menghasilkan error")
+	}
+	if _, err := cube.Series("pulau", CubeFilter{}); err == nil {
+		t.Error("level yang tidak dikenal seharusnya menghasilkan error")
+	}
+}
+
+func TestDataCubeMeanAggregation(t *testing.T) {
+	defer func(metrics []Metric) { activeMetrics = metrics }(activeMetrics)
+	activeMetrics = []Metric{{Name: "tutupan", Column: "cover", Aggregation: "mean"}}
+
+	cube := newDataCube(cubeTestData(), 0)
+	if got := cube.ProvinceAreas(2021)["ACEH"]; got != 100 {
+		t.Errorf("rata-rata ACEH 2021 = %v, seharusnya 100", got)
+	}
+	if got := cube.NationalArea(2020); got != 200 {
+		t.Errorf("rata-rata nasional 2020 = %v, seharusnya 200", got)
+	}
+	summed, err := cube.Series(LevelNational, CubeFilter{Regencies: []string{"ID-1101", "ID-1102", "ID-1401"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := summed[0].Values[2021]; got != 400.0/3 {
+		t.Errorf("rata-rata nasional 2021 dari kabupaten = %v, seharusnya %v", got, 400.0/3)
+	}
+}
+
+func TestAggregateValues(t *testing.T) {
+	values := []float64{4, 1, 3}
+	for _, test := range []struct {
+		function string
+		want     float64
+	}{
+		{"sum", 8}, {"mean", 8.0 / 3}, {"min", 1}, {"max", 4}, {"count", 3},
+	} {
+		got, err := aggregateValues(values, test.function)
+		if err != nil || got != test.want {
+			t.Errorf("%s = %v (%v), seharusnya %v", test.function, got, err, test.want)
+		}
+	}
+	if got, err := aggregateValues(nil, "max"); err != nil || got != 0 {
+		t.Errorf("max tanpa nilai = %v (%v), seharusnya 0", got, err)
+	}
+	if _, err := aggregateValues(values, "median"); err == nil {
+		t.Error("agregasi yang tidak dikenal seharusnya menghasilkan error")
+	}
+}
@@ -0,0 +1,1610 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Mode query menjawab pertanyaan ad-hoc dengan bahasa mirip SQL:
+//
+//	SELECT kabupaten, provinsi, tambah(2015, 2020) AS tambah
+//	FROM kabupaten WHERE provinsi LIKE 'kalimantan%'
+//	ORDER BY tambah DESC LIMIT 10
+//
+// Sumber data dibangun dari kubus dan model provinsi yang sama dengan yang
+// dipakai laporan. Kata kunci tidak membedakan huruf besar/kecil, begitu
+// juga perbandingan teks (=, LIKE, IN). Agregasi (sum, mean, min, max,
+// count) meringkas grup GROUP BY, atau seluruh hasil bila tanpa GROUP BY;
+// seperti SQL, kolom non-agregat di kueri berkelompok harus tercantum di
+// GROUP BY.
+
+var queryFormats = []string{"tabel", "csv", "json"}
+
+// maxQueryRoundDigits membatasi digit round() agar 10^digit tidak meluap.
+const maxQueryRoundDigits = 15
+
+// queryRow adalah satu baris sumber: nilai kolom (float64, string atau
+// bool), seri tahunan untuk fungsi seperti area(tahun) dan tahun-tahun data
+// (terurut) yang boleh dipakai sebagai argumen fungsi tersebut.
+type queryRow struct {
+	Values map[string]any
+	Yearly map[string]map[int]float64
+	Years  []int
+}
+
+type queryColumnInfo struct {
+	Name        string
+	Description string
+}
+
+type queryFunction struct {
+	Usage       string
+	Description string
+	MinArgs     int
+	MaxArgs     int
+	Call        func(row queryRow, args []any) (any, error)
+}
+
+type querySource struct {
+	Name        string
+	Description string
+	Columns     []queryColumnInfo
+	Functions   map[string]queryFunction
+	Load        func(ctx context.Context, data *queryData) ([]queryRow, error)
+}
+
+// queryData memuat data mentah sekali dan membangun kubus/model hanya bila
+// sumber yang diminta membutuhkannya.
+type queryData struct {
+	rawData []RawPalmOilData
+	cube    *DataCube
+	models  []ProvinceModel
+}
+
+func (d *queryData) dataCube() *DataCube {
+	if d.cube == nil {
+		d.cube = newDataCube(d.rawData, 0)
+	}
+	return d.cube
+}
+
+func (d *queryData) provinceModels(ctx context.Context) ([]ProvinceModel, error) {
+	if d.models == nil {
+		production, err := loadProductionData()
+		if err != nil {
+			return nil, err
+		}
+		forestLoss, err := loadForestLoss()
+		if err != nil {
+			return nil, err
+		}
+		forest, err := analyzeDeforestation(d.dataCube(), forestLoss)
+		if err != nil {
+			return nil, err
+		}
+		models, err := buildProvinceModels(ctx, d.dataCube(), production, forest, loadScoringConfig())
+		if err != nil {
+			return nil, err
+		}
+		d.models = models
+	}
+	return d.models, nil
+}
+
+var querySources = []*querySource{
+	{
+		Name:        "baris",
+		Description: "baris mentah CSV (tahun × kabupaten)",
+		Columns: []queryColumnInfo{
+			{"tahun", "tahun data"},
+			{"id", "RegionID kabupaten"},
+			{"kabupaten", "nama kabupaten"},
+			{"provinsi", "nama provinsi"},
+			{"area", "area tertanam (ha)"},
+		},
+		Load: func(ctx context.Context, data *queryData) ([]queryRow, error) {
+			rows := make([]queryRow, len(data.rawData))
+			for i, raw := range data.rawData {
+				rows[i] = queryRow{Values: map[string]any{
+					"tahun":     float64(raw.Year),
+					"id":        raw.RegionID,
+					"kabupaten": raw.Region,
+					"provinsi":  raw.ParentRegion,
+					"area":      raw.Values[0],
+				}}
+			}
+			return rows, nil
+		},
+	},
+	{
+		Name:        "kabupaten",
+		Description: "satu baris per kabupaten dari kubus data",
+		Columns: []queryColumnInfo{
+			{"id", "RegionID kabupaten"},
+			{"kabupaten", "nama kabupaten (tahun terakhir)"},
+			{"provinsi", "provinsi (tahun terakhir)"},
+			{"jumlah_tahun", "jumlah tahun yang memiliki data"},
+			{"area_2003", "area 2003 (ha)"},
+			{"area_2022", "area 2022 (ha)"},
+			{"tahun_puncak", "tahun area tertinggi"},
+			{"area_puncak", "area tertinggi (ha)"},
+		},
+		Functions: areaQueryFunctions(),
+		Load: func(ctx context.Context, data *queryData) ([]queryRow, error) {
+			series, err := data.dataCube().Series(LevelRegency, CubeFilter{})
+			if err != nil {
+				return nil, err
+			}
+			rows := make([]queryRow, len(series))
+			for i, regency := range series {
+				peakYear, peakArea := findPeakYearAndArea(regency.Values)
+				rows[i] = queryRow{
+					Values: map[string]any{
+						"id":           regency.Key,
+						"kabupaten":    regency.Name,
+						"provinsi":     regency.Province,
+						"jumlah_tahun": float64(len(regency.Values)),
+						"area_2003":    regency.Values[2003],
+						"area_2022":    regency.Values[2022],
+						"tahun_puncak": float64(peakYear),
+						"area_puncak":  peakArea,
+					},
+					Yearly: map[string]map[int]float64{"area": regency.Values},
+					Years:  data.dataCube().Years,
+				}
+			}
+			return rows, nil
+		},
+	},
+	{
+		Name:        "provinsi",
+		Description: "model provinsi yang sama dengan Excel dan laporan",
+		Columns: []queryColumnInfo{
+			{"provinsi", "nama provinsi"},
+			{"area_2003", "area 2003 (ha)"},
+			{"area_2022", "area 2022 (ha)"},
+			{"pertumbuhan_20tahun", "pertumbuhan 2003-2022 (%)"},
+			{"pertumbuhan_tahunan", "rata-rata pertumbuhan per tahun (%)"},
+			{"pangsa_2022", "pangsa pasar 2022 (%)"},
+			{"peringkat_2022", "peringkat area 2022"},
+			{"tren", "kode tren, mis. EXPLOSIVE_GROWTH"},
+			{"efisiensi", "skor efisiensi produksi (0-10, dari hasil per hektar bila ada data produksi)"},
+			{"daya_saing", "skor daya saing"},
+			{"potensi_investasi", "kode potensi investasi: VERY HIGH, HIGH, MEDIUM, LOW, VERY LOW"},
+			{"risiko", "kode tingkat risiko, mis. HIGH"},
+			{"proyeksi_2030", "proyeksi area 2030 (ha)"},
+			{"tahun_puncak", "tahun area tertinggi"},
+			{"area_puncak", "area tertinggi (ha)"},
+			{"stabilitas", "indeks stabilitas"},
+			{"periode_dominan", "fase pertumbuhan dominan"},
+			{"hasil_tbs", "hasil TBS tahun data produksi terakhir (t/ha, 0 tanpa data produksi)"},
+			{"hasil_cpo", "hasil CPO (t/ha)"},
+			{"tren_hasil", "tren hasil TBS (t/ha per tahun)"},
+			{"kesenjangan_hasil", "kesenjangan hasil TBS terhadap provinsi terbaik (%)"},
+		},
+		Functions: provinceQueryFunctions(),
+		Load: func(ctx context.Context, data *queryData) ([]queryRow, error) {
+			models, err := data.provinceModels(ctx)
+			if err != nil {
+				return nil, err
+			}
+			rows := make([]queryRow, len(models))
+			for i, model := range models {
+				ranks := make(map[int]float64, len(model.Ranks.Area))
+				for year, rank := range model.Ranks.Area {
+					ranks[year] = float64(rank)
+				}
+				rows[i] = queryRow{
+					Values: map[string]any{
+						"provinsi":            model.Province,
+						"area_2003":           model.TotalArea2003,
+						"area_2022":           model.TotalArea2022,
+						"pertumbuhan_20tahun": model.GrowthRate20Years,
+						"pertumbuhan_tahunan": model.AnnualGrowthRate,
+						"pangsa_2022":         model.MarketShare2022,
+						"peringkat_2022":      float64(model.Rank2022),
+						"tren":                model.Trend,
+						"efisiensi":           model.ProductionEfficiency,
+						"daya_saing":          model.Competitiveness,
+						"potensi_investasi":   model.InvestmentPotential,
+						"risiko":              model.RiskLevel,
+						"proyeksi_2030":       model.Projection2030,
+						"tahun_puncak":        float64(model.PeakYear),
+						"area_puncak":         model.PeakArea,
+						"stabilitas":          model.StabilityIndex,
+						"periode_dominan":     model.DominantPeriod,
+						"hasil_tbs":           yieldOf(model).FFBYield,
+						"hasil_cpo":           yieldOf(model).CPOYield,
+						"tren_hasil":          yieldOf(model).Trend,
+						"kesenjangan_hasil":   yieldOf(model).Gap,
+					},
+					Yearly: map[string]map[int]float64{
+						"area":      model.YearlyData,
+						"pangsa":    model.MarketShareHistory,
+						"peringkat": ranks,
+					},
+					Years: data.dataCube().Years,
+				}
+			}
+			return rows, nil
+		},
+	},
+	{
+		Name:        "nasional",
+		Description: "tren nasional per tahun",
+		Columns: []queryColumnInfo{
+			{"tahun", "tahun"},
+			{"area", "total area nasional (ha)"},
+			{"pertumbuhan", "pertumbuhan dari tahun sebelumnya (%)"},
+			{"perubahan", "perubahan area dari tahun sebelumnya (ha)"},
+			{"provinsi_teratas", "provinsi dengan area terbesar"},
+			{"area_provinsi_teratas", "area provinsi teratas (ha)"},
+		},
+		Load: func(ctx context.Context, data *queryData) ([]queryRow, error) {
+			trends := analyzeNationalTrends(data.dataCube())
+			rows := make([]queryRow, len(trends))
+			for i, trend := range trends {
+				rows[i] = queryRow{Values: map[string]any{
+					"tahun":                 float64(trend.Year),
+					"area":                  trend.TotalArea,
+					"pertumbuhan":           trend.GrowthRate,
+					"perubahan":             trend.AnnualChange,
+					"provinsi_teratas":      trend.TopProvince,
+					"area_provinsi_teratas": trend.TopProvinceArea,
+				}}
+			}
+			return rows, nil
+		},
+	},
+}
+
+// areaQueryFunctions adalah fungsi seri tahunan untuk sumber kabupaten dan
+// provinsi. Tahun di luar rentang data adalah error; tahun di dalam rentang
+// tanpa data (mis. kabupaten yang baru muncul) bernilai 0.
+func areaQueryFunctions() map[string]queryFunction {
+	span := func(row queryRow, args []any) (float64, float64, error) {
+		from, err := row.yearArg(args[0])
+		if err != nil {
+			return 0, 0, err
+		}
+		to, err := row.yearArg(args[1])
+		if err != nil {
+			return 0, 0, err
+		}
+		return row.Yearly["area"][from], row.Yearly["area"][to], nil
+	}
+
+	return map[string]queryFunction{
+		"area": {Usage: "area(tahun)", Description: "area pada tahun tersebut (ha)", MinArgs: 1, MaxArgs: 1, Call: queryYearlySeries("area")},
+		"tambah": {Usage: "tambah(dari, sampai)", Description: "selisih area sampai - dari (ha)", MinArgs: 2, MaxArgs: 2,
+			Call: func(row queryRow, args []any) (any, error) {
+				from, to, err := span(row, args)
+				return to - from, err
+			}},
+		"pertumbuhan": {Usage: "pertumbuhan(dari, sampai)", Description: "pertumbuhan area dari → sampai (%, 0 bila area awal 0)", MinArgs: 2, MaxArgs: 2,
+			Call: func(row queryRow, args []any) (any, error) {
+				from, to, err := span(row, args)
+				if err != nil || from <= 0 {
+					return 0.0, err
+				}
+				return (to - from) / from * 100, nil
+			}},
+	}
+}
+
+// provinceQueryFunctions menambahkan seri pangsa pasar dan peringkat yang
+// hanya dihitung untuk model provinsi.
+func provinceQueryFunctions() map[string]queryFunction {
+	functions := areaQueryFunctions()
+	functions["pangsa"] = queryFunction{Usage: "pangsa(tahun)", Description: "pangsa pasar pada tahun tersebut (%)", MinArgs: 1, MaxArgs: 1,
+		Call: queryYearlySeries("pangsa")}
+	functions["peringkat"] = queryFunction{Usage: "peringkat(tahun)", Description: "peringkat area pada tahun tersebut", MinArgs: 1, MaxArgs: 1,
+		Call: queryYearlySeries("peringkat")}
+	return functions
+}
+
+func queryYearlySeries(series string) func(row queryRow, args []any) (any, error) {
+	return func(row queryRow, args []any) (any, error) {
+		year, err := row.yearArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return row.Yearly[series][year], nil
+	}
+}
+
+// queryScalarFunctions tersedia di semua sumber.
+var queryScalarFunctions = map[string]queryFunction{
+	"abs": {Usage: "abs(x)", Description: "nilai mutlak", MinArgs: 1, MaxArgs: 1,
+		Call: func(row queryRow, args []any) (any, error) {
+			x, err := queryNumber(args[0])
+			return math.Abs(x), err
+		}},
+	"round": {Usage: "round(x[, digit])", Description: "pembulatan", MinArgs: 1, MaxArgs: 2,
+		Call: func(row queryRow, args []any) (any, error) {
+			x, err := queryNumber(args[0])
+			if err != nil {
+				return nil, err
+			}
+			digits := 0
+			if len(args) == 2 {
+				value, err := queryNumber(args[1])
+				if err != nil {
+					return nil, err
+				}
+				if value != math.Trunc(value) || value < 0 || value > maxQueryRoundDigits {
+					return nil, fmt.Errorf("digit harus bilangan bulat 0-%d, bukan %s", maxQueryRoundDigits, formatQueryValue(value, true))
+				}
+				digits = int(value)
+			}
+			scale := math.Pow10(digits)
+			return math.Round(x*scale) / scale, nil
+		}},
+}
+
+func findQuerySource(name string) (*querySource, error) {
+	for _, source := range querySources {
+		if strings.EqualFold(source.Name, name) {
+			return source, nil
+		}
+	}
+	var names []string
+	for _, source := range querySources {
+		names = append(names, source.Name)
+	}
+	return nil, fmt.Errorf("sumber tidak dikenal: %q (gunakan %s)", name, strings.Join(names, ", "))
+}
+
+func (s *querySource) hasColumn(name string) bool {
+	return slices.ContainsFunc(s.Columns, func(column queryColumnInfo) bool { return column.Name == name })
+}
+
+func (s *querySource) function(name string) (queryFunction, bool) {
+	if function, ok := s.Functions[name]; ok {
+		return function, true
+	}
+	function, ok := queryScalarFunctions[name]
+	return function, ok
+}
+
+// runQuery mengurai dan menjalankan kueri lalu menulis hasilnya ke out
+// dalam format tabel, csv atau json.
+func runQuery(ctx context.Context, text, format string, out io.Writer) error {
+	statement, err := parseQuery(text)
+	if err != nil {
+		return err
+	}
+	source, err := findQuerySource(statement.Source)
+	if err != nil {
+		return err
+	}
+	if err := statement.check(source); err != nil {
+		return err
+	}
+
+	rawData, _, err := loadRawData(dataFile)
+	if err != nil {
+		return err
+	}
+	rows, err := source.Load(ctx, &queryData{rawData: rawData})
+	if err != nil {
+		return err
+	}
+
+	result, err := statement.execute(source, rows)
+	if err != nil {
+		return err
+	}
+	return writeQueryResult(out, result, format)
+}
+
+// printQueryHelp mencetak sumber, kolom dan fungsi yang dapat dipakai.
+func printQueryHelp(out io.Writer) {
+	fmt.Fprintln(out, "Penggunaan: tet [-format tabel|csv|json] query \"SELECT ... FROM <sumber> [WHERE ...] [GROUP BY ...] [HAVING ...] [ORDER BY ... [DESC]] [LIMIT n]\"")
+	fmt.Fprintln(out, "\nContoh:")
+	fmt.Fprintln(out, "  SELECT kabupaten, provinsi, tambah(2015, 2020) AS tambah FROM kabupaten WHERE provinsi LIKE 'kalimantan%' ORDER BY tambah DESC LIMIT 10")
+	fmt.Fprintln(out, "  SELECT provinsi, tahun_puncak, area_puncak FROM provinsi WHERE tahun_puncak < 2022 ORDER BY tahun_puncak")
+	fmt.Fprintln(out, "  SELECT provinsi, count(*) AS kabupaten, sum(area) AS area FROM baris WHERE tahun = 2022 GROUP BY provinsi ORDER BY area DESC")
+	for _, source := range querySources {
+		fmt.Fprintf(out, "\nSumber %s: %s\n", source.Name, source.Description)
+		for _, column := range source.Columns {
+			fmt.Fprintf(out, "  %-24s %s\n", column.Name, column.Description)
+		}
+		names := make([]string, 0, len(source.Functions))
+		for name := range source.Functions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(out, "  %-24s %s\n", source.Functions[name].Usage, source.Functions[name].Description)
+		}
+	}
+	fmt.Fprintf(out, "\nFungsi umum: abs(x), round(x[, digit]); agregasi: %s\n", strings.Join(aggregateFunctions, ", "))
+	fmt.Fprintln(out, "Operator: + - * /, = != < <= > >=, LIKE ('%' dan '_'), IN (...), AND, OR, NOT")
+}
+
+// --- Tokenizer dan parser ---
+
+type queryTokenKind int
+
+const (
+	tokenEOF queryTokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenSymbol
+)
+
+type queryToken struct {
+	Kind queryTokenKind
+	Text string
+	Pos  int
+	End  int
+}
+
+var queryKeywords = []string{"SELECT", "FROM", "WHERE", "GROUP", "BY", "HAVING", "ORDER", "ASC", "DESC",
+	"LIMIT", "AS", "AND", "OR", "NOT", "LIKE", "IN"}
+
+func tokenizeQuery(text string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, queryToken{Kind: tokenIdent, Text: string(runes[start:i])})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, queryToken{Kind: tokenNumber, Text: string(runes[start:i])})
+		case r == '\'':
+			var value strings.Builder
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("teks tidak ditutup mulai posisi %d", start+1)
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						value.WriteRune('\'')
+						i++
+						continue
+					}
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+			}
+			tokens = append(tokens, queryToken{Kind: tokenString, Text: value.String()})
+		default:
+			symbol := string(r)
+			if i+1 < len(runes) {
+				if pair := string(runes[i : i+2]); pair == "<=" || pair == ">=" || pair == "!=" || pair == "<>" {
+					symbol = pair
+				}
+			}
+			if !strings.Contains("(),*+-/=<>", symbol) && len(symbol) == 1 {
+				return nil, fmt.Errorf("karakter tidak dikenal %q di posisi %d", r, i+1)
+			}
+			i += len([]rune(symbol))
+			tokens = append(tokens, queryToken{Kind: tokenSymbol, Text: symbol})
+		}
+		tokens[len(tokens)-1].Pos, tokens[len(tokens)-1].End = start, i
+	}
+	return append(tokens, queryToken{Kind: tokenEOF, Pos: len(runes), End: len(runes)}), nil
+}
+
+type queryExpr any
+
+type queryLiteral struct{ Value any }
+
+type queryColumnRef struct{ Name string }
+
+type queryCall struct {
+	Name string
+	Args []queryExpr
+	Star bool
+}
+
+type queryUnary struct {
+	Op      string
+	Operand queryExpr
+}
+
+type queryBinary struct {
+	Op          string
+	Left, Right queryExpr
+}
+
+type queryIn struct {
+	Operand queryExpr
+	List    []queryExpr
+	Negate  bool
+}
+
+type querySelectItem struct {
+	Expr queryExpr
+	Name string
+}
+
+type queryOrder struct {
+	Expr       queryExpr
+	Descending bool
+}
+
+type queryStatement struct {
+	Items   []querySelectItem
+	Star    bool
+	Source  string
+	Where   queryExpr
+	GroupBy []queryExpr
+	Having  queryExpr
+	OrderBy []queryOrder
+	Limit   int
+}
+
+type queryParser struct {
+	text   []rune
+	tokens []queryToken
+	pos    int
+}
+
+func parseQuery(text string) (*queryStatement, error) {
+	tokens, err := tokenizeQuery(text)
+	if err != nil {
+		return nil, fmt.Errorf("kueri tidak valid: %w", err)
+	}
+	parser := &queryParser{text: []rune(text), tokens: tokens}
+	statement, err := parser.statement()
+	if err != nil {
+		return nil, fmt.Errorf("kueri tidak valid: %w", err)
+	}
+	return statement, nil
+}
+
+func (p *queryParser) peek() queryToken { return p.tokens[p.pos] }
+
+func (p *queryParser) next() queryToken {
+	token := p.tokens[p.pos]
+	if token.Kind != tokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *queryParser) isKeyword(keyword string) bool {
+	token := p.peek()
+	return token.Kind == tokenIdent && strings.EqualFold(token.Text, keyword)
+}
+
+func (p *queryParser) acceptKeyword(keyword string) bool {
+	if p.isKeyword(keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) acceptSymbol(symbol string) bool {
+	if token := p.peek(); token.Kind == tokenSymbol && token.Text == symbol {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) unexpected(expected string) error {
+	token := p.peek()
+	if token.Kind == tokenEOF {
+		return fmt.Errorf("diharapkan %s, tetapi kueri berakhir", expected)
+	}
+	return fmt.Errorf("diharapkan %s di posisi %d, ditemukan %q", expected, token.Pos+1, string(p.text[token.Pos:token.End]))
+}
+
+func (p *queryParser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.unexpected(keyword)
+	}
+	return nil
+}
+
+func (p *queryParser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.unexpected(fmt.Sprintf("%q", symbol))
+	}
+	return nil
+}
+
+func (p *queryParser) identifier() (string, error) {
+	token := p.peek()
+	if token.Kind != tokenIdent || slices.Contains(queryKeywords, strings.ToUpper(token.Text)) {
+		return "", p.unexpected("nama")
+	}
+	p.pos++
+	return strings.ToLower(token.Text), nil
+}
+
+func (p *queryParser) statement() (*queryStatement, error) {
+	statement := &queryStatement{Limit: -1}
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+
+	if p.acceptSymbol("*") {
+		statement.Star = true
+	} else {
+		for {
+			start := p.peek().Pos
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			item := querySelectItem{Expr: expr, Name: strings.TrimSpace(string(p.text[start:p.tokens[p.pos-1].End]))}
+			if p.acceptKeyword("AS") {
+				if item.Name, err = p.identifier(); err != nil {
+					return nil, err
+				}
+			}
+			statement.Items = append(statement.Items, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	source, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	statement.Source = source
+
+	if p.acceptKeyword("WHERE") {
+		if statement.Where, err = p.expression(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("GROUP") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			statement.GroupBy = append(statement.GroupBy, expr)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if p.acceptKeyword("HAVING") {
+		if statement.Having, err = p.expression(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			order := queryOrder{Expr: expr}
+			if p.acceptKeyword("DESC") {
+				order.Descending = true
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			statement.OrderBy = append(statement.OrderBy, order)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		token := p.next()
+		limit, err := strconv.Atoi(token.Text)
+		if token.Kind != tokenNumber || err != nil || limit < 0 {
+			p.pos--
+			return nil, p.unexpected("bilangan bulat LIMIT")
+		}
+		statement.Limit = limit
+	}
+	if p.peek().Kind != tokenEOF {
+		return nil, p.unexpected("akhir kueri")
+	}
+	return statement, nil
+}
+
+func (p *queryParser) expression() (queryExpr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = queryBinary{Op: "OR", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) and() (queryExpr, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = queryBinary{Op: "AND", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) not() (queryExpr, error) {
+	if p.acceptKeyword("NOT") {
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return queryUnary{Op: "NOT", Operand: operand}, nil
+	}
+	return p.comparison()
+}
+
+func (p *queryParser) comparison() (queryExpr, error) {
+	left, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+
+	negate := p.acceptKeyword("NOT")
+	switch {
+	case p.acceptKeyword("LIKE"):
+		right, err := p.additive()
+		if err != nil {
+			return nil, err
+		}
+		var expr queryExpr = queryBinary{Op: "LIKE", Left: left, Right: right}
+		if negate {
+			expr = queryUnary{Op: "NOT", Operand: expr}
+		}
+		return expr, nil
+	case p.acceptKeyword("IN"):
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		in := queryIn{Operand: left, Negate: negate}
+		for {
+			item, err := p.additive()
+			if err != nil {
+				return nil, err
+			}
+			in.List = append(in.List, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		return in, p.expectSymbol(")")
+	case negate:
+		return nil, p.unexpected("LIKE atau IN setelah NOT")
+	}
+
+	for _, op := range []string{"=", "!=", "<>", "<=", ">=", "<", ">"} {
+		if p.acceptSymbol(op) {
+			right, err := p.additive()
+			if err != nil {
+				return nil, err
+			}
+			if op == "<>" {
+				op = "!="
+			}
+			return queryBinary{Op: op, Left: left, Right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *queryParser) additive() (queryExpr, error) {
+	left, err := p.multiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().Text
+		if !p.acceptSymbol("+") && !p.acceptSymbol("-") {
+			return left, nil
+		}
+		right, err := p.multiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = queryBinary{Op: op, Left: left, Right: right}
+	}
+}
+
+func (p *queryParser) multiplicative() (queryExpr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().Text
+		if !p.acceptSymbol("*") && !p.acceptSymbol("/") {
+			return left, nil
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = queryBinary{Op: op, Left: left, Right: right}
+	}
+}
+
+func (p *queryParser) unary() (queryExpr, error) {
+	if p.acceptSymbol("-") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return queryUnary{Op: "-", Operand: operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *queryParser) primary() (queryExpr, error) {
+	token := p.peek()
+	switch token.Kind {
+	case tokenNumber:
+		p.pos++
+		value, err := strconv.ParseFloat(token.Text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bilangan tidak valid %q di posisi %d", token.Text, token.Pos+1)
+		}
+		return queryLiteral{Value: value}, nil
+	case tokenString:
+		p.pos++
+		return queryLiteral{Value: token.Text}, nil
+	case tokenSymbol:
+		if p.acceptSymbol("(") {
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expectSymbol(")")
+		}
+	case tokenIdent:
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptSymbol("(") {
+			return queryColumnRef{Name: name}, nil
+		}
+		call := queryCall{Name: name}
+		if p.acceptSymbol("*") {
+			call.Star = true
+		} else if p.peek().Kind != tokenSymbol || p.peek().Text != ")" {
+			for {
+				arg, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+				call.Args = append(call.Args, arg)
+				if !p.acceptSymbol(",") {
+					break
+				}
+			}
+		}
+		return call, p.expectSymbol(")")
+	}
+	return nil, p.unexpected("nilai, kolom atau fungsi")
+}
+
+// --- Validasi dan eksekusi ---
+
+func isAggregateCall(expr queryExpr) bool {
+	call, ok := expr.(queryCall)
+	return ok && slices.Contains(aggregateFunctions, call.Name)
+}
+
+func containsAggregate(expr queryExpr) bool {
+	found := false
+	walkQueryExpr(expr, func(e queryExpr) {
+		if isAggregateCall(e) {
+			found = true
+		}
+	})
+	return found
+}
+
+func walkQueryExpr(expr queryExpr, visit func(queryExpr)) {
+	if expr == nil {
+		return
+	}
+	visit(expr)
+	switch e := expr.(type) {
+	case queryCall:
+		for _, arg := range e.Args {
+			walkQueryExpr(arg, visit)
+		}
+	case queryUnary:
+		walkQueryExpr(e.Operand, visit)
+	case queryBinary:
+		walkQueryExpr(e.Left, visit)
+		walkQueryExpr(e.Right, visit)
+	case queryIn:
+		walkQueryExpr(e.Operand, visit)
+		for _, item := range e.List {
+			walkQueryExpr(item, visit)
+		}
+	}
+}
+
+func (s *queryStatement) grouped() bool {
+	if len(s.GroupBy) > 0 || s.Having != nil {
+		return true
+	}
+	for _, item := range s.Items {
+		if containsAggregate(item.Expr) {
+			return true
+		}
+	}
+	for _, order := range s.OrderBy {
+		if containsAggregate(order.Expr) {
+			return true
+		}
+	}
+	return false
+}
+
+// check memastikan kolom dan fungsi dikenal sumber sebelum data dimuat.
+// Alias SELECT boleh dipakai di HAVING dan ORDER BY; agregasi tidak boleh
+// di WHERE, GROUP BY, atau di dalam agregasi lain. Pada kueri berkelompok
+// setiap kolom di luar agregasi harus tercantum di GROUP BY.
+func (s *queryStatement) check(source *querySource) error {
+	var aliases []string
+	for _, item := range s.Items {
+		aliases = append(aliases, item.Name)
+	}
+
+	checkExpr := func(expr queryExpr, clause string, allowAliases, allowAggregates bool) error {
+		var err error
+		walkQueryExpr(expr, func(e queryExpr) {
+			if err != nil {
+				return
+			}
+			switch e := e.(type) {
+			case queryColumnRef:
+				if !source.hasColumn(e.Name) && !(allowAliases && slices.Contains(aliases, e.Name)) {
+					var names []string
+					for _, column := range source.Columns {
+						names = append(names, column.Name)
+					}
+					err = fmt.Errorf("kolom tidak dikenal %q di %s (kolom sumber %s: %s)", e.Name, clause, source.Name, strings.Join(names, ", "))
+				}
+			case queryCall:
+				if isAggregateCall(e) {
+					switch {
+					case !allowAggregates:
+						err = fmt.Errorf("agregasi %s() tidak boleh dipakai di %s", e.Name, clause)
+					case len(e.Args) > 0 && slices.ContainsFunc(e.Args, containsAggregate):
+						err = fmt.Errorf("agregasi %s() tidak boleh bersarang", e.Name)
+					case !e.Star && len(e.Args) != 1:
+						err = fmt.Errorf("agregasi %s() membutuhkan satu argumen", e.Name)
+					case e.Star && e.Name != "count":
+						err = fmt.Errorf("%s(*) tidak didukung, hanya count(*)", e.Name)
+					}
+					return
+				}
+				function, ok := source.function(e.Name)
+				switch {
+				case !ok:
+					err = fmt.Errorf("fungsi tidak dikenal %s() untuk sumber %s", e.Name, source.Name)
+				case e.Star || len(e.Args) < function.MinArgs || len(e.Args) > function.MaxArgs:
+					err = fmt.Errorf("jumlah argumen %s() tidak sesuai, gunakan %s", e.Name, function.Usage)
+				}
+			}
+		})
+		return err
+	}
+
+	for _, item := range s.Items {
+		if err := checkExpr(item.Expr, "SELECT", false, true); err != nil {
+			return err
+		}
+	}
+	if err := checkExpr(s.Where, "WHERE", false, false); err != nil {
+		return err
+	}
+	for _, expr := range s.GroupBy {
+		if err := checkExpr(expr, "GROUP BY", false, false); err != nil {
+			return err
+		}
+	}
+	if err := checkExpr(s.Having, "HAVING", true, true); err != nil {
+		return err
+	}
+	for _, order := range s.OrderBy {
+		if err := checkExpr(order.Expr, "ORDER BY", true, true); err != nil {
+			return err
+		}
+	}
+
+	if !s.grouped() {
+		return nil
+	}
+	if s.Star {
+		return fmt.Errorf("SELECT * tidak dapat dipakai bersama GROUP BY atau agregasi")
+	}
+	clauses := []struct {
+		name    string
+		exprs   []queryExpr
+		aliases []string
+	}{
+		{"SELECT", nil, nil},
+		{"HAVING", []queryExpr{s.Having}, aliases},
+		{"ORDER BY", nil, aliases},
+	}
+	for _, item := range s.Items {
+		clauses[0].exprs = append(clauses[0].exprs, item.Expr)
+	}
+	for _, order := range s.OrderBy {
+		clauses[2].exprs = append(clauses[2].exprs, order.Expr)
+	}
+	for _, clause := range clauses {
+		for _, expr := range clause.exprs {
+			switch e := s.ungroupedExpr(expr, clause.aliases).(type) {
+			case queryColumnRef:
+				return fmt.Errorf("kolom %q di %s harus tercantum di GROUP BY atau berada di dalam agregasi", e.Name, clause.name)
+			case queryCall:
+				return fmt.Errorf("%s() di %s harus tercantum di GROUP BY atau berada di dalam agregasi", e.Name, clause.name)
+			}
+		}
+	}
+	return nil
+}
+
+// ungroupedExpr mengembalikan bagian ekspresi yang nilainya bergantung pada
+// satu baris grup: kolom atau fungsi seri yang tidak tercantum di GROUP BY
+// dan tidak berada di dalam agregasi. Alias SELECT sudah bernilai per grup.
+func (s *queryStatement) ungroupedExpr(expr queryExpr, aliases []string) queryExpr {
+	if expr == nil || isAggregateCall(expr) || slices.ContainsFunc(s.GroupBy, func(group queryExpr) bool {
+		return reflect.DeepEqual(group, expr)
+	}) {
+		return nil
+	}
+	var children []queryExpr
+	switch e := expr.(type) {
+	case queryColumnRef:
+		if slices.Contains(aliases, e.Name) {
+			return nil
+		}
+		return e
+	case queryCall:
+		if _, ok := queryScalarFunctions[e.Name]; !ok {
+			return e
+		}
+		children = e.Args
+	case queryUnary:
+		children = []queryExpr{e.Operand}
+	case queryBinary:
+		children = []queryExpr{e.Left, e.Right}
+	case queryIn:
+		children = append([]queryExpr{e.Operand}, e.List...)
+	}
+	for _, child := range children {
+		if ungrouped := s.ungroupedExpr(child, aliases); ungrouped != nil {
+			return ungrouped
+		}
+	}
+	return nil
+}
+
+type queryResult struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// queryEnv adalah konteks evaluasi: grup baris (satu baris bila tanpa
+// agregasi) dan nilai alias SELECT yang sudah dihitung.
+type queryEnv struct {
+	source  *querySource
+	rows    []queryRow
+	aliases map[string]any
+}
+
+func (s *queryStatement) execute(source *querySource, rows []queryRow) (*queryResult, error) {
+	if s.Star {
+		for _, column := range source.Columns {
+			s.Items = append(s.Items, querySelectItem{Expr: queryColumnRef{Name: column.Name}, Name: column.Name})
+		}
+	}
+
+	var filtered []queryRow
+	for _, row := range rows {
+		if s.Where == nil {
+			filtered = append(filtered, row)
+			continue
+		}
+		keep, err := evalQueryBool(s.Where, queryEnv{source: source, rows: []queryRow{row}}, "WHERE")
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			filtered = append(filtered, row)
+		}
+	}
+
+	var groups [][]queryRow
+	if s.grouped() {
+		position := make(map[string]int)
+		for _, row := range filtered {
+			parts := make([]string, len(s.GroupBy))
+			for i, expr := range s.GroupBy {
+				value, err := evalQuery(expr, queryEnv{source: source, rows: []queryRow{row}})
+				if err != nil {
+					return nil, err
+				}
+				parts[i] = fmt.Sprint(value)
+			}
+			key := strings.Join(parts, "\x00")
+			i, ok := position[key]
+			if !ok {
+				i = len(groups)
+				position[key] = i
+				groups = append(groups, nil)
+			}
+			groups[i] = append(groups[i], row)
+		}
+		if len(s.GroupBy) == 0 && len(groups) == 0 {
+			groups = append(groups, nil)
+		}
+	} else {
+		for _, row := range filtered {
+			groups = append(groups, []queryRow{row})
+		}
+	}
+
+	type outputRow struct {
+		values []any
+		keys   []any
+	}
+	var output []outputRow
+	for _, group := range groups {
+		env := queryEnv{source: source, rows: group, aliases: make(map[string]any)}
+		values := make([]any, len(s.Items))
+		for i, item := range s.Items {
+			value, err := evalQuery(item.Expr, queryEnv{source: source, rows: group})
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+			env.aliases[item.Name] = value
+		}
+		if s.Having != nil {
+			keep, err := evalQueryBool(s.Having, env, "HAVING")
+			if err != nil {
+				return nil, err
+			}
+			if !keep {
+				continue
+			}
+		}
+		keys := make([]any, len(s.OrderBy))
+		for i, order := range s.OrderBy {
+			value, err := evalQuery(order.Expr, env)
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = value
+		}
+		output = append(output, outputRow{values: values, keys: keys})
+	}
+
+	sort.SliceStable(output, func(i, j int) bool {
+		for k, order := range s.OrderBy {
+			c := compareQueryValues(output[i].keys[k], output[j].keys[k])
+			if c == 0 {
+				continue
+			}
+			if order.Descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	if s.Limit >= 0 && len(output) > s.Limit {
+		output = output[:s.Limit]
+	}
+
+	result := &queryResult{}
+	for _, item := range s.Items {
+		result.Columns = append(result.Columns, item.Name)
+	}
+	for _, row := range output {
+		result.Rows = append(result.Rows, row.values)
+	}
+	return result, nil
+}
+
+func evalQueryBool(expr queryExpr, env queryEnv, clause string) (bool, error) {
+	value, err := evalQuery(expr, env)
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s harus menghasilkan kondisi benar/salah, bukan %s", clause, formatQueryValue(value, true))
+	}
+	return result, nil
+}
+
+func evalQuery(expr queryExpr, env queryEnv) (any, error) {
+	switch e := expr.(type) {
+	case queryLiteral:
+		return e.Value, nil
+
+	case queryColumnRef:
+		if value, ok := env.aliases[e.Name]; ok {
+			return value, nil
+		}
+		if len(env.rows) == 0 {
+			return nil, nil
+		}
+		return env.rows[0].Values[e.Name], nil
+
+	case queryCall:
+		if isAggregateCall(e) {
+			if e.Star {
+				return float64(len(env.rows)), nil
+			}
+			values := make([]float64, 0, len(env.rows))
+			for _, row := range env.rows {
+				value, err := evalQuery(e.Args[0], queryEnv{source: env.source, rows: []queryRow{row}})
+				if err != nil {
+					return nil, err
+				}
+				number, err := queryNumber(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s(): %w", e.Name, err)
+				}
+				values = append(values, number)
+			}
+			return aggregateValues(values, e.Name)
+		}
+		args := make([]any, len(e.Args))
+		for i, arg := range e.Args {
+			value, err := evalQuery(arg, env)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = value
+		}
+		if len(env.rows) == 0 {
+			return nil, nil
+		}
+		function, _ := env.source.function(e.Name)
+		value, err := function.Call(env.rows[0], args)
+		if err != nil {
+			return nil, fmt.Errorf("%s(): %w", e.Name, err)
+		}
+		return value, nil
+
+	case queryUnary:
+		operand, err := evalQuery(e.Operand, env)
+		if err != nil {
+			return nil, err
+		}
+		if e.Op == "NOT" {
+			value, ok := operand.(bool)
+			if !ok {
+				return nil, fmt.Errorf("NOT membutuhkan kondisi, bukan %s", formatQueryValue(operand, true))
+			}
+			return !value, nil
+		}
+		number, err := queryNumber(operand)
+		return -number, err
+
+	case queryIn:
+		operand, err := evalQuery(e.Operand, env)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range e.List {
+			value, err := evalQuery(item, env)
+			if err != nil {
+				return nil, err
+			}
+			if compareQueryValues(operand, value) == 0 {
+				return !e.Negate, nil
+			}
+		}
+		return e.Negate, nil
+
+	case queryBinary:
+		left, err := evalQuery(e.Left, env)
+		if err != nil {
+			return nil, err
+		}
+		if e.Op == "AND" || e.Op == "OR" {
+			l, ok := left.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%s membutuhkan kondisi, bukan %s", e.Op, formatQueryValue(left, true))
+			}
+			if (e.Op == "AND" && !l) || (e.Op == "OR" && l) {
+				return l, nil
+			}
+			right, err := evalQuery(e.Right, env)
+			if err != nil {
+				return nil, err
+			}
+			r, ok := right.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%s membutuhkan kondisi, bukan %s", e.Op, formatQueryValue(right, true))
+			}
+			return r, nil
+		}
+
+		right, err := evalQuery(e.Right, env)
+		if err != nil {
+			return nil, err
+		}
+		switch e.Op {
+		case "=":
+			return compareQueryValues(left, right) == 0, nil
+		case "!=":
+			return compareQueryValues(left, right) != 0, nil
+		case "<":
+			return compareQueryValues(left, right) < 0, nil
+		case "<=":
+			return compareQueryValues(left, right) <= 0, nil
+		case ">":
+			return compareQueryValues(left, right) > 0, nil
+		case ">=":
+			return compareQueryValues(left, right) >= 0, nil
+		case "LIKE":
+			return matchQueryLike(fmt.Sprint(left), fmt.Sprint(right)), nil
+		}
+
+		l, err := queryNumber(left)
+		if err != nil {
+			return nil, fmt.Errorf("operator %s: %w", e.Op, err)
+		}
+		r, err := queryNumber(right)
+		if err != nil {
+			return nil, fmt.Errorf("operator %s: %w", e.Op, err)
+		}
+		switch e.Op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/":
+			if r == 0 {
+				return nil, fmt.Errorf("operator %s: pembagian dengan nol", e.Op)
+			}
+			return l / r, nil
+		}
+	}
+	return nil, fmt.Errorf("ekspresi tidak didukung: %v", expr)
+}
+
+func queryNumber(value any) (float64, error) {
+	if number, ok := value.(float64); ok {
+		return number, nil
+	}
+	return 0, fmt.Errorf("nilai %q bukan angka", formatQueryValue(value, true))
+}
+
+func queryYearArg(value any) (int, error) {
+	year, err := queryNumber(value)
+	if err != nil || year != math.Trunc(year) {
+		return 0, fmt.Errorf("tahun harus bilangan bulat, bukan %s", formatQueryValue(value, true))
+	}
+	return int(year), nil
+}
+
+// yearArg sama dengan queryYearArg dan juga menolak tahun di luar rentang
+// data baris.
+func (row queryRow) yearArg(value any) (int, error) {
+	year, err := queryYearArg(value)
+	if err != nil {
+		return 0, err
+	}
+	if len(row.Years) > 0 && (year < row.Years[0] || year > row.Years[len(row.Years)-1]) {
+		return 0, fmt.Errorf("tahun %d di luar rentang data %d-%d", year, row.Years[0], row.Years[len(row.Years)-1])
+	}
+	return year, nil
+}
+
+// compareQueryValues membandingkan angka secara numerik dan teks tanpa
+// membedakan huruf besar/kecil; nilai kosong (nil) selalu paling kecil.
+func compareQueryValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToUpper(fmt.Sprint(a)), strings.ToUpper(fmt.Sprint(b)))
+}
+
+func matchQueryLike(value, pattern string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String()).MatchString(value)
+}
+
+// --- Keluaran ---
+
+func writeQueryResult(out io.Writer, result *queryResult, format string) error {
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(out)
+		writer.Write(result.Columns)
+		for _, row := range result.Rows {
+			record := make([]string, len(row))
+			for i, value := range row {
+				record[i] = formatQueryValue(value, false)
+			}
+			writer.Write(record)
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case "json":
+		var buffer bytes.Buffer
+		buffer.WriteString("[")
+		for i, row := range result.Rows {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString("\n  {")
+			for j, value := range row {
+				if j > 0 {
+					buffer.WriteString(", ")
+				}
+				if number, ok := value.(float64); ok && (math.IsNaN(number) || math.IsInf(number, 0)) {
+					value = nil
+				}
+				key, _ := json.Marshal(result.Columns[j])
+				encoded, err := json.Marshal(value)
+				if err != nil {
+					return err
+				}
+				buffer.Write(key)
+				buffer.WriteString(": ")
+				buffer.Write(encoded)
+			}
+			buffer.WriteString("}")
+		}
+		buffer.WriteString("\n]\n")
+		_, err := out.Write(buffer.Bytes())
+		return err
+	}
+
+	// Tabel rata: angka rata kanan, teks rata kiri.
+	cells := make([][]string, len(result.Rows))
+	widths := make([]int, len(result.Columns))
+	numeric := make([]bool, len(result.Columns))
+	for i, column := range result.Columns {
+		widths[i] = len([]rune(column))
+		numeric[i] = len(result.Rows) > 0
+	}
+	for r, row := range result.Rows {
+		cells[r] = make([]string, len(row))
+		for i, value := range row {
+			cells[r][i] = formatQueryValue(value, true)
+			widths[i] = max(widths[i], len([]rune(cells[r][i])))
+			if _, ok := value.(float64); !ok && value != nil {
+				numeric[i] = false
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	writeLine := func(values []string) {
+		for i, value := range values {
+			padding := strings.Repeat(" ", widths[i]-len([]rune(value)))
+			if i > 0 {
+				buffer.WriteString("  ")
+			}
+			if numeric[i] {
+				buffer.WriteString(padding + value)
+			} else if i < len(values)-1 {
+				buffer.WriteString(value + padding)
+			} else {
+				buffer.WriteString(value)
+			}
+		}
+		buffer.WriteString("\n")
+	}
+	writeLine(result.Columns)
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("-", width)
+	}
+	writeLine(separators)
+	for _, row := range cells {
+		writeLine(row)
+	}
+	fmt.Fprintf(&buffer, "(%d baris)\n", len(result.Rows))
+	_, err := out.Write(buffer.Bytes())
+	return err
+}
+
+// formatQueryValue menampilkan bilangan bulat tanpa desimal; di tabel
+// bilangan pecahan dibulatkan dua desimal, di CSV ditulis presisi penuh.
+func formatQueryValue(value any, rounded bool) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatFloat(v, 'f', 0, 64)
+		}
+		if rounded {
+			return strconv.FormatFloat(v, 'f', 2, 64)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMain membuang pesan progres agar keluaran go test tetap bersih.
+func TestMain(m *testing.M) {
+	progressOutput = io.Discard
+	os.Exit(m.Run())
+}
+
+var queryTestSource = &querySource{
+	Name: "uji",
+	Columns: []queryColumnInfo{
+		{"kabupaten", "nama kabupaten"},
+		{"provinsi", "nama provinsi"},
+		{"area", "area 2022"},
+	},
+	Functions: areaQueryFunctions(),
+}
+
+func queryTestRows() []queryRow {
+	years := []int{2020, 2021, 2022}
+	row := func(regency, province string, areas ...float64) queryRow {
+		yearly := make(map[int]float64)
+		for i, area := range areas {
+			if area > 0 {
+				yearly[years[i]] = area
+			}
+		}
+		return queryRow{
+			Values: map[string]any{"kabupaten": regency, "provinsi": province, "area": areas[len(areas)-1]},
+			Yearly: map[string]map[int]float64{"area": yearly},
+			Years:  years,
+		}
+	}
+	return []queryRow{
+		row("KAMPAR", "RIAU", 100, 150, 200),
+		row("SIAK", "RIAU", 0, 40, 50),
+		row("KETAPANG", "KALIMANTAN BARAT", 80, 90, 120),
+		row("SAMBAS", "KALIMANTAN BARAT", 30, 30, 30),
+		row("ACEH TAMIANG", "ACEH", 60, 55, 0),
+	}
+}
+
+func runTestQuery(text string) (*queryResult, error) {
+	statement, err := parseQuery(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := statement.check(queryTestSource); err != nil {
+		return nil, err
+	}
+	return statement.execute(queryTestSource, queryTestRows())
+}
+
+func TestQueryEvaluation(t *testing.T) {
+	tests := []struct {
+		query   string
+		columns []string
+		rows    [][]any
+	}{
+		{"SELECT 1 + 2 * 3 AS x, (1 + 2) * 3 AS y, -4 / 2 AS z FROM uji LIMIT 1",
+			[]string{"x", "y", "z"}, [][]any{{7.0, 9.0, -2.0}}},
+		{"SELECT kabupaten FROM uji WHERE provinsi LIKE 'kalimantan%' AND NOT kabupaten = 'sambas'",
+			[]string{"kabupaten"}, [][]any{{"KETAPANG"}}},
+		{"SELECT kabupaten FROM uji WHERE provinsi IN ('aceh', 'Riau') AND area(2020) > 0 ORDER BY kabupaten",
+			[]string{"kabupaten"}, [][]any{{"ACEH TAMIANG"}, {"KAMPAR"}}},
+		{"select kabupaten, tambah(2020, 2022) as tambah, pertumbuhan(2020, 2022) as tumbuh from uji where area > 0 order by tambah desc limit 2",
+			[]string{"kabupaten", "tambah", "tumbuh"}, [][]any{{"KAMPAR", 100.0, 100.0}, {"SIAK", 50.0, 0.0}}},
+		{"SELECT provinsi, count(*) AS n, sum(area) AS total, mean(area(2021)) AS rata FROM uji GROUP BY provinsi HAVING n > 1 ORDER BY total DESC",
+			[]string{"provinsi", "n", "total", "rata"}, [][]any{{"RIAU", 2.0, 250.0, 95.0}, {"KALIMANTAN BARAT", 2.0, 150.0, 60.0}}},
+		{"SELECT min(area) AS terkecil, max(area(2020)) AS terbesar, count(*) AS n FROM uji WHERE area > 1000",
+			[]string{"terkecil", "terbesar", "n"}, [][]any{{0.0, 0.0, 0.0}}},
+		{"SELECT round(area / 3, 1) AS sepertiga, abs(area(2020) - area(2022)) AS selisih FROM uji WHERE kabupaten = 'kampar'",
+			[]string{"sepertiga", "selisih"}, [][]any{{66.7, 100.0}}},
+	}
+	for _, test := range tests {
+		result, err := runTestQuery(test.query)
+		if err != nil {
+			t.Errorf("%s: error %v", test.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(result.Columns, test.columns) || !reflect.DeepEqual(result.Rows, test.rows) {
+			t.Errorf("%s:\n  didapat %v %v\n  seharusnya %v %v", test.query, result.Columns, result.Rows, test.columns, test.rows)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct{ query, message string }{
+		{"SELECT area / 0 FROM uji", "pembagian dengan nol"},
+		{"SELECT area(2022) / area(2020) FROM uji", "pembagian dengan nol"},
+		{"SELECT area(1) FROM uji", "di luar rentang data 2020-2022"},
+		{"SELECT tambah(2020, 2030) FROM uji", "di luar rentang data"},
+		{"SELECT area(2021.5) FROM uji", "bilangan bulat"},
+		{"SELECT luas FROM uji", "kolom tidak dikenal"},
+		{"SELECT kabupaten FROM uji WHERE sum(area) > 0", "tidak boleh dipakai di WHERE"},
+		{"SELECT sum(count(*)) FROM uji", "bersarang"},
+		{"SELECT area(2020, 2021) FROM uji", "jumlah argumen"},
+		{"SELECT kabupaten FROM uji WHERE area", "benar/salah"},
+		{"SELECT kabupaten + 1 FROM uji", "bukan angka"},
+		{"SELECT provinsi, area FROM uji GROUP BY provinsi", `kolom "area" di SELECT harus tercantum di GROUP BY`},
+		{"SELECT provinsi, sum(area) AS total FROM uji GROUP BY provinsi ORDER BY kabupaten", `kolom "kabupaten" di ORDER BY`},
+		{"SELECT count(*) AS n FROM uji HAVING area > 0", `kolom "area" di HAVING`},
+		{"SELECT provinsi, round(area(2020)) FROM uji GROUP BY provinsi", "area() di SELECT harus tercantum di GROUP BY"},
+		{"SELECT * FROM uji GROUP BY provinsi", "SELECT *"},
+		{"SELECT pangsa(2022) FROM uji", "fungsi tidak dikenal pangsa()"},
+		{"SELECT round(area, 400) FROM uji", "digit harus bilangan bulat 0-15"},
+		{"SELECT round(area, 1.5) FROM uji", "digit harus bilangan bulat 0-15"},
+		{"SELECT kabupaten uji", ""},
+	}
+	for _, test := range tests {
+		_, err := runTestQuery(test.query)
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("%s: error %v, seharusnya memuat %q", test.query, err, test.message)
+		}
+	}
+}
+
+func TestMatchQueryLike(t *testing.T) {
+	for _, test := range []struct {
+		value, pattern string
+		want           bool
+	}{
+		{"KALIMANTAN BARAT", "kalimantan%", true},
+		{"KALIMANTAN BARAT", "%barat", true},
+		{"RIAU", "r_au", true},
+		{"RIAU", "r_u", false},
+		{"ACEH", "%", true},
+		{"50% AREA", "50%", true},
+	} {
+		if got := matchQueryLike(test.value, test.pattern); got != test.want {
+			t.Errorf("%q LIKE %q = %v, seharusnya %v", test.value, test.pattern, got, test.want)
+		}
+	}
+}
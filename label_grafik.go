@@ -0,0 +1,241 @@
+package main
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"slices"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Label scatter plot ditempatkan secara greedy: label berprioritas tertinggi
+// dipasang lebih dulu pada posisi kandidat di sekeliling titiknya (8 arah,
+// beberapa cincin jarak). Posisi yang menabrak label lain, titik lain atau
+// tepi area plot dilewati; label di cincin luar diberi garis penunjuk, dan
+// label yang tidak mendapat tempat sama sekali tidak digambar.
+const (
+	labelRings          = 8
+	labelOutlierZ       = 2.5
+	labelObstacleRadius = 4
+)
+
+var labelModes = []string{"semua", "top", "outlier"}
+
+var labelDirections = [][2]float64{
+	{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1},
+}
+
+type labelLayer struct {
+	Points      plotter.XYs
+	Labels      []string
+	Priority    []float64
+	Radii       []vg.Length
+	Obstacles   plotter.XYs
+	ObstacleRad []vg.Length
+	TextStyle   text.Style
+	LeaderStyle draw.LineStyle
+
+	selected []int
+}
+
+// newLabelLayer memilih label sesuai mode label grafik (semua, top N
+// berdasarkan priority, atau outlier) dan memakai semua titik sebagai
+// penghalang.
+func newLabelLayer(points plotter.XYs, labels []string, priority []float64) (*labelLayer, error) {
+	if len(points) != len(labels) || len(points) != len(priority) {
+		return nil, errors.New("jumlah titik, label dan prioritas label tidak sama")
+	}
+
+	layer := &labelLayer{
+		Obstacles: points,
+		TextStyle: text.Style{
+			Color:   color.Black,
+			Font:    font.From(plotter.DefaultFont, plotter.DefaultFontSize),
+			Handler: plot.DefaultTextHandler,
+		},
+		LeaderStyle: draw.LineStyle{Color: color.Gray{Y: 120}, Width: vg.Points(0.5)},
+	}
+	layer.selected = selectLabels(points, priority)
+	for _, i := range layer.selected {
+		layer.Points = append(layer.Points, points[i])
+		layer.Labels = append(layer.Labels, labels[i])
+		layer.Priority = append(layer.Priority, priority[i])
+	}
+	layer.setRadii(nil)
+	return layer, nil
+}
+
+// setRadii memberi ukuran glyph per titik (urutan sama dengan titik yang
+// diberikan ke newLabelLayer) agar label tidak menimpa bubble besar; nil
+// berarti semua titik memakai labelObstacleRadius.
+func (l *labelLayer) setRadii(radii []vg.Length) {
+	l.ObstacleRad = make([]vg.Length, len(l.Obstacles))
+	for i := range l.ObstacleRad {
+		l.ObstacleRad[i] = vg.Points(labelObstacleRadius)
+		if radii != nil {
+			l.ObstacleRad[i] = radii[i]
+		}
+	}
+	l.Radii = make([]vg.Length, len(l.selected))
+	for i, index := range l.selected {
+		l.Radii[i] = l.ObstacleRad[index]
+	}
+}
+
+func selectLabels(points plotter.XYs, priority []float64) []int {
+	indices := make([]int, len(points))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	switch chartSettings.LabelMode {
+	case "top":
+		sort.SliceStable(indices, func(a, b int) bool { return priority[indices[a]] > priority[indices[b]] })
+		if len(indices) > chartSettings.LabelTop {
+			indices = indices[:chartSettings.LabelTop]
+		}
+	case "outlier":
+		xs := make([]float64, len(points))
+		ys := make([]float64, len(points))
+		for i, point := range points {
+			xs[i], ys[i] = point.X, point.Y
+		}
+		xz, yz := robustZScores(xs), robustZScores(ys)
+		indices = slices.DeleteFunc(indices, func(i int) bool {
+			return math.Abs(xz[i]) <= labelOutlierZ && math.Abs(yz[i]) <= labelOutlierZ
+		})
+	}
+	return indices
+}
+
+// robustZScores memakai median dan MAD (diskalakan 1.4826) agar satu provinsi
+// raksasa tidak menyembunyikan outlier lain; bila MAD nol dipakai simpangan
+// baku biasa.
+func robustZScores(values []float64) []float64 {
+	sorted := slices.Clone(values)
+	sort.Float64s(sorted)
+	median := percentile(sorted, 50)
+
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	sort.Float64s(deviations)
+	scale := percentile(deviations, 50) * 1.4826
+	if scale == 0 {
+		scale = calculateVolatility(values)
+	}
+
+	scores := make([]float64, len(values))
+	if scale == 0 {
+		return scores
+	}
+	for i, v := range values {
+		scores[i] = (v - median) / scale
+	}
+	return scores
+}
+
+func (l *labelLayer) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+
+	var taken []vg.Rectangle
+	for i, point := range l.Obstacles {
+		center := vg.Point{X: trX(point.X), Y: trY(point.Y)}
+		radius := vg.Points(labelObstacleRadius)
+		if i < len(l.ObstacleRad) {
+			radius = l.ObstacleRad[i]
+		}
+		taken = append(taken, vg.Rectangle{
+			Min: vg.Point{X: center.X - radius, Y: center.Y - radius},
+			Max: vg.Point{X: center.X + radius, Y: center.Y + radius},
+		})
+	}
+
+	order := make([]int, len(l.Points))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return l.Priority[order[a]] > l.Priority[order[b]] })
+
+	for _, i := range order {
+		anchor := vg.Point{X: trX(l.Points[i].X), Y: trY(l.Points[i].Y)}
+		if !c.Contains(anchor) {
+			continue
+		}
+
+		bounds := l.TextStyle.Rectangle(l.Labels[i])
+		box, ring, ok := findLabelPosition(c.Rectangle, anchor, bounds.Size(), l.Radii[i]+vg.Points(1), taken)
+		if !ok {
+			continue
+		}
+		taken = append(taken, box)
+
+		if ring > 0 {
+			end := vg.Point{
+				X: max(box.Min.X, min(anchor.X, box.Max.X)),
+				Y: max(box.Min.Y, min(anchor.Y, box.Max.Y)),
+			}
+			c.StrokeLine2(l.LeaderStyle, anchor.X, anchor.Y, end.X, end.Y)
+		}
+		c.FillText(l.TextStyle, box.Min.Sub(bounds.Min), l.Labels[i])
+	}
+}
+
+// findLabelPosition mencoba kandidat cincin demi cincin dan mengembalikan
+// kotak label pertama yang tidak bertabrakan beserta indeks cincinnya.
+func findLabelPosition(area vg.Rectangle, anchor vg.Point, size vg.Point, gap vg.Length, taken []vg.Rectangle) (vg.Rectangle, int, bool) {
+	step := size.Y * 1.5
+	for ring := 0; ring < labelRings; ring++ {
+		distance := gap + vg.Length(ring)*step
+		for _, direction := range labelDirections {
+			dx, dy := direction[0], direction[1]
+			if dx != 0 && dy != 0 {
+				dx, dy = dx*math.Sqrt2/2, dy*math.Sqrt2/2
+			}
+
+			var origin vg.Point
+			switch {
+			case direction[0] > 0:
+				origin.X = anchor.X + distance*vg.Length(dx)
+			case direction[0] < 0:
+				origin.X = anchor.X + distance*vg.Length(dx) - size.X
+			default:
+				origin.X = anchor.X - size.X/2
+			}
+			switch {
+			case direction[1] > 0:
+				origin.Y = anchor.Y + distance*vg.Length(dy)
+			case direction[1] < 0:
+				origin.Y = anchor.Y + distance*vg.Length(dy) - size.Y
+			default:
+				origin.Y = anchor.Y - size.Y/2
+			}
+
+			box := vg.Rectangle{Min: origin, Max: origin.Add(size)}
+			if !rectangleInside(box, area) {
+				continue
+			}
+			if !slices.ContainsFunc(taken, func(r vg.Rectangle) bool { return rectanglesOverlap(box, r) }) {
+				return box, ring, true
+			}
+		}
+	}
+	return vg.Rectangle{}, 0, false
+}
+
+func rectangleInside(inner, outer vg.Rectangle) bool {
+	return inner.Min.X >= outer.Min.X && inner.Min.Y >= outer.Min.Y &&
+		inner.Max.X <= outer.Max.X && inner.Max.Y <= outer.Max.Y
+}
+
+func rectanglesOverlap(a, b vg.Rectangle) bool {
+	return a.Min.X < b.Max.X && b.Min.X < a.Max.X && a.Min.Y < b.Max.Y && b.Min.Y < a.Max.Y
+}
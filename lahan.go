@@ -0,0 +1,463 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Tabel luas lahan opsional per provinsi dan kabupaten. Kolom wajib province
+// dan land_area_ha; region_trase_id dan suitable_area_ha (lahan sesuai atau
+// non-hutan) opsional. Baris tanpa region_trase_id adalah luas provinsi;
+// provinsi tanpa baris sendiri memakai jumlah kabupatennya (lahan sesuai
+// hanya bila diisi untuk semua kabupatennya). Kapasitas adalah lahan sesuai
+// bila diisi, selain itu luas wilayah.
+const landAreaFile = "luas_lahan.csv"
+
+// Ambang pemakaian kapasitas (%) untuk status lahan.
+const (
+	landNearSaturation = 75.0
+	landSaturated      = 90.0
+)
+
+// landSaturationHorizon membatasi perkiraan tahun jenuh; laju ekspansi yang
+// sangat lambat tidak menghasilkan tahun jenuh.
+const landSaturationHorizon = 2100
+
+// landTrendYears adalah jumlah tahun terakhir yang dipakai untuk laju
+// ekspansi saat memperkirakan tahun jenuh.
+const landTrendYears = 5
+
+var landIntensityClasses = []float64{5, 15, 30, 50}
+
+type LandArea struct {
+	Total    float64
+	Suitable float64
+}
+
+type LandAreaTable struct {
+	Provinces map[string]LandArea
+	Regencies map[string]LandArea
+}
+
+// LandIntensity adalah intensitas lahan satu provinsi atau kabupaten: pangsa
+// luas wilayah yang ditanami per tahun, pemakaian kapasitas dan sisa lahan
+// 2022, serta perkiraan tahun kapasitas habis pada laju ekspansi terakhir
+// (0 bila tidak sedang berekspansi atau melewati landSaturationHorizon).
+type LandIntensity struct {
+	Level          CubeLevel
+	Key            string
+	Name           string
+	Province       string
+	LandArea       float64
+	SuitableArea   float64
+	Planted2022    float64
+	Shares         map[int]float64
+	Share2003      float64
+	Share2022      float64
+	CapacityUse    float64
+	Headroom       float64
+	ExpansionRate  float64
+	SaturationYear int
+	Status         string
+	Rank           int
+}
+
+type LandUseAnalysis struct {
+	Provinces []LandIntensity
+	Regencies []LandIntensity
+}
+
+// loadLandAreas mengembalikan nil tanpa error bila file tidak ada.
+func loadLandAreas() (*LandAreaTable, error) {
+	records, err := readOptionalCSV(landAreaFile)
+	if err != nil || records == nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%s tidak memiliki baris data", landAreaFile)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"province", "land_area_ha"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("kolom %q tidak ada di header %s", required, landAreaFile)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	table := &LandAreaTable{
+		Provinces: make(map[string]LandArea),
+		Regencies: make(map[string]LandArea),
+	}
+	regencyTotals := make(map[string]LandArea)
+	missingSuitable := make(map[string]bool)
+	for line, record := range records[1:] {
+		total, err := strconv.ParseFloat(field(record, "land_area_ha"), 64)
+		if err != nil || total <= 0 {
+			return nil, fmt.Errorf("%s baris %d: land_area_ha tidak valid %q", landAreaFile, line+2, field(record, "land_area_ha"))
+		}
+		land := LandArea{Total: total}
+		if value := field(record, "suitable_area_ha"); value != "" {
+			if land.Suitable, err = strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("%s baris %d: suitable_area_ha tidak valid %q", landAreaFile, line+2, value)
+			}
+		}
+
+		province := strings.ToUpper(field(record, "province"))
+		if regency := field(record, "region_trase_id"); regency != "" {
+			table.Regencies[regency] = land
+			current := regencyTotals[province]
+			regencyTotals[province] = LandArea{Total: current.Total + land.Total, Suitable: current.Suitable + land.Suitable}
+			missingSuitable[province] = missingSuitable[province] || land.Suitable <= 0
+		} else {
+			table.Provinces[province] = land
+		}
+	}
+	for province, land := range regencyTotals {
+		if _, ok := table.Provinces[province]; !ok {
+			if missingSuitable[province] {
+				land.Suitable = 0
+			}
+			table.Provinces[province] = land
+		}
+	}
+
+	fmt.Fprintf(progressOutput, "🏞️  Luas lahan dibaca: %d provinsi, %d kabupaten dari %s\n", len(table.Provinces), len(table.Regencies), landAreaFile)
+	return table, nil
+}
+
+// analyzeLandUse menghitung intensitas lahan provinsi dan kabupaten yang
+// ada di tabel luas lahan; nil bila tabel tidak ada atau metrik utama bukan
+// luas.
+func analyzeLandUse(cube *DataCube, table *LandAreaTable) (*LandUseAnalysis, error) {
+	if table == nil {
+		fmt.Fprintf(progressOutput, "🏞️  Intensitas lahan dilewati: %s tidak ditemukan\n", landAreaFile)
+		return nil, nil
+	}
+	if metricUnit() != defaultMetric.Unit {
+		fmt.Fprintf(progressOutput, "⚠️  Intensitas lahan dilewati: metrik utama %s bukan luas (%s)\n", primaryMetric().Name, defaultMetric.Unit)
+		return nil, nil
+	}
+
+	analysis := &LandUseAnalysis{}
+	for _, level := range []CubeLevel{LevelProvince, LevelRegency} {
+		series, err := cube.Series(level, CubeFilter{})
+		if err != nil {
+			return nil, err
+		}
+		var intensities []LandIntensity
+		for _, s := range series {
+			land, ok := table.Regencies[s.Key]
+			if level == LevelProvince {
+				land, ok = table.Provinces[strings.ToUpper(s.Key)]
+			}
+			if ok {
+				intensities = append(intensities, newLandIntensity(s, land))
+			}
+		}
+		rankLandIntensities(intensities)
+		if level == LevelProvince {
+			analysis.Provinces = intensities
+		} else {
+			analysis.Regencies = intensities
+		}
+	}
+
+	fmt.Fprintf(progressOutput, "🏞️  Intensitas lahan dihitung: %d provinsi, %d kabupaten\n", len(analysis.Provinces), len(analysis.Regencies))
+	return analysis, nil
+}
+
+func newLandIntensity(series CubeSeries, land LandArea) LandIntensity {
+	intensity := LandIntensity{
+		Level:        series.Level,
+		Key:          series.Key,
+		Name:         series.Name,
+		Province:     series.Province,
+		LandArea:     land.Total,
+		SuitableArea: land.Suitable,
+		Planted2022:  series.Values[2022],
+		Shares:       make(map[int]float64, len(series.Values)),
+	}
+	for year, area := range series.Values {
+		intensity.Shares[year] = area / land.Total * 100
+	}
+	intensity.Share2003 = intensity.Shares[2003]
+	intensity.Share2022 = intensity.Shares[2022]
+
+	capacity := land.Total
+	if land.Suitable > 0 {
+		capacity = land.Suitable
+	}
+	intensity.CapacityUse = intensity.Planted2022 / capacity * 100
+	intensity.Headroom = math.Max(capacity-intensity.Planted2022, 0)
+
+	recent := make(map[int]float64)
+	for year := 2022 - landTrendYears + 1; year <= 2022; year++ {
+		if area, ok := series.Values[year]; ok {
+			recent[year] = area
+		}
+	}
+	if len(recent) >= 2 {
+		intensity.ExpansionRate, _ = linearTrend(recent)
+	}
+	if intensity.Headroom > 0 && intensity.ExpansionRate > 0 {
+		if year := 2022 + math.Ceil(intensity.Headroom/intensity.ExpansionRate); year <= landSaturationHorizon {
+			intensity.SaturationYear = int(year)
+		}
+	}
+
+	switch {
+	case intensity.CapacityUse >= landSaturated:
+		intensity.Status = "SATURATED"
+	case intensity.CapacityUse >= landNearSaturation:
+		intensity.Status = "NEAR SATURATION"
+	default:
+		intensity.Status = "AVAILABLE"
+	}
+	return intensity
+}
+
+// rankLandIntensities mengurutkan berdasarkan pangsa lahan 2022 tertinggi.
+func rankLandIntensities(intensities []LandIntensity) {
+	sort.Slice(intensities, func(i, j int) bool {
+		if intensities[i].Share2022 != intensities[j].Share2022 {
+			return intensities[i].Share2022 > intensities[j].Share2022
+		}
+		return intensities[i].Key < intensities[j].Key
+	})
+	for i := range intensities {
+		intensities[i].Rank = i + 1
+	}
+}
+
+// landWarnings memilih wilayah yang sudah mendekati jenuh atau diperkirakan
+// jenuh sebelum akhir proyeksi.
+func landWarnings(intensities []LandIntensity) []LandIntensity {
+	var warnings []LandIntensity
+	for _, intensity := range intensities {
+		if intensity.Status != "AVAILABLE" || (intensity.SaturationYear > 0 && intensity.SaturationYear <= projectionEndYear) {
+			warnings = append(warnings, intensity)
+		}
+	}
+	return warnings
+}
+
+func landRegionName(intensity LandIntensity) string {
+	if intensity.Level == LevelRegency {
+		return fmt.Sprintf("%s (%s)", intensity.Name, intensity.Province)
+	}
+	return intensity.Key
+}
+
+func formatSaturationYear(year int) string {
+	if year == 0 {
+		return "-"
+	}
+	return strconv.Itoa(year)
+}
+
+func writeLandUseSheets(f *excelize.File, analysis *LandUseAnalysis) {
+	if analysis == nil {
+		return
+	}
+
+	for _, group := range []struct {
+		sheet       string
+		intensities []LandIntensity
+	}{
+		{"Intensitas_Lahan", analysis.Provinces},
+		{"Intensitas_Lahan_Kabupaten", analysis.Regencies},
+	} {
+		if len(group.intensities) == 0 {
+			continue
+		}
+		sheet := tr(group.sheet)
+		f.NewSheet(sheet)
+
+		headers := []string{"Peringkat", "Wilayah", "Luas Wilayah (ha)", "Lahan Sesuai (ha)", "Area 2022 (ha)", "Pangsa Lahan 2003 (%)",
+			"Pangsa Lahan 2022 (%)", "Pemakaian Kapasitas (%)", "Sisa Lahan (ha)", "Laju Ekspansi (ha/tahun)", "Perkiraan Jenuh", "Status"}
+		for year := 2003; year <= 2022; year++ {
+			headers = append(headers, trf("Pangsa Lahan %d (%%)", year))
+		}
+		for i, header := range headers {
+			cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+			f.SetCellValue(sheet, cell, tr(header))
+		}
+		f.SetColWidth(sheet, "B", "B", 30)
+		f.SetColWidth(sheet, "C", "L", 18)
+
+		for i, intensity := range group.intensities {
+			row := i + 2
+			values := []interface{}{intensity.Rank, landRegionName(intensity), math.Round(intensity.LandArea), math.Round(intensity.SuitableArea),
+				math.Round(intensity.Planted2022), math.Round(intensity.Share2003*100) / 100, math.Round(intensity.Share2022*100) / 100,
+				math.Round(intensity.CapacityUse*10) / 10, math.Round(intensity.Headroom), math.Round(intensity.ExpansionRate),
+				formatSaturationYear(intensity.SaturationYear), tr(intensity.Status)}
+			for year := 2003; year <= 2022; year++ {
+				values = append(values, math.Round(intensity.Shares[year]*100)/100)
+			}
+			for j, value := range values {
+				cell, _ := excelize.CoordinatesToCellName(j+1, row)
+				f.SetCellValue(sheet, cell, value)
+			}
+		}
+	}
+}
+
+func landIntensityClass(share float64) int {
+	class := 0
+	for class < len(landIntensityClasses) && share >= landIntensityClasses[class] {
+		class++
+	}
+	return class
+}
+
+func landIntensityClassLabel(class int) string {
+	switch class {
+	case 0:
+		return fmt.Sprintf("< %.0f%%", landIntensityClasses[0])
+	case len(landIntensityClasses):
+		return fmt.Sprintf("≥ %.0f%%", landIntensityClasses[class-1])
+	}
+	return fmt.Sprintf("%.0f-%.0f%%", landIntensityClasses[class-1], landIntensityClasses[class])
+}
+
+// createLandIntensityMap menggambar lapisan peta intensitas lahan kabupaten
+// di koordinat centroidFile, dikelompokkan per kelas pangsa lahan 2022;
+// kabupaten yang mendekati jenuh diberi label.
+func createLandIntensityMap(analysis *LandUseAnalysis) error {
+	if analysis == nil || len(analysis.Regencies) == 0 {
+		return nil
+	}
+	centroids, err := loadCentroids()
+	if err != nil {
+		return err
+	}
+	if centroids == nil {
+		fmt.Fprintf(progressOutput, "🏞️  Peta intensitas lahan dilewati: %s tidak ditemukan\n", centroidFile)
+		return nil
+	}
+	positions := make(map[string]plotter.XY, len(centroids))
+	for _, centroid := range centroids {
+		positions[centroid.ID] = plotter.XY{X: centroid.Lon, Y: centroid.Lat}
+	}
+
+	p := plot.New()
+	p.Title.Text = tr("PETA INTENSITAS LAHAN KELAPA SAWIT KABUPATEN 2022")
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Bujur")
+	p.Y.Label.Text = tr("Lintang")
+
+	classes := make([]plotter.XYs, len(landIntensityClasses)+1)
+	var allPoints, labelPoints plotter.XYs
+	var labels []string
+	var priority []float64
+	for _, intensity := range analysis.Regencies {
+		point, ok := positions[intensity.Key]
+		if !ok {
+			continue
+		}
+		class := landIntensityClass(intensity.Share2022)
+		classes[class] = append(classes[class], point)
+		allPoints = append(allPoints, point)
+		if intensity.Status != "AVAILABLE" {
+			labelPoints = append(labelPoints, point)
+			labels = append(labels, intensity.Name)
+			priority = append(priority, intensity.CapacityUse)
+		}
+	}
+	if len(allPoints) == 0 {
+		fmt.Fprintf(progressOutput, "🏞️  Peta intensitas lahan dilewati: tidak ada kabupaten dengan centroid di %s\n", centroidFile)
+		return nil
+	}
+
+	for class, points := range classes {
+		if len(points) == 0 {
+			continue
+		}
+		scatter, err := plotter.NewScatter(points)
+		if err != nil {
+			return err
+		}
+		scatter.GlyphStyle.Color = chartTheme.Growth[class]
+		scatter.GlyphStyle.Radius = vg.Points(3 + float64(class))
+		scatter.GlyphStyle.Shape = draw.CircleGlyph{}
+		p.Add(scatter)
+		p.Legend.Add(landIntensityClassLabel(class), scatter)
+	}
+
+	if len(labelPoints) > 0 {
+		labelPlot, err := newLabelLayer(labelPoints, labels, priority)
+		if err != nil {
+			return err
+		}
+		labelPlot.Obstacles = allPoints
+		p.Add(labelPlot)
+	}
+
+	p.Add(plotter.NewGrid())
+	p.Legend.Top = true
+	p.Legend.Left = true
+
+	return saveChart(p, 16*vg.Inch, 8*vg.Inch, "peta_intensitas_lahan_kabupaten")
+}
+
+func buildLandUseReport(analysis *LandUseAnalysis) string {
+	if analysis == nil || len(analysis.Provinces)+len(analysis.Regencies) == 0 {
+		return ""
+	}
+
+	report := tr("\n### 🏞️ INTENSITAS PENGGUNAAN LAHAN\n\n")
+	report += trf("Pangsa lahan adalah area tertanam dibagi luas wilayah. Pemakaian kapasitas membandingkan area tertanam dengan lahan sesuai (atau luas wilayah bila tidak tersedia); status MENDEKATI JENUH mulai %.0f%% dan JENUH mulai %.0f%%. Perkiraan tahun jenuh memakai laju ekspansi %d tahun terakhir.\n\n",
+		landNearSaturation, landSaturated, landTrendYears)
+
+	if len(analysis.Provinces) > 0 {
+		report += tr("| Peringkat | Provinsi | Pangsa Lahan 2003 | Pangsa Lahan 2022 | Pemakaian Kapasitas | Sisa Lahan (ha) | Perkiraan Jenuh | Status |\n")
+		report += "|-----------|----------|-------------------|-------------------|---------------------|-----------------|-----------------|--------|\n"
+		for _, intensity := range analysis.Provinces {
+			report += fmt.Sprintf("| %d | %s | %.1f%% | %.1f%% | %.1f%% | %s | %s | %s |\n", intensity.Rank, intensity.Key,
+				intensity.Share2003, intensity.Share2022, intensity.CapacityUse, formatNumber(intensity.Headroom),
+				formatSaturationYear(intensity.SaturationYear), tr(intensity.Status))
+		}
+	}
+
+	if len(analysis.Regencies) > 0 {
+		report += tr("\n**10 kabupaten dengan pangsa lahan tertinggi (2022):**\n\n")
+		report += tr("| Peringkat | Kabupaten | Provinsi | Pangsa Lahan | Pemakaian Kapasitas | Status |\n")
+		report += "|-----------|-----------|----------|--------------|---------------------|--------|\n"
+		for i := 0; i < len(analysis.Regencies) && i < 10; i++ {
+			intensity := analysis.Regencies[i]
+			report += fmt.Sprintf("| %d | %s | %s | %.1f%% | %.1f%% | %s |\n", intensity.Rank, intensity.Name, intensity.Province,
+				intensity.Share2022, intensity.CapacityUse, tr(intensity.Status))
+		}
+	}
+
+	warnings := append(landWarnings(analysis.Provinces), landWarnings(analysis.Regencies)...)
+	if len(warnings) > 0 {
+		report += tr("\n**⚠️ Peringatan kejenuhan lahan:**\n\n")
+		for _, intensity := range warnings {
+			if intensity.Status != "AVAILABLE" {
+				report += trf("- %s: %.1f%% kapasitas lahan sudah ditanami (%s)\n", landRegionName(intensity), intensity.CapacityUse, tr(intensity.Status))
+			} else {
+				report += trf("- %s: pada laju ekspansi saat ini kapasitas lahan habis sekitar %d\n", landRegionName(intensity), intensity.SaturationYear)
+			}
+		}
+	}
+	return report
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Versi HTML dirender dari blok Markdown yang sama dengan PDF sehingga isi
+// ketiga format identik. Hanya subset Markdown yang dipakai laporan yang
+// didukung: judul, paragraf, bullet, tabel, garis, gambar, **tebal** dan
+// [tautan](url).
+const htmlReportStyle = `body{font-family:"Liberation Sans",Arial,sans-serif;max-width:960px;margin:2em auto;padding:0 1em;color:#222;line-height:1.5}
+h1,h2{text-align:center}h3{color:#145a32;border-bottom:1px solid #ccc;padding-bottom:.2em;margin-top:2em}
+table{border-collapse:collapse;margin:1em 0;font-size:.9em}th,td{border:1px solid #999;padding:.25em .6em;text-align:left}
+th{background:#dcebe1}img{max-width:100%}hr{border:0;border-top:1px solid #999}`
+
+var markdownLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+
+func renderMarkdownHTML(markdown, title string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"" + outputLanguage + "\">\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>" + html.EscapeString(title) + "</title>\n<style>\n" + htmlReportStyle + "\n</style>\n</head>\n<body>\n")
+
+	inList := false
+	for _, block := range parseMarkdownBlocks(markdown, strings.TrimSpace) {
+		if inList && block.Kind != "bullet" {
+			b.WriteString("</ul>\n")
+			inList = false
+		}
+
+		switch block.Kind {
+		case "heading":
+			tag := fmt.Sprintf("h%d", min(block.Level, 6))
+			b.WriteString("<" + tag + ">" + htmlInline(block.Text) + "</" + tag + ">\n")
+		case "paragraph":
+			b.WriteString("<p>" + htmlInline(block.Text) + "</p>\n")
+		case "bullet":
+			if !inList {
+				b.WriteString("<ul>\n")
+				inList = true
+			}
+			b.WriteString("<li>" + htmlInline(block.Text) + "</li>\n")
+		case "table":
+			b.WriteString("<table>\n")
+			for i, row := range block.Rows {
+				cell := "td"
+				if i == 0 {
+					cell = "th"
+				}
+				b.WriteString("<tr>")
+				for _, text := range row {
+					b.WriteString("<" + cell + ">" + htmlInline(text) + "</" + cell + ">")
+				}
+				b.WriteString("</tr>\n")
+			}
+			b.WriteString("</table>\n")
+		case "rule":
+			b.WriteString("<hr>\n")
+		case "image":
+			b.WriteString("<p><img src=\"" + html.EscapeString(block.Raw) + "\" alt=\"" + html.EscapeString(block.Text) + "\"></p>\n")
+		}
+	}
+	if inList {
+		b.WriteString("</ul>\n")
+	}
+
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// htmlInline meng-escape teks lalu mengubah **tebal** dan [tautan](url).
+func htmlInline(text string) string {
+	escaped := html.EscapeString(text)
+	parts := strings.Split(escaped, "**")
+	for i := 1; i < len(parts); i += 2 {
+		if i < len(parts)-1 {
+			parts[i] = "<strong>" + parts[i] + "</strong>"
+		} else {
+			parts[i] = "**" + parts[i]
+		}
+	}
+	return markdownLinkPattern.ReplaceAllString(strings.Join(parts, ""), `<a href="$2">$1</a>`)
+}
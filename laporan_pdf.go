@@ -0,0 +1,460 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"codeberg.org/go-fonts/liberation/liberationsansbold"
+	"codeberg.org/go-fonts/liberation/liberationsansregular"
+	"codeberg.org/go-pdf/fpdf"
+)
+
+// Laporan PDF dirender dari Markdown yang sama dengan laporan strategis
+// sehingga kedua format selalu memuat bagian yang identik. Grafik yang
+// relevan disisipkan di akhir bagiannya masing-masing; bagian dikenali dari
+// emoji judulnya sehingga pemetaan tetap berlaku untuk semua bahasa.
+const strategicReportPDFFile = "rekomendasi_strategis_provinsi_20tahun.pdf"
+
+const (
+	pdfMargin       = 15.0
+	pdfBottomMargin = 20.0
+	pdfLineHeight   = 5.0
+	pdfTableLine    = 4.0
+	pdfMaxChartH    = 200.0
+)
+
+var reportSectionCharts = []struct {
+	emoji  string
+	charts []string
+}{
+	{"📊", []string{"trend_nasional_20tahun"}},
+	{"📈", []string{"trend_pertumbuhan_provinsi_20tahun"}},
+	{"🏭", []string{"kurva_lorenz_2003_vs_2022"}},
+	{"🗺️", []string{"moran_scatter_kabupaten"}},
+	{"🧭", []string{"skenario_nasional_2030", "skenario_provinsi_2030"}},
+	{"🎲", []string{"monte_carlo_nasional_2035"}},
+	{"🎚️", []string{"tornado_sensitivitas"}},
+	{"🔀", []string{"bump_chart_peringkat_20tahun"}},
+	{"🥧", []string{"pangsa_pasar_stacked_20tahun"}},
+	{"📋", []string{"peta_heatmap_provinsi_20tahun"}},
+	{"🎯", []string{"matriks_investasi_provinsi_20tahun"}},
+	{"🚀", []string{"proyeksi_2030"}},
+}
+
+type markdownBlock struct {
+	Kind  string
+	Level int
+	Text  string
+	Raw   string
+	Rows  [][]string
+}
+
+type pdfTOCEntry struct {
+	Title string
+	Level int
+	Page  int
+}
+
+func createStrategicReportPDF(markdown string) error {
+	blocks := parseMarkdownBlocks(markdown, stripEmoji)
+
+	// Nomor halaman daftar isi baru diketahui setelah konten dirender, jadi
+	// dokumen dirender dua kali; jumlah entri sama sehingga halaman stabil.
+	_, toc, err := renderStrategicPDF(blocks, nil)
+	if err != nil {
+		return err
+	}
+	pdf, _, err := renderStrategicPDF(blocks, toc)
+	if err != nil {
+		return err
+	}
+
+	if err := pdf.OutputFileAndClose(strategicReportPDFFile); err != nil {
+		return fmt.Errorf("error menyimpan laporan PDF: %w", err)
+	}
+	fmt.Fprintln(progressOutput, "📄 Laporan strategis PDF berhasil dibuat:", strategicReportPDFFile)
+	return nil
+}
+
+// parseMarkdownBlocks memecah Markdown laporan menjadi blok; clean dipakai
+// untuk membersihkan teks (stripEmoji untuk PDF, TrimSpace untuk HTML).
+// Raw selalu berisi teks asli judul atau path gambar.
+func parseMarkdownBlocks(markdown string, clean func(string) string) []markdownBlock {
+	var blocks []markdownBlock
+	lines := strings.Split(markdown, "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " ")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			raw := strings.TrimSpace(trimmed[level:])
+			blocks = append(blocks, markdownBlock{Kind: "heading", Level: level, Text: clean(raw), Raw: raw})
+		case trimmed == "---":
+			blocks = append(blocks, markdownBlock{Kind: "rule"})
+		case strings.HasPrefix(trimmed, "|"):
+			var rows [][]string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				row := strings.TrimSpace(lines[i])
+				if strings.Trim(row, "|-: ") == "" {
+					continue
+				}
+				var cells []string
+				for _, cell := range strings.Split(strings.Trim(row, "|"), "|") {
+					cells = append(cells, clean(strings.TrimSpace(cell)))
+				}
+				rows = append(rows, cells)
+			}
+			i--
+			blocks = append(blocks, markdownBlock{Kind: "table", Rows: rows})
+		case strings.HasPrefix(trimmed, "![") && strings.HasSuffix(trimmed, ")") && strings.Contains(trimmed, "]("):
+			alt, path, _ := strings.Cut(trimmed[2:len(trimmed)-1], "](")
+			blocks = append(blocks, markdownBlock{Kind: "image", Text: clean(alt), Raw: path})
+		case strings.HasPrefix(trimmed, "- "):
+			blocks = append(blocks, markdownBlock{Kind: "bullet", Text: clean(trimmed[2:])})
+		default:
+			blocks = append(blocks, markdownBlock{Kind: "paragraph", Text: clean(trimmed)})
+		}
+	}
+
+	return blocks
+}
+
+// stripEmoji membuang karakter yang tidak tersedia di font Liberation.
+func stripEmoji(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r > 0xFFFF || (r >= 0x2600 && r <= 0x27BF) || r == 0xFE0F || r == 0x200D {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// newReportPDF menyiapkan dokumen A4 dengan font dan nomor halaman; halaman
+// sampul (halaman pertama) tidak diberi nomor bila cover true.
+func newReportPDF(cover bool) *fpdf.Fpdf {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("Liberation", "", liberationsansregular.TTF)
+	pdf.AddUTF8FontFromBytes("Liberation", "B", liberationsansbold.TTF)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfBottomMargin)
+	pdf.AliasNbPages("{nb}")
+	pdf.SetFooterFunc(func() {
+		if cover && pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-15)
+		pdf.SetFont("Liberation", "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 10, trf("Halaman %d dari {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+	return pdf
+}
+
+func renderStrategicPDF(blocks []markdownBlock, toc []pdfTOCEntry) (*fpdf.Fpdf, []pdfTOCEntry, error) {
+	pdf := newReportPDF(true)
+
+	title, subtitle := tr("LAPORAN STRATEGIS"), ""
+	var sections []pdfTOCEntry
+	for _, block := range blocks {
+		if block.Kind != "heading" {
+			continue
+		}
+		switch block.Level {
+		case 1:
+			title = block.Text
+		case 2:
+			subtitle = block.Text
+		default:
+			sections = append(sections, pdfTOCEntry{Title: block.Text, Level: block.Level - 3})
+		}
+	}
+	links := make([]int, len(sections))
+	for i := range links {
+		links[i] = pdf.AddLink()
+	}
+
+	writePDFCover(pdf, title, subtitle)
+	writePDFTableOfContents(pdf, sections, toc, links)
+
+	pdf.AddPage()
+	section := 0
+	var pendingCharts []string
+	for _, block := range blocks {
+		switch block.Kind {
+		case "heading":
+			if block.Level < 3 {
+				continue
+			}
+			if block.Level == 3 {
+				writePDFCharts(pdf, pendingCharts)
+				pendingCharts = chartsForSection(block.Raw)
+			}
+			writePDFHeading(pdf, block)
+			sections[section].Page = pdf.PageNo()
+			pdf.SetLink(links[section], -1, -1)
+			pdf.Bookmark(block.Text, block.Level-3, -1)
+			section++
+		default:
+			writePDFBlock(pdf, block, ".")
+		}
+	}
+	writePDFCharts(pdf, pendingCharts)
+
+	if pdf.Err() {
+		return nil, nil, fmt.Errorf("error membuat laporan PDF: %w", pdf.Error())
+	}
+	return pdf, sections, nil
+}
+
+// writePDFBlock menulis blok selain judul; path gambar relatif terhadap
+// baseDir.
+func writePDFBlock(pdf *fpdf.Fpdf, block markdownBlock, baseDir string) {
+	switch block.Kind {
+	case "paragraph":
+		pdf.SetFont("Liberation", "", 10)
+		writePDFRichText(pdf, block.Text)
+		pdf.Ln(pdfLineHeight + 1)
+	case "bullet":
+		pdf.SetFont("Liberation", "", 10)
+		pdf.SetLeftMargin(pdfMargin + 5)
+		pdf.SetX(pdfMargin + 1)
+		pdf.Write(pdfLineHeight, "•  ")
+		writePDFRichText(pdf, block.Text)
+		pdf.SetLeftMargin(pdfMargin)
+		pdf.Ln(pdfLineHeight + 1)
+	case "table":
+		writePDFTable(pdf, block.Rows)
+	case "rule":
+		pdf.Ln(2)
+		width, _ := pdf.GetPageSize()
+		pdf.Line(pdfMargin, pdf.GetY(), width-pdfMargin, pdf.GetY())
+		pdf.Ln(3)
+	case "image":
+		writePDFImage(pdf, filepath.Join(baseDir, block.Raw))
+	}
+}
+
+func writePDFCover(pdf *fpdf.Fpdf, title, subtitle string) {
+	pdf.AddPage()
+	pdf.SetY(90)
+	pdf.SetFont("Liberation", "B", 24)
+	pdf.MultiCell(0, 11, title, "", "C", false)
+	pdf.Ln(4)
+	pdf.SetFont("Liberation", "", 15)
+	pdf.MultiCell(0, 8, subtitle, "", "C", false)
+	pdf.Ln(30)
+	pdf.SetFont("Liberation", "", 11)
+	pdf.MultiCell(0, 6, "Palm Oil Analytics System\n"+time.Now().Format("2 January 2006"), "", "C", false)
+}
+
+func writePDFTableOfContents(pdf *fpdf.Fpdf, sections, pages []pdfTOCEntry, links []int) {
+	pdf.AddPage()
+	pdf.SetFont("Liberation", "B", 16)
+	pdf.CellFormat(0, 10, tr("DAFTAR ISI"), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	width, _ := pdf.GetPageSize()
+	for i, entry := range sections {
+		indent := float64(entry.Level) * 8
+		style, size := "B", 11.0
+		if entry.Level > 0 {
+			style, size = "", 10
+		}
+		pdf.SetFont("Liberation", style, size)
+
+		page := ""
+		if i < len(pages) {
+			page = fmt.Sprintf("%d", pages[i].Page)
+		}
+		pdf.SetX(pdfMargin + indent)
+		pdf.CellFormat(width-2*pdfMargin-indent-15, 7, entry.Title, "", 0, "L", false, links[i], "")
+		pdf.CellFormat(15, 7, page, "", 1, "R", false, links[i], "")
+	}
+}
+
+func writePDFHeading(pdf *fpdf.Fpdf, block markdownBlock) {
+	_, height := pdf.GetPageSize()
+	if pdf.GetY() > height-pdfBottomMargin-30 {
+		pdf.AddPage()
+	}
+
+	size := 14.0
+	if block.Level > 3 {
+		size = 11.5
+	}
+	pdf.Ln(3)
+	pdf.SetFont("Liberation", "B", size)
+	if block.Level == 3 {
+		pdf.SetTextColor(20, 90, 50)
+	}
+	pdf.MultiCell(0, size*0.5, block.Text, "", "L", false)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(2)
+}
+
+// writePDFRichText menulis satu baris Markdown dengan dukungan **tebal**.
+func writePDFRichText(pdf *fpdf.Fpdf, text string) {
+	for i, part := range strings.Split(text, "**") {
+		if part == "" {
+			continue
+		}
+		if i%2 == 1 {
+			pdf.SetFont("Liberation", "B", 10)
+		} else {
+			pdf.SetFont("Liberation", "", 10)
+		}
+		pdf.Write(pdfLineHeight, part)
+	}
+	pdf.SetFont("Liberation", "", 10)
+}
+
+func writePDFTable(pdf *fpdf.Fpdf, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	available := pageWidth - 2*pdfMargin
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	pdf.SetFont("Liberation", "B", 8)
+	widths := make([]float64, columns)
+	total := 0.0
+	for _, row := range rows {
+		for j, cell := range row {
+			widths[j] = max(widths[j], min(pdf.GetStringWidth(strings.ReplaceAll(cell, "**", ""))+4, available/2))
+		}
+	}
+	for _, w := range widths {
+		total += w
+	}
+	if total > available {
+		for j := range widths {
+			widths[j] = widths[j] / total * available
+		}
+	}
+
+	drawRow := func(row []string, header bool) {
+		style := ""
+		if header {
+			style = "B"
+		}
+		pdf.SetFont("Liberation", style, 8)
+
+		cells := make([][]string, columns)
+		lines := 1
+		for j := 0; j < columns; j++ {
+			text := ""
+			if j < len(row) {
+				text = strings.ReplaceAll(row[j], "**", "")
+			}
+			cells[j] = pdf.SplitText(text, widths[j])
+			lines = max(lines, len(cells[j]))
+		}
+		rowHeight := float64(lines)*pdfTableLine + 1
+
+		x, y := pdfMargin, pdf.GetY()
+		for j := 0; j < columns; j++ {
+			if header {
+				pdf.SetFillColor(220, 235, 225)
+				pdf.Rect(x, y, widths[j], rowHeight, "FD")
+			} else {
+				pdf.Rect(x, y, widths[j], rowHeight, "D")
+			}
+			for k, line := range cells[j] {
+				pdf.SetXY(x, y+0.5+float64(k)*pdfTableLine)
+				pdf.CellFormat(widths[j], pdfTableLine, line, "", 0, "L", false, 0, "")
+			}
+			x += widths[j]
+		}
+		pdf.SetXY(pdfMargin, y+rowHeight)
+	}
+
+	// Tabel dipaginasi manual: baris yang tidak muat memicu halaman baru dan
+	// header diulang agar setiap halaman tetap terbaca berdiri sendiri.
+	pdf.SetAutoPageBreak(false, pdfBottomMargin)
+	pdf.Ln(1)
+	drawRow(rows[0], true)
+	for _, row := range rows[1:] {
+		pdf.SetFont("Liberation", "", 8)
+		lines := 1
+		for j, cell := range row {
+			if j < columns {
+				lines = max(lines, len(pdf.SplitText(strings.ReplaceAll(cell, "**", ""), widths[j])))
+			}
+		}
+		if pdf.GetY()+float64(lines)*pdfTableLine+1 > pageHeight-pdfBottomMargin {
+			pdf.AddPage()
+			drawRow(rows[0], true)
+		}
+		drawRow(row, false)
+	}
+	pdf.SetAutoPageBreak(true, pdfBottomMargin)
+	pdf.Ln(4)
+}
+
+func chartsForSection(heading string) []string {
+	for _, section := range reportSectionCharts {
+		if strings.HasPrefix(heading, section.emoji) {
+			return section.charts
+		}
+	}
+	return nil
+}
+
+// isReportChart melaporkan apakah grafik name disisipkan ke laporan PDF.
+func isReportChart(name string) bool {
+	for _, section := range reportSectionCharts {
+		if slices.Contains(section.charts, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func writePDFCharts(pdf *fpdf.Fpdf, charts []string) {
+	for _, name := range charts {
+		writePDFImage(pdf, chartImagePath(name))
+	}
+}
+
+// writePDFImage menyisipkan PNG selebar halaman (dibatasi pdfMaxChartH);
+// file yang tidak ada dilewati.
+func writePDFImage(pdf *fpdf.Fpdf, path string) {
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+
+	info := pdf.RegisterImageOptions(path, fpdf.ImageOptions{ImageType: "PNG", ReadDpi: false})
+	if info == nil || info.Width() == 0 {
+		return
+	}
+	pageWidth, pageHeight := pdf.GetPageSize()
+	width := pageWidth - 2*pdfMargin
+	height := width * info.Height() / info.Width()
+	if height > pdfMaxChartH {
+		width = width * pdfMaxChartH / height
+		height = pdfMaxChartH
+	}
+
+	if pdf.GetY()+height > pageHeight-pdfBottomMargin {
+		pdf.AddPage()
+	}
+	pdf.ImageOptions(path, (pageWidth-width)/2, pdf.GetY()+2, width, height, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetY(pdf.GetY() + height + 6)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Laporan strategis dirender dari template text/template. Template bawaan
+// ikut dikompilasi lewat embed; file dengan nama yang sama di direktori
+// -template-dir menggantikan template bawaan tanpa perlu kompilasi ulang.
+// Urutan pemuatan: template bawaan, template bawaan <lang>/ untuk bahasa
+// selain id, lalu -template-dir dan -template-dir/<lang>/ sehingga template
+// pengguna selalu menang atas template bawaan.
+const (
+	reportTemplateRoot    = "laporan_strategis.md.tmpl"
+	reportTemplatePattern = "*.tmpl"
+)
+
+//go:embed templates/*.tmpl templates/en/*.tmpl
+var defaultReportTemplates embed.FS
+
+// ReportData adalah konteks data yang diteruskan ke template laporan.
+type ReportData struct {
+	GeneratedAt  string
+	StartYear    int
+	EndYear      int
+	RoadmapStart int
+	RoadmapEnd   int
+	Summary      ReportSummary
+	Models       []ProvinceModel
+	Trends       []NationalTrend
+	Decades      []DecadalAnalysis
+	Scenarios    []ScenarioResult
+	Groups       []ProvinceGroup
+	Sections     ReportSections
+}
+
+type ReportSummary struct {
+	TotalProvinces      int
+	HighGrowthProvinces int
+	PrimeProvinces      int
+	HasTrends           bool
+	TotalArea2003       float64
+	TotalArea2022       float64
+	TotalGrowth         float64
+	AverageGrowth       float64
+}
+
+type ProvinceGroup struct {
+	Name      string
+	Provinces []ProvinceModel
+}
+
+// ReportSections berisi bagian analisis lanjutan dalam bentuk Markdown jadi;
+// string kosong berarti analisisnya tidak dijalankan.
+type ReportSections struct {
+	Concentration  string
+	Spatial        string
+	Scenarios      string
+	MonteCarlo     string
+	ScoreBreakdown string
+	Sensitivity    string
+	RankMobility   string
+	MarketShare    string
+	Metrics        string
+	Yield          string
+	LandUse        string
+	Deforestation  string
+	Certification  string
+}
+
+var reportTemplateFuncs = template.FuncMap{
+	"tr":           tr,
+	"unit":         metricUnit,
+	"metric":       metricLabel,
+	"lower":        strings.ToLower,
+	"formatNumber": formatNumber,
+	"join":         strings.Join,
+	"upper":        strings.ToUpper,
+	"add": func(a, b int) int {
+		return a + b
+	},
+	"first": func(values []string) string {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	},
+}
+
+func loadReportTemplates(dir string) (*template.Template, error) {
+	tmpl, err := template.New(reportTemplateRoot).Funcs(reportTemplateFuncs).ParseFS(defaultReportTemplates, "templates/"+reportTemplatePattern)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template bawaan: %w", err)
+	}
+	if outputLanguage != defaultLanguage {
+		embedded, _ := fs.Glob(defaultReportTemplates, "templates/"+outputLanguage+"/"+reportTemplatePattern)
+		if len(embedded) > 0 {
+			if _, err := tmpl.ParseFS(defaultReportTemplates, embedded...); err != nil {
+				return nil, fmt.Errorf("error parsing template bawaan: %w", err)
+			}
+		}
+	}
+	if dir == "" {
+		return tmpl, nil
+	}
+
+	overrides, err := parseTemplateOverrides(tmpl, dir)
+	if err != nil {
+		return nil, err
+	}
+	if outputLanguage != defaultLanguage {
+		translated, err := parseTemplateOverrides(tmpl, filepath.Join(dir, outputLanguage))
+		if err != nil {
+			return nil, err
+		}
+		overrides += translated
+	}
+
+	if overrides > 0 {
+		fmt.Fprintf(progressOutput, "📝 %d template laporan dimuat dari %s\n", overrides, dir)
+	}
+	return tmpl, nil
+}
+
+func parseTemplateOverrides(tmpl *template.Template, dir string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, reportTemplatePattern))
+	if err != nil {
+		return 0, fmt.Errorf("error membaca direktori template: %w", err)
+	}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return 0, fmt.Errorf("error membaca template: %w", err)
+		}
+		if _, err := tmpl.New(filepath.Base(path)).Parse(string(content)); err != nil {
+			return 0, fmt.Errorf("error parsing template %s: %w", path, err)
+		}
+	}
+	return len(paths), nil
+}
+
+func renderReportTemplate(tmpl *template.Template, data ReportData) (string, error) {
+	var report strings.Builder
+	if err := tmpl.ExecuteTemplate(&report, reportTemplateRoot, data); err != nil {
+		return "", fmt.Errorf("error merender template laporan: %w", err)
+	}
+	return report.String(), nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func renderTestTemplate(t *testing.T, dir, name string) string {
+	t.Helper()
+	tmpl, err := loadReportTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := tmpl.ExecuteTemplate(&out, name, ReportData{RoadmapStart: 2025, RoadmapEnd: 2030}); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+// Template di -template-dir harus menang atas template bawaan, termasuk
+// template bawaan bahasa lain yang dimuat sesudah template dasar.
+func TestLoadReportTemplatesOverrideOrder(t *testing.T) {
+	defer func(language string) { outputLanguage = language }(outputLanguage)
+	outputLanguage = "en"
+
+	if got := renderTestTemplate(t, "", "rekomendasi"); !strings.Contains(got, "STRATEGIC RECOMMENDATIONS 2025-2030") {
+		t.Fatalf("tanpa -template-dir seharusnya memakai template bawaan en, didapat %q", got)
+	}
+
+	dir := t.TempDir()
+	override := filepath.Join(dir, "rekomendasi.md.tmpl")
+	if err := os.WriteFile(override, []byte(`{{define "rekomendasi"}}umum{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := renderTestTemplate(t, dir, "rekomendasi"); got != "umum" {
+		t.Errorf("template pengguna tingkat atas = %q, seharusnya menggantikan template bawaan en", got)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "en"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	translated := filepath.Join(dir, "en", "rekomendasi.md.tmpl")
+	if err := os.WriteFile(translated, []byte(`{{define "rekomendasi"}}english{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := renderTestTemplate(t, dir, "rekomendasi"); got != "english" {
+		t.Errorf("template pengguna en = %q, seharusnya menang atas template pengguna tingkat atas", got)
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Metrik adalah kolom nilai per kabupaten-tahun di CSV berformat Trase
+// (area tertanam, deforestasi, jumlah pabrik, produksi, ...). Metrik
+// pertama yang dipilih lewat -metric menjadi metrik utama: semua analisis,
+// grafik dan laporan dihitung dari nilainya, dan placeholder {{metric}} dan
+// {{unit}} di teks keluaran diisi label dan satuan metrik tersebut. Metrik
+// lainnya ditampilkan berdampingan di sheet dan bagian laporan tersendiri.
+const metricsFile = "metrik.json"
+
+type Metric struct {
+	Name        string `json:"name"`
+	Column      string `json:"column"`
+	Label       string `json:"label"`
+	Unit        string `json:"unit"`
+	Aggregation string `json:"aggregation"`
+}
+
+type MetricCatalog struct {
+	Metrics []Metric `json:"metrics"`
+}
+
+var defaultMetric = Metric{
+	Name:        "area",
+	Column:      "oil_palm_planted_area_hectares",
+	Label:       "Area",
+	Unit:        "ha",
+	Aggregation: "sum",
+}
+
+// metricAggregations adalah aturan agregasi ke provinsi dan nasional: sum
+// menjumlahkan nilai kabupaten, mean merata-ratakan baris.
+var metricAggregations = []string{"sum", "mean"}
+
+// activeMetrics adalah metrik yang dibaca dari CSV, sesuai urutan
+// RawPalmOilData.Values; activeMetrics[0] adalah metrik utama.
+var activeMetrics = []Metric{defaultMetric}
+
+func loadMetricCatalog() []Metric {
+	catalog := MetricCatalog{Metrics: []Metric{defaultMetric}}
+
+	content, err := os.ReadFile(metricsFile)
+	if os.IsNotExist(err) {
+		return catalog.Metrics
+	} else if err != nil {
+		log.Fatal("Error membaca katalog metrik:", err)
+	}
+
+	if err := json.Unmarshal(content, &catalog); err != nil {
+		log.Fatal("Error parsing katalog metrik:", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, metric := range catalog.Metrics {
+		switch {
+		case metric.Name == "" || metric.Column == "":
+			log.Fatalf("Metrik %q: name dan column wajib diisi", metric.Name)
+		case seen[metric.Name]:
+			log.Fatalf("Metrik %q didefinisikan lebih dari sekali", metric.Name)
+		case !slices.Contains(metricAggregations, metric.Aggregation):
+			log.Fatalf("Metrik %q: agregasi tidak dikenal %q (gunakan %s)", metric.Name, metric.Aggregation, strings.Join(metricAggregations, " atau "))
+		}
+		seen[metric.Name] = true
+	}
+	return catalog.Metrics
+}
+
+// selectMetrics memilih metrik dari katalog berdasarkan daftar nama yang
+// dipisah koma; daftar kosong berarti metrik pertama katalog.
+func selectMetrics(catalog []Metric, names string) []Metric {
+	if strings.TrimSpace(names) == "" {
+		return catalog[:1]
+	}
+
+	var selected []Metric
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		index := slices.IndexFunc(catalog, func(metric Metric) bool { return metric.Name == name })
+		if index < 0 {
+			var available []string
+			for _, metric := range catalog {
+				available = append(available, metric.Name)
+			}
+			log.Fatalf("Metrik tidak dikenal: %q (tersedia di %s: %s)", name, metricsFile, strings.Join(available, ", "))
+		}
+		if !slices.ContainsFunc(selected, func(metric Metric) bool { return metric.Name == name }) {
+			selected = append(selected, catalog[index])
+		}
+	}
+	return selected
+}
+
+func setActiveMetrics(metrics []Metric) {
+	activeMetrics = metrics
+	metricReplacer = newMetricReplacer(primaryMetric())
+}
+
+func primaryMetric() Metric {
+	return activeMetrics[0]
+}
+
+// metricReplacer mengisi placeholder metrik utama di teks katalog:
+// {{metric}}, {{Metric}} dan {{METRIC}} untuk label (huruf kecil, apa
+// adanya, kapital) dan {{unit}} untuk satuan. Teks tanpa placeholder tidak
+// diubah, jadi hektare yang bukan metrik utama (hasil t/ha, kehilangan
+// hutan, luas wilayah) tetap ditulis apa adanya.
+var metricReplacer = newMetricReplacer(defaultMetric)
+
+func newMetricReplacer(metric Metric) *strings.Replacer {
+	return strings.NewReplacer(
+		"{{metric}}", strings.ToLower(metric.Label),
+		"{{Metric}}", metric.Label,
+		"{{METRIC}}", strings.ToUpper(metric.Label),
+		"{{unit}}", metric.Unit,
+	)
+}
+
+func metricText(text string) string {
+	return metricReplacer.Replace(text)
+}
+
+// metricUnit dan metricLabel dipakai teks yang tidak melewati tr(), mis.
+// template laporan dan angka berformat.
+func metricUnit() string {
+	return primaryMetric().Unit
+}
+
+func metricLabel() string {
+	return primaryMetric().Label
+}
+
+// MetricSummary meringkas satu metrik per provinsi untuk perbandingan
+// berdampingan: nilai awal dan akhir (sesuai aturan agregasi metrik) dan
+// korelasi Pearson nilai akhirnya dengan metrik utama antarprovinsi.
+type MetricSummary struct {
+	Metric        Metric
+	NationalStart float64
+	NationalEnd   float64
+	Provinces     map[string]MetricValue
+	Correlation   float64
+}
+
+type MetricValue struct {
+	Start  float64
+	End    float64
+	Growth float64
+}
+
+// compareMetrics memakai kubus metrik utama dan membangun kubus untuk setiap
+// metrik aktif lainnya; hasilnya kosong bila hanya satu metrik yang dipilih.
+func compareMetrics(rawData []RawPalmOilData, primary *DataCube, startYear, endYear int) ([]MetricSummary, error) {
+	if len(activeMetrics) < 2 {
+		return nil, nil
+	}
+
+	var summaries []MetricSummary
+	for i, metric := range activeMetrics {
+		cube := primary
+		if i > 0 {
+			cube = newDataCube(rawData, i)
+		}
+		series, err := cube.Series(LevelProvince, CubeFilter{})
+		if err != nil {
+			return nil, err
+		}
+		summary := MetricSummary{
+			Metric:        metric,
+			NationalStart: cube.NationalArea(startYear),
+			NationalEnd:   cube.NationalArea(endYear),
+			Provinces:     make(map[string]MetricValue, len(series)),
+		}
+		for _, province := range series {
+			value := MetricValue{Start: province.Values[startYear], End: province.Values[endYear]}
+			if value.Start > 0 {
+				value.Growth = (value.End - value.Start) / value.Start * 100
+			}
+			summary.Provinces[province.Key] = value
+		}
+		summaries = append(summaries, summary)
+	}
+
+	provinces := metricProvinces(summaries)
+	primaryValues := make([]float64, len(provinces))
+	for i, province := range provinces {
+		primaryValues[i] = summaries[0].Provinces[province].End
+	}
+	for i := range summaries {
+		values := make([]float64, len(provinces))
+		for j, province := range provinces {
+			values[j] = summaries[i].Provinces[province].End
+		}
+		summaries[i].Correlation = pearsonCorrelation(primaryValues, values)
+	}
+
+	fmt.Fprintf(progressOutput, "📐 Perbandingan metrik dihitung: %d metrik\n", len(summaries))
+	return summaries, nil
+}
+
+// metricProvinces mengurutkan provinsi berdasarkan nilai akhir metrik utama.
+func metricProvinces(summaries []MetricSummary) []string {
+	var provinces []string
+	for province := range summaries[0].Provinces {
+		provinces = append(provinces, province)
+	}
+	sort.Slice(provinces, func(i, j int) bool {
+		a, b := summaries[0].Provinces[provinces[i]].End, summaries[0].Provinces[provinces[j]].End
+		if a != b {
+			return a > b
+		}
+		return provinces[i] < provinces[j]
+	})
+	return provinces
+}
+
+// metricHeading adalah judul kolom metrik, mis. "Deforestasi 2022 (ha)".
+func metricHeading(metric Metric, suffix string) string {
+	if metric.Unit == "" {
+		return metric.Label + " " + suffix
+	}
+	return fmt.Sprintf("%s %s (%s)", metric.Label, suffix, metric.Unit)
+}
+
+// formatMetricValue seperti formatNumber, tetapi nilai kecil (mis. rata-rata
+// per kabupaten) tetap ditampilkan dengan dua desimal.
+func formatMetricValue(value float64) string {
+	if math.Abs(value) < 100 {
+		return fmt.Sprintf("%.2f", value)
+	}
+	return formatNumber(value)
+}
+
+func writeMetricSheet(f *excelize.File, summaries []MetricSummary, startYear, endYear int) {
+	if len(summaries) == 0 {
+		return
+	}
+	sheet := tr("Metrik")
+	f.NewSheet(sheet)
+
+	f.SetCellValue(sheet, "A1", tr("Provinsi"))
+	f.SetColWidth(sheet, "A", "A", 24)
+	column := 2
+	for _, summary := range summaries {
+		for _, heading := range []string{
+			metricHeading(summary.Metric, fmt.Sprint(startYear)),
+			metricHeading(summary.Metric, fmt.Sprint(endYear)),
+			summary.Metric.Label + " " + tr("Pertumbuhan (%)"),
+		} {
+			name, _ := excelize.ColumnNumberToName(column)
+			f.SetCellValue(sheet, name+"1", heading)
+			f.SetColWidth(sheet, name, name, 20)
+			column++
+		}
+	}
+
+	for i, province := range metricProvinces(summaries) {
+		row := i + 2
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), province)
+		column := 2
+		for _, summary := range summaries {
+			value := summary.Provinces[province]
+			for _, number := range []float64{value.Start, value.End, value.Growth} {
+				cell, _ := excelize.CoordinatesToCellName(column, row)
+				f.SetCellValue(sheet, cell, math.Round(number*100)/100)
+				column++
+			}
+		}
+	}
+}
+
+func buildMetricReport(summaries []MetricSummary, startYear, endYear int) string {
+	if len(summaries) == 0 {
+		return ""
+	}
+
+	report := tr("\n### 📐 PERBANDINGAN METRIK\n\n")
+	report += trf("| Metrik | Satuan | Agregasi | Nasional %d | Nasional %d | Korelasi dengan %s (provinsi, %d) |\n",
+		startYear, endYear, summaries[0].Metric.Label, endYear)
+	report += "|--------|--------|----------|-------------|-------------|------------------------------------|\n"
+	for _, summary := range summaries {
+		report += fmt.Sprintf("| %s | %s | %s | %s | %s | %.2f |\n", summary.Metric.Label, summary.Metric.Unit,
+			summary.Metric.Aggregation, formatMetricValue(summary.NationalStart), formatMetricValue(summary.NationalEnd), summary.Correlation)
+	}
+
+	provinces := metricProvinces(summaries)
+	if len(provinces) > 10 {
+		provinces = provinces[:10]
+	}
+	report += trf("\n**10 provinsi teratas menurut %s %d:**\n\n", summaries[0].Metric.Label, endYear)
+	report += "| " + tr("Provinsi")
+	separator := "|----------"
+	for _, summary := range summaries {
+		report += " | " + metricHeading(summary.Metric, fmt.Sprint(endYear)) + " | " + summary.Metric.Label + " Δ%"
+		separator += "|----------|------"
+	}
+	report += " |\n" + separator + "|\n"
+	for _, province := range provinces {
+		report += "| " + province
+		for _, summary := range summaries {
+			value := summary.Provinces[province]
+			report += fmt.Sprintf(" | %s | %.1f%%", formatMetricValue(value.End), value.Growth)
+		}
+		report += " |\n"
+	}
+	return report
+}
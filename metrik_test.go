@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestMetricPlaceholders(t *testing.T) {
+	defer setActiveMetrics(activeMetrics)
+	setActiveMetrics([]Metric{{Name: "pabrik", Label: "Pabrik", Unit: "unit"}})
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Total {{Metric}} (juta {{unit}})", "Total Pabrik (juta unit)"},
+		{"PROYEKSI {{METRIC}} NASIONAL", "PROYEKSI PABRIK NASIONAL"},
+		{"distribusi {{metric}} kabupaten", "distribusi pabrik kabupaten"},
+		// Hektare dan kata area tanpa placeholder bukan metrik utama.
+		{"Hasil TBS (t/ha) per area", "Hasil TBS (t/ha) per area"},
+	}
+	for _, test := range tests {
+		if got := metricText(test.text); got != test.want {
+			t.Errorf("metricText(%q) = %q, seharusnya %q", test.text, got, test.want)
+		}
+	}
+
+	setActiveMetrics([]Metric{defaultMetric})
+	if got := metricText("Total {{Metric}} ({{unit}})"); got != "Total Area (ha)" {
+		t.Errorf("metrik bawaan menghasilkan %q, seharusnya %q", got, "Total Area (ha)")
+	}
+}
@@ -0,0 +1,459 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Simulasi Monte Carlo melakukan bootstrap laju pertumbuhan tahunan historis
+// tiap provinsi. Pada mode berkorelasi (bawaan, flag -mc-correlated), satu
+// tahun historis diundi untuk semua provinsi sekaligus sehingga guncangan
+// antarprovinsi ikut terbawa; pada mode independen tiap provinsi mengundi
+// lajunya sendiri. Jalur dibagi ke dalam chunk dengan seed tetap, jadi hasil
+// tidak bergantung pada jumlah goroutine. Ambang peluang nasional dan
+// provinsi diatur lewat -mc-thresholds dan -mc-province-threshold.
+const (
+	monteCarloPaths     = 5000
+	monteCarloSeed      = 42
+	monteCarloEndYear   = 2035
+	monteCarloChunkSize = 250
+
+	defaultMonteCarloThresholds        = "20000000,25000000,30000000"
+	defaultMonteCarloProvinceThreshold = 1000000
+)
+
+var monteCarloPercentiles = []float64{5, 25, 50, 75, 95}
+
+// MonteCarloSettings adalah pilihan simulasi dari flag; ambang dalam satuan
+// metrik utama.
+type MonteCarloSettings struct {
+	Correlated         bool
+	NationalThresholds []float64
+	ProvinceThreshold  float64
+}
+
+type PercentileBand struct {
+	Year   int
+	Values map[float64]float64
+	Mean   float64
+}
+
+type ThresholdProbability struct {
+	Year        int
+	Threshold   float64
+	Probability float64
+}
+
+type ProvinceMonteCarlo struct {
+	Province       string
+	Bands          []PercentileBand
+	ProbExceed     map[int]float64
+	ProbDecline    float64
+	HistoricalDraw int
+}
+
+type MonteCarloResult struct {
+	MonteCarloSettings
+	Paths      int
+	Seed       int64
+	National   []PercentileBand
+	Thresholds []ThresholdProbability
+	Provinces  []ProvinceMonteCarlo
+}
+
+// parseMonteCarloThresholds membaca daftar ambang nasional dipisah koma dan
+// mengurutkannya naik.
+func parseMonteCarloThresholds(text string) ([]float64, error) {
+	var thresholds []float64
+	for _, part := range strings.Split(text, ",") {
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || threshold <= 0 {
+			return nil, fmt.Errorf("ambang Monte Carlo tidak valid: %q (harus angka positif)", part)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	sort.Float64s(thresholds)
+	return slices.Compact(thresholds), nil
+}
+
+func runMonteCarloSimulation(ctx context.Context, models []ProvinceModel, settings MonteCarloSettings) (*MonteCarloResult, error) {
+	years := monteCarloEndYear - 2022
+	provinceCount := len(models)
+
+	independent := make([][]float64, provinceCount)
+	byYear := make([]map[int]float64, provinceCount)
+	for i, model := range models {
+		independent[i] = calculateYearlyGrowthRates(model.YearlyData)
+		byYear[i] = calculateGrowthRatesByYear(model.YearlyData)
+	}
+
+	// paths[p][i][t] adalah area provinsi i pada tahun 2023+t di jalur p.
+	paths := make([][][]float64, monteCarloPaths)
+
+	chunkCount := (monteCarloPaths + monteCarloChunkSize - 1) / monteCarloChunkSize
+	err := parallelFor(ctx, chunkCount, func(chunk int) error {
+		rng := rand.New(rand.NewSource(monteCarloSeed + int64(chunk)))
+		end := min((chunk+1)*monteCarloChunkSize, monteCarloPaths)
+		for p := chunk * monteCarloChunkSize; p < end; p++ {
+			paths[p] = simulateMonteCarloPath(models, independent, byYear, years, settings.Correlated, rng)
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MonteCarloResult{
+		MonteCarloSettings: settings,
+		Paths:              monteCarloPaths,
+		Seed:               monteCarloSeed,
+	}
+
+	samples := make([]float64, monteCarloPaths)
+	for t := 0; t < years; t++ {
+		year := 2023 + t
+		for p := range paths {
+			samples[p] = 0
+			for i := 0; i < provinceCount; i++ {
+				samples[p] += paths[p][i][t]
+			}
+		}
+		result.National = append(result.National, summarizeSamples(year, samples))
+		for _, threshold := range settings.NationalThresholds {
+			result.Thresholds = append(result.Thresholds, ThresholdProbability{
+				Year:        year,
+				Threshold:   threshold,
+				Probability: probabilityAbove(samples, threshold),
+			})
+		}
+	}
+
+	for i, model := range models {
+		province := ProvinceMonteCarlo{
+			Province:       model.Province,
+			ProbExceed:     make(map[int]float64),
+			HistoricalDraw: len(independent[i]),
+		}
+		for t := 0; t < years; t++ {
+			year := 2023 + t
+			for p := range paths {
+				samples[p] = paths[p][i][t]
+			}
+			province.Bands = append(province.Bands, summarizeSamples(year, samples))
+			province.ProbExceed[year] = probabilityAbove(samples, settings.ProvinceThreshold)
+		}
+		province.ProbDecline = 1 - probabilityAbove(samples, model.TotalArea2022)
+		result.Provinces = append(result.Provinces, province)
+	}
+
+	fmt.Fprintf(progressOutput, "🎲 Simulasi Monte Carlo selesai: %d jalur x %d provinsi (2023-%d, seed %d)\n",
+		monteCarloPaths, provinceCount, monteCarloEndYear, monteCarloSeed)
+	return result, nil
+}
+
+func simulateMonteCarloPath(models []ProvinceModel, independent [][]float64, byYear []map[int]float64, years int, correlated bool, rng *rand.Rand) [][]float64 {
+	path := make([][]float64, len(models))
+	current := make([]float64, len(models))
+	for i, model := range models {
+		path[i] = make([]float64, years)
+		current[i] = model.TotalArea2022
+	}
+
+	for t := 0; t < years; t++ {
+		historicalYear := 2004 + rng.Intn(19)
+		for i := range models {
+			rate, ok := 0.0, false
+			if correlated {
+				rate, ok = byYear[i][historicalYear]
+			}
+			if !ok && len(independent[i]) > 0 {
+				rate = independent[i][rng.Intn(len(independent[i]))]
+			}
+			current[i] *= 1 + rate/100
+			if current[i] < 0 {
+				current[i] = 0
+			}
+			path[i][t] = current[i]
+		}
+	}
+
+	return path
+}
+
+func calculateGrowthRatesByYear(yearlyData map[int]float64) map[int]float64 {
+	rates := make(map[int]float64)
+	years := getSortedYears(yearlyData)
+
+	for i := 1; i < len(years); i++ {
+		if yearlyData[years[i-1]] > 0 {
+			rates[years[i]] = ((yearlyData[years[i]] - yearlyData[years[i-1]]) / yearlyData[years[i-1]]) * 100
+		}
+	}
+	return rates
+}
+
+func summarizeSamples(year int, samples []float64) PercentileBand {
+	sorted := make([]float64, len(samples))
+	copy(sorted, samples)
+	sort.Float64s(sorted)
+
+	band := PercentileBand{Year: year, Values: make(map[float64]float64)}
+	for _, p := range monteCarloPercentiles {
+		band.Values[p] = percentile(sorted, p)
+	}
+	for _, v := range sorted {
+		band.Mean += v
+	}
+	band.Mean /= float64(len(sorted))
+	return band
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	position := p / 100 * float64(len(sorted)-1)
+	lower := int(position)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	fraction := position - float64(lower)
+	return sorted[lower] + fraction*(sorted[lower+1]-sorted[lower])
+}
+
+func probabilityAbove(samples []float64, threshold float64) float64 {
+	count := 0
+	for _, v := range samples {
+		if v > threshold {
+			count++
+		}
+	}
+	return float64(count) / float64(len(samples))
+}
+
+func findBand(bands []PercentileBand, year int) *PercentileBand {
+	for i := range bands {
+		if bands[i].Year == year {
+			return &bands[i]
+		}
+	}
+	return nil
+}
+
+func monteCarloMode(result *MonteCarloResult) string {
+	if result.Correlated {
+		return tr("berkorelasi (bootstrap tahun bersama)")
+	}
+	return tr("independen")
+}
+
+func writeMonteCarloSheets(f *excelize.File, result *MonteCarloResult) {
+	sheet := tr("Monte_Carlo_Nasional")
+	f.NewSheet(sheet)
+
+	f.SetCellValue(sheet, "A1", trf("SIMULASI MONTE CARLO %d JALUR, SEED %d, MODE %s", result.Paths, result.Seed, monteCarloMode(result)))
+
+	headers := []string{"Tahun", "Rata-rata ({{unit}})"}
+	for _, p := range monteCarloPercentiles {
+		headers = append(headers, trf("P%.0f ({{unit}})", p))
+	}
+	for _, threshold := range result.NationalThresholds {
+		headers = append(headers, fmt.Sprintf("P(> %s)", formatNumber(threshold)))
+	}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 2)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 16)
+	}
+
+	for i, band := range result.National {
+		row := i + 3
+		values := []interface{}{band.Year, formatNumber(band.Mean)}
+		for _, p := range monteCarloPercentiles {
+			values = append(values, formatNumber(band.Values[p]))
+		}
+		for _, threshold := range result.Thresholds {
+			if threshold.Year == band.Year {
+				values = append(values, fmt.Sprintf("%.1f%%", threshold.Probability*100))
+			}
+		}
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, row)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+
+	sheet = tr("Monte_Carlo_Provinsi")
+	f.NewSheet(sheet)
+
+	headers = []string{"Provinsi", "Tahun", "Rata-rata ({{unit}})"}
+	for _, p := range monteCarloPercentiles {
+		headers = append(headers, trf("P%.0f ({{unit}})", p))
+	}
+	headers = append(headers, fmt.Sprintf("P(> %s)", formatNumber(result.ProvinceThreshold)),
+		trf("P(Turun vs 2022 di %d)", monteCarloEndYear), "Sampel Historis")
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 16)
+	}
+
+	row := 2
+	for _, province := range result.Provinces {
+		for _, year := range []int{2030, monteCarloEndYear} {
+			band := findBand(province.Bands, year)
+			if band == nil {
+				continue
+			}
+			values := []interface{}{province.Province, year, formatNumber(band.Mean)}
+			for _, p := range monteCarloPercentiles {
+				values = append(values, formatNumber(band.Values[p]))
+			}
+			values = append(values, fmt.Sprintf("%.1f%%", province.ProbExceed[year]*100),
+				fmt.Sprintf("%.1f%%", province.ProbDecline*100), province.HistoricalDraw)
+			for j, value := range values {
+				cell, _ := excelize.CoordinatesToCellName(j+1, row)
+				f.SetCellValue(sheet, cell, value)
+			}
+			row++
+		}
+	}
+}
+
+func createMonteCarloFanChart(trends []NationalTrend, result *MonteCarloResult) error {
+	if len(trends) == 0 {
+		return nil
+	}
+
+	p := plot.New()
+	p.Title.Text = trf("SIMULASI MONTE CARLO {{METRIC}} NASIONAL 2023-%d (%d JALUR)", monteCarloEndYear, result.Paths)
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Tahun")
+	p.Y.Label.Text = tr("Total {{Metric}} (juta {{unit}})")
+
+	last := trends[len(trends)-1]
+	bands := []struct {
+		lower, upper float64
+		fill         color.RGBA
+		label        string
+	}{
+		{5, 95, color.RGBA{R: 173, G: 216, B: 230, A: 255}, "P5-P95"},
+		{25, 75, color.RGBA{R: 70, G: 130, B: 180, A: 255}, "P25-P75"},
+	}
+
+	for _, b := range bands {
+		var ring plotter.XYs
+		ring = append(ring, plotter.XY{X: float64(last.Year), Y: last.TotalArea / 1000000})
+		for _, band := range result.National {
+			ring = append(ring, plotter.XY{X: float64(band.Year), Y: band.Values[b.upper] / 1000000})
+		}
+		for i := len(result.National) - 1; i >= 0; i-- {
+			band := result.National[i]
+			ring = append(ring, plotter.XY{X: float64(band.Year), Y: band.Values[b.lower] / 1000000})
+		}
+
+		polygon, err := plotter.NewPolygon(ring)
+		if err != nil {
+			return err
+		}
+		polygon.Color = b.fill
+		polygon.LineStyle.Width = vg.Length(0)
+		p.Add(polygon)
+		p.Legend.Add(b.label, polygon)
+	}
+
+	median := plotter.XYs{{X: float64(last.Year), Y: last.TotalArea / 1000000}}
+	for _, band := range result.National {
+		median = append(median, plotter.XY{X: float64(band.Year), Y: band.Values[50] / 1000000})
+	}
+	medianLine, err := plotter.NewLine(median)
+	if err != nil {
+		return err
+	}
+	medianLine.Color = color.RGBA{R: 0, G: 0, B: 139, A: 255}
+	medianLine.Width = vg.Points(2)
+	p.Add(medianLine)
+	p.Legend.Add(tr("Median"), medianLine)
+
+	history := make(plotter.XYs, len(trends))
+	for i, trend := range trends {
+		history[i].X = float64(trend.Year)
+		history[i].Y = trend.TotalArea / 1000000
+	}
+	historyLine, err := plotter.NewLine(history)
+	if err != nil {
+		return err
+	}
+	historyLine.Color = color.RGBA{R: 0, G: 100, B: 0, A: 255}
+	historyLine.Width = vg.Points(2)
+	p.Add(historyLine)
+	p.Legend.Add(tr("Historis"), historyLine)
+
+	p.Legend.Top = true
+	p.Legend.Left = true
+	p.Add(plotter.NewGrid())
+
+	return saveChart(p, 16*vg.Inch, 10*vg.Inch, "monte_carlo_nasional_2035")
+}
+
+func buildMonteCarloReport(result *MonteCarloResult) string {
+	report := trf("\n### 🎲 SIMULASI MONTE CARLO 2023-%d\n\n", monteCarloEndYear)
+	report += trf("Sebanyak %d jalur disimulasikan dengan bootstrap laju pertumbuhan historis (seed %d). Mode: %s.\n\n",
+		result.Paths, result.Seed, monteCarloMode(result))
+	var thresholds []string
+	for _, threshold := range result.NationalThresholds {
+		thresholds = append(thresholds, formatNumber(threshold))
+	}
+	report += trf("Ambang peluang nasional: %s %s; ambang provinsi: %s %s.\n\n",
+		strings.Join(thresholds, ", "), metricUnit(), formatNumber(result.ProvinceThreshold), metricUnit())
+	report += tr("| Tahun | P5 | Median | P95 |")
+	for _, threshold := range result.NationalThresholds {
+		report += fmt.Sprintf(" P(> %s) |", formatNumber(threshold))
+	}
+	report += "\n|-------|----|--------|-----|"
+	for range result.NationalThresholds {
+		report += "---------|"
+	}
+	report += "\n"
+
+	for _, year := range []int{2025, 2030, monteCarloEndYear} {
+		band := findBand(result.National, year)
+		if band == nil {
+			continue
+		}
+		unit := metricUnit()
+		report += fmt.Sprintf("| %d | %s %s | %s %s | %s %s |", year,
+			formatNumber(band.Values[5]), unit, formatNumber(band.Values[50]), unit, formatNumber(band.Values[95]), unit)
+		for _, threshold := range result.Thresholds {
+			if threshold.Year == year {
+				report += fmt.Sprintf(" %.0f%% |", threshold.Probability*100)
+			}
+		}
+		report += "\n"
+	}
+
+	var likely []string
+	for _, province := range result.Provinces {
+		if province.ProbExceed[2030] >= 0.5 {
+			likely = append(likely,
+				fmt.Sprintf("%s (%.0f%%)", province.Province, province.ProbExceed[2030]*100))
+		}
+	}
+	if len(likely) > 0 {
+		report += trf("\nProvinsi dengan peluang ≥50%% melampaui %s %s pada 2030: %s\n",
+			formatNumber(result.ProvinceThreshold), metricUnit(), joinLimited(likely, 15))
+	}
+
+	return report
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40, 50}
+	for _, test := range []struct{ p, want float64 }{
+		{0, 10}, {25, 20}, {50, 30}, {60, 34}, {95, 48}, {100, 50},
+	} {
+		if got := percentile(sorted, test.p); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("P%.0f = %v, seharusnya %v", test.p, got, test.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("persentil sampel kosong = %v, seharusnya 0", got)
+	}
+}
+
+func TestSummarizeSamples(t *testing.T) {
+	band := summarizeSamples(2030, []float64{4, 1, 3, 2, 5})
+	if band.Year != 2030 || band.Mean != 3 || band.Values[50] != 3 || band.Values[5] != 1.2 || band.Values[95] != 4.8 {
+		t.Errorf("ringkasan sampel salah: %+v", band)
+	}
+	if got := probabilityAbove([]float64{1, 2, 3, 4}, 2); got != 0.5 {
+		t.Errorf("P(> 2) = %v, seharusnya 0.5", got)
+	}
+}
+
+// monteCarloTestModels membuat provinsi dengan pertumbuhan historis tetap
+// 10% per tahun dan dua provinsi kembar dengan laju bervariasi.
+func monteCarloTestModels() []ProvinceModel {
+	steady := make(map[int]float64)
+	volatile := make(map[int]float64)
+	area := 1000.0
+	for year := 2003; year <= 2022; year++ {
+		steady[year] = 1000 * math.Pow(1.1, float64(year-2003))
+		volatile[year] = area
+		area *= 1 + float64(year%7)/20
+	}
+	models := []ProvinceModel{{Province: "STABIL", YearlyData: steady, TotalArea2022: steady[2022]}}
+	for _, name := range []string{"KEMBAR A", "KEMBAR B"} {
+		models = append(models, ProvinceModel{Province: name, YearlyData: volatile, TotalArea2022: volatile[2022]})
+	}
+	return models
+}
+
+func TestSimulateMonteCarloPath(t *testing.T) {
+	models := monteCarloTestModels()
+	independent := make([][]float64, len(models))
+	byYear := make([]map[int]float64, len(models))
+	for i, model := range models {
+		independent[i] = calculateYearlyGrowthRates(model.YearlyData)
+		byYear[i] = calculateGrowthRatesByYear(model.YearlyData)
+	}
+
+	for _, correlated := range []bool{true, false} {
+		path := simulateMonteCarloPath(models, independent, byYear, 13, correlated, rand.New(rand.NewSource(1)))
+		for step := range path[0] {
+			want := models[0].TotalArea2022 * math.Pow(1.1, float64(step+1))
+			if math.Abs(path[0][step]-want)/want > 1e-9 {
+				t.Errorf("correlated=%v: area tahun ke-%d = %v, seharusnya %v", correlated, step+1, path[0][step], want)
+			}
+		}
+
+		// Pada mode berkorelasi provinsi kembar mengundi tahun yang sama
+		// sehingga jalurnya identik; pada mode independen tidak.
+		identical := true
+		for step := range path[1] {
+			identical = identical && path[1][step] == path[2][step]
+		}
+		if identical != correlated {
+			t.Errorf("correlated=%v: jalur provinsi kembar identik = %v", correlated, identical)
+		}
+	}
+}
+
+func TestRunMonteCarloSimulationDeterministic(t *testing.T) {
+	models := monteCarloTestModels()
+	defer func(parallelism int) { pipelineParallelism = parallelism }(pipelineParallelism)
+
+	var results []*MonteCarloResult
+	for _, workers := range []int{1, 4} {
+		pipelineParallelism = workers
+		result, err := runMonteCarloSimulation(context.Background(), models, MonteCarloSettings{
+			Correlated: true, NationalThresholds: []float64{1e5, 1e6}, ProvinceThreshold: 1e4,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+	for i, band := range results[0].National {
+		other := results[1].National[i]
+		for _, p := range monteCarloPercentiles {
+			if band.Values[p] != other.Values[p] {
+				t.Fatalf("%d P%.0f berbeda antara 1 dan 4 worker: %v vs %v", band.Year, p, band.Values[p], other.Values[p])
+			}
+		}
+	}
+	if !results[0].Correlated || results[0].Paths != monteCarloPaths || len(results[0].Thresholds) != 2*len(results[0].National) {
+		t.Errorf("metadata hasil salah: %+v", results[0])
+	}
+	// Area STABIL tumbuh tetap: 1000·1.1^22 ≈ 8.140 pada 2025 dan
+	// 1000·1.1^27 ≈ 13.110 pada 2030, jadi ambang 10.000 hanya terlampaui 2030.
+	stable := results[0].Provinces[0].ProbExceed
+	if stable[2025] != 0 || stable[2030] != 1 {
+		t.Errorf("P(STABIL > 10.000) 2025 = %v dan 2030 = %v, seharusnya 0 dan 1", stable[2025], stable[2030])
+	}
+}
+
+func TestParseMonteCarloThresholds(t *testing.T) {
+	got, err := parseMonteCarloThresholds("3e7, 2e7,2e7")
+	if err != nil || len(got) != 2 || got[0] != 2e7 || got[1] != 3e7 {
+		t.Errorf("ambang = %v (%v), seharusnya [2e7 3e7]", got, err)
+	}
+	for _, text := range []string{"", "abc", "1e6,-5", "0"} {
+		if _, err := parseMonteCarloThresholds(text); err == nil {
+			t.Errorf("%q seharusnya ditolak", text)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+const stackedAreaTopProvinces = 8
+
+type ShareChange struct {
+	Province   string
+	FromYear   int
+	ToYear     int
+	FromShare  float64
+	ToShare    float64
+	Change     float64
+	AreaChange float64
+}
+
+func assignMarketShareHistory(models []ProvinceModel) {
+	totals := make(map[int]float64)
+	for _, model := range models {
+		for year, area := range model.YearlyData {
+			totals[year] += area
+		}
+	}
+
+	for i := range models {
+		models[i].MarketShareHistory = make(map[int]float64)
+		for year := 2003; year <= 2022; year++ {
+			if totals[year] > 0 {
+				models[i].MarketShareHistory[year] = models[i].YearlyData[year] / totals[year] * 100
+			}
+		}
+	}
+}
+
+func calculateShareChanges(models []ProvinceModel, fromYear, toYear int) []ShareChange {
+	var changes []ShareChange
+	for _, model := range models {
+		changes = append(changes, ShareChange{
+			Province:   model.Province,
+			FromYear:   fromYear,
+			ToYear:     toYear,
+			FromShare:  model.MarketShareHistory[fromYear],
+			ToShare:    model.MarketShareHistory[toYear],
+			Change:     model.MarketShareHistory[toYear] - model.MarketShareHistory[fromYear],
+			AreaChange: model.YearlyData[toYear] - model.YearlyData[fromYear],
+		})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Change > changes[j].Change
+	})
+	return changes
+}
+
+func getIslandGroup(province string) string {
+	switch {
+	case strings.HasPrefix(province, "SUMATERA"), province == "RIAU", province == "JAMBI", province == "ACEH",
+		province == "BENGKULU", province == "LAMPUNG", strings.HasPrefix(province, "KEP"):
+		return "SUMATERA"
+	case strings.HasPrefix(province, "KALIMANTAN"):
+		return "KALIMANTAN"
+	case strings.HasPrefix(province, "PAPUA"):
+		return "PAPUA"
+	case strings.HasPrefix(province, "SULAWESI"), province == "GORONTALO":
+		return "SULAWESI"
+	}
+	return "LAINNYA"
+}
+
+func calculateIslandShares(models []ProvinceModel, year int) map[string]float64 {
+	shares := make(map[string]float64)
+	for _, model := range models {
+		shares[getIslandGroup(model.Province)] += model.MarketShareHistory[year]
+	}
+	return shares
+}
+
+func writeMarketShareSheets(f *excelize.File, models []ProvinceModel, changes []ShareChange) {
+	sheet := tr("Pangsa_Pasar_Tahunan")
+	f.NewSheet(sheet)
+
+	f.SetCellValue(sheet, "A1", tr("Provinsi"))
+	f.SetColWidth(sheet, "A", "A", 24)
+	for year := 2003; year <= 2022; year++ {
+		cell, _ := excelize.CoordinatesToCellName(year-2003+2, 1)
+		f.SetCellValue(sheet, cell, year)
+	}
+	for i, model := range models {
+		row := i + 2
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), model.Province)
+		for year := 2003; year <= 2022; year++ {
+			cell, _ := excelize.CoordinatesToCellName(year-2003+2, row)
+			f.SetCellValue(sheet, cell, fmt.Sprintf("%.2f%%", model.MarketShareHistory[year]))
+		}
+	}
+
+	if len(changes) == 0 {
+		return
+	}
+
+	sheet = tr("Perubahan_Pangsa")
+	f.NewSheet(sheet)
+
+	from, to := changes[0].FromYear, changes[0].ToYear
+	headers := []string{"Provinsi", trf("Pangsa %d (%%)", from), trf("Pangsa %d (%%)", to),
+		"Perubahan (poin %)", trf("Perubahan {{Metric}} %d-%d ({{unit}})", from, to), "Pulau"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 20)
+	}
+	for i, change := range changes {
+		row := i + 2
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), change.Province)
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), fmt.Sprintf("%.2f%%", change.FromShare))
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), fmt.Sprintf("%.2f%%", change.ToShare))
+		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), fmt.Sprintf("%+.2f", change.Change))
+		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), formatSignedNumber(change.AreaChange))
+		f.SetCellValue(sheet, fmt.Sprintf("F%d", row), tr(getIslandGroup(change.Province)))
+	}
+}
+
+func createMarketShareStackedChart(models []ProvinceModel) error {
+	p := plot.New()
+	p.Title.Text = tr("KOMPOSISI {{METRIC}} NASIONAL PER PROVINSI 2003-2022 (100%)")
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Tahun")
+	p.Y.Label.Text = tr("Pangsa {{Metric}} Nasional (%)")
+
+	top := models
+	if len(top) > stackedAreaTopProvinces {
+		top = top[:stackedAreaTopProvinces]
+	}
+
+	type layer struct {
+		name   string
+		shares map[int]float64
+	}
+	var layers []layer
+	others := make(map[int]float64)
+	for year := 2003; year <= 2022; year++ {
+		others[year] = 100
+	}
+	for _, model := range top {
+		layers = append(layers, layer{name: getShortProvinceName(model.Province), shares: model.MarketShareHistory})
+		for year := 2003; year <= 2022; year++ {
+			others[year] -= model.MarketShareHistory[year]
+		}
+	}
+	layers = append(layers, layer{name: "LAINNYA", shares: others})
+
+	cumulative := make(map[int]float64)
+	for i, l := range layers {
+		var ring plotter.XYs
+		for year := 2003; year <= 2022; year++ {
+			ring = append(ring, plotter.XY{X: float64(year), Y: cumulative[year] + l.shares[year]})
+		}
+		for year := 2022; year >= 2003; year-- {
+			ring = append(ring, plotter.XY{X: float64(year), Y: cumulative[year]})
+		}
+		for year := 2003; year <= 2022; year++ {
+			cumulative[year] += l.shares[year]
+		}
+
+		polygon, err := plotter.NewPolygon(ring)
+		if err != nil {
+			return err
+		}
+		if l.name == "LAINNYA" {
+			polygon.Color = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+		} else {
+			polygon.Color = bumpColor(i)
+		}
+		polygon.LineStyle.Width = vg.Points(0.5)
+		polygon.LineStyle.Color = color.White
+		p.Add(polygon)
+		p.Legend.Add(tr(l.name), polygon)
+	}
+
+	p.X.Min, p.X.Max = 2003, 2022
+	p.Y.Min, p.Y.Max = 0, 100
+	p.Legend.Left = false
+	p.Legend.Top = true
+	p.Legend.XOffs = vg.Points(-10)
+
+	return saveChart(p, 18*vg.Inch, 10*vg.Inch, "pangsa_pasar_stacked_20tahun")
+}
+
+func buildMarketShareReport(models []ProvinceModel, changes []ShareChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	from, to := changes[0].FromYear, changes[0].ToYear
+	report := trf("\n### 🥧 PERGESERAN PANGSA PASAR %d-%d\n\n", from, to)
+
+	fromIslands := calculateIslandShares(models, from)
+	toIslands := calculateIslandShares(models, to)
+	report += trf("| Pulau | Pangsa %d | Pangsa %d | Perubahan |\n", from, to)
+	report += "|-------|------------|------------|-----------|\n"
+	for _, island := range []string{"SUMATERA", "KALIMANTAN", "SULAWESI", "PAPUA", "LAINNYA"} {
+		report += trf("| %s | %.1f%% | %.1f%% | %+.1f poin |\n",
+			tr(island), fromIslands[island], toIslands[island], toIslands[island]-fromIslands[island])
+	}
+
+	// Daftar kenaikan dan penurunan hanya memuat perubahan dengan tanda yang
+	// sesuai, jadi bisa lebih pendek dari lima atau kosong.
+	var gains, losses []string
+	for i := 0; i < len(changes) && len(gains) < 5 && changes[i].Change > 0; i++ {
+		gains = append(gains, trf("%s (%+.2f poin)", changes[i].Province, changes[i].Change))
+	}
+	for i := len(changes) - 1; i >= 0 && len(losses) < 5 && changes[i].Change < 0; i-- {
+		losses = append(losses, trf("%s (%+.2f poin)", changes[i].Province, changes[i].Change))
+	}
+	if len(gains) == 0 {
+		gains = []string{"-"}
+	}
+	if len(losses) == 0 {
+		losses = []string{"-"}
+	}
+	report += tr("\n**Kenaikan pangsa terbesar:** ") + strings.Join(gains, ", ")
+	report += tr("\n\n**Penurunan pangsa terbesar:** ") + strings.Join(losses, ", ")
+	report += "\n"
+
+	return report
+}
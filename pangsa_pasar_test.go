@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildMarketShareReportDeclinesOnlyNegative(t *testing.T) {
+	changes := []ShareChange{
+		{Province: "A", FromYear: 2003, ToYear: 2022, Change: 3},
+		{Province: "B", FromYear: 2003, ToYear: 2022, Change: 1},
+		{Province: "C", FromYear: 2003, ToYear: 2022, Change: 0},
+		{Province: "D", FromYear: 2003, ToYear: 2022, Change: -4},
+	}
+	report := buildMarketShareReport(nil, changes)
+
+	declines := report[strings.Index(report, "**Penurunan pangsa terbesar:**"):]
+	if !strings.Contains(declines, "D (-4.00 poin)") || strings.Contains(declines, "B (") || strings.Contains(declines, "C (") {
+		t.Errorf("penurunan seharusnya hanya D, didapat %q", declines)
+	}
+	gains := report[strings.Index(report, "**Kenaikan pangsa terbesar:**"):strings.Index(report, "**Penurunan")]
+	if !strings.Contains(gains, "A (+3.00 poin), B (+1.00 poin)") || strings.Contains(gains, "C (") || strings.Contains(gains, "D (") {
+		t.Errorf("kenaikan seharusnya A dan B, didapat %q", gains)
+	}
+
+	report = buildMarketShareReport(nil, changes[:2])
+	if !strings.Contains(report, "**Penurunan pangsa terbesar:** -") {
+		t.Errorf("tanpa penurunan seharusnya tertulis \"-\", didapat %q", report)
+	}
+}
@@ -0,0 +1,502 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Mode compare membandingkan dua ekspor CSV kabupaten (vintage lama dan
+// baru) sebelum hasil run baru dipercaya. Baris disejajarkan berdasarkan
+// (tahun, RegionID); nilai yang bergeser lebih dari toleransi (ha) dicatat
+// sebagai revisi, lalu kedua dataset dimodelkan ulang untuk melihat dampaknya
+// pada metrik, peringkat dan kategori provinsi.
+const (
+	comparisonExcelFile      = "perbandingan_dataset.xlsx"
+	comparisonReportFile     = "perbandingan_dataset.md"
+	defaultRevisionTolerance = 1.0
+	comparisonTopRevisions   = 20
+)
+
+type regionYearKey struct {
+	Year     int
+	RegionID string
+}
+
+type regionInfo struct {
+	Region   string
+	Province string
+	Years    int
+}
+
+// RegionChange mencatat kabupaten yang hanya ada di salah satu dataset
+// (ADDED/REMOVED) atau yang nama/provinsinya berubah (RENAMED).
+type RegionChange struct {
+	Status      string
+	RegionID    string
+	Region      string
+	Province    string
+	OldRegion   string
+	OldProvince string
+	Years       int
+}
+
+type ValueRevision struct {
+	Year          int
+	RegionID      string
+	Region        string
+	Province      string
+	OldArea       float64
+	NewArea       float64
+	Difference    float64
+	DifferencePct float64
+}
+
+// ProvinceModelChange membandingkan model satu provinsi; Old atau New
+// bernilai nol bila provinsi tidak ada di dataset tersebut.
+type ProvinceModelChange struct {
+	Province      string
+	InOld         bool
+	InNew         bool
+	Old           ProvinceModel
+	New           ProvinceModel
+	OldCategories []string
+	NewCategories []string
+}
+
+type VintageComparison struct {
+	OldFile     string
+	NewFile     string
+	Tolerance   float64
+	OldRows     int
+	NewRows     int
+	AddedRows   int
+	RemovedRows int
+	Regions     []RegionChange
+	Revisions   []ValueRevision
+	NetRevision float64
+	Provinces   []ProvinceModelChange
+}
+
+func runVintageComparison(ctx context.Context, oldFile, newFile string, tolerance float64) error {
+	if tolerance < 0 {
+		return fmt.Errorf("toleransi revisi tidak valid: %.2f (harus >= 0)", tolerance)
+	}
+
+	fmt.Fprintln(progressOutput, "🔍 PERBANDINGAN VINTAGE DATASET KELAPA SAWIT")
+	scoring := loadScoringConfig()
+	comparison := VintageComparison{}
+	var oldCube, newCube *DataCube
+	var oldModels, newModels []ProvinceModel
+
+	err := runPipeline(ctx, []pipelineStage{
+		{Name: "data-lama", Run: func(ctx context.Context) (err error) {
+			oldData, _, err := loadRawData(oldFile)
+			oldCube = newDataCube(oldData, 0)
+			return err
+		}},
+		{Name: "data-baru", Run: func(ctx context.Context) (err error) {
+			newData, _, err := loadRawData(newFile)
+			newCube = newDataCube(newData, 0)
+			return err
+		}},
+		{Name: "model-lama", Needs: []string{"data-lama"}, Run: func(ctx context.Context) (err error) {
+			oldModels, err = buildProvinceModels(ctx, oldCube, nil, nil, scoring)
+			return err
+		}},
+		{Name: "model-baru", Needs: []string{"data-baru"}, Run: func(ctx context.Context) (err error) {
+			newModels, err = buildProvinceModels(ctx, newCube, nil, nil, scoring)
+			return err
+		}},
+		{Name: "selisih", Needs: []string{"data-lama", "data-baru", "model-lama", "model-baru"}, Run: func(ctx context.Context) (err error) {
+			comparison, err = compareDatasets(oldCube, newCube, tolerance)
+			comparison.OldFile, comparison.NewFile = oldFile, newFile
+			comparison.Provinces = compareProvinceModels(oldModels, newModels)
+			return err
+		}},
+		{Name: "excel", Needs: []string{"selisih"}, Run: func(ctx context.Context) error {
+			return createComparisonExcel(comparison)
+		}},
+		{Name: "laporan", Needs: []string{"selisih"}, Run: func(ctx context.Context) error {
+			report := buildComparisonReport(comparison)
+			if err := os.WriteFile(comparisonReportFile, []byte(report), 0o644); err != nil {
+				return fmt.Errorf("error menyimpan ringkasan perbandingan: %w", err)
+			}
+			return nil
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(progressOutput, "   Wilayah berubah: %d, revisi nilai: %d, provinsi terdampak: %d\n",
+		len(comparison.Regions), len(comparison.Revisions), countChangedProvinces(comparison.Provinces, tolerance))
+	fmt.Fprintln(progressOutput, "\n✅ PERBANDINGAN DATASET SELESAI!")
+	fmt.Fprintln(progressOutput, "📁 File Output:")
+	fmt.Fprintln(progressOutput, "   - "+comparisonExcelFile)
+	fmt.Fprintln(progressOutput, "   - "+comparisonReportFile)
+	return nil
+}
+
+func compareDatasets(oldCube, newCube *DataCube, tolerance float64) (VintageComparison, error) {
+	oldValues, oldRegions, err := indexRegionYears(oldCube)
+	if err != nil {
+		return VintageComparison{}, err
+	}
+	newValues, newRegions, err := indexRegionYears(newCube)
+	if err != nil {
+		return VintageComparison{}, err
+	}
+	comparison := VintageComparison{Tolerance: tolerance, OldRows: len(oldValues), NewRows: len(newValues)}
+
+	for id, info := range newRegions {
+		old, ok := oldRegions[id]
+		switch {
+		case !ok:
+			comparison.Regions = append(comparison.Regions, RegionChange{Status: "ADDED", RegionID: id, Region: info.Region, Province: info.Province, Years: info.Years})
+		case old.Region != info.Region || old.Province != info.Province:
+			comparison.Regions = append(comparison.Regions, RegionChange{Status: "RENAMED", RegionID: id, Region: info.Region, Province: info.Province,
+				OldRegion: old.Region, OldProvince: old.Province, Years: info.Years})
+		}
+	}
+	for id, info := range oldRegions {
+		if _, ok := newRegions[id]; !ok {
+			comparison.Regions = append(comparison.Regions, RegionChange{Status: "REMOVED", RegionID: id, Region: info.Region, Province: info.Province, Years: info.Years})
+		}
+	}
+	sort.Slice(comparison.Regions, func(i, j int) bool {
+		a, b := comparison.Regions[i], comparison.Regions[j]
+		if a.Status != b.Status {
+			return a.Status < b.Status
+		}
+		return a.RegionID < b.RegionID
+	})
+
+	for key, newArea := range newValues {
+		oldArea, ok := oldValues[key]
+		if !ok {
+			comparison.AddedRows++
+			continue
+		}
+		difference := newArea - oldArea
+		if math.Abs(difference) <= tolerance {
+			continue
+		}
+
+		info := newRegions[key.RegionID]
+		revision := ValueRevision{
+			Year:       key.Year,
+			RegionID:   key.RegionID,
+			Region:     info.Region,
+			Province:   info.Province,
+			OldArea:    oldArea,
+			NewArea:    newArea,
+			Difference: difference,
+		}
+		if oldArea != 0 {
+			revision.DifferencePct = difference / oldArea * 100
+		}
+		comparison.Revisions = append(comparison.Revisions, revision)
+		comparison.NetRevision += difference
+	}
+	for key := range oldValues {
+		if _, ok := newValues[key]; !ok {
+			comparison.RemovedRows++
+		}
+	}
+	sort.Slice(comparison.Revisions, func(i, j int) bool {
+		a, b := comparison.Revisions[i], comparison.Revisions[j]
+		if math.Abs(a.Difference) != math.Abs(b.Difference) {
+			return math.Abs(a.Difference) > math.Abs(b.Difference)
+		}
+		if a.RegionID != b.RegionID {
+			return a.RegionID < b.RegionID
+		}
+		return a.Year < b.Year
+	})
+
+	return comparison, nil
+}
+
+// indexRegionYears membaca area per (tahun, RegionID) dari seri kabupaten
+// kubus; nama dan provinsi wilayah mengikuti tahun terakhir yang memuatnya.
+func indexRegionYears(cube *DataCube) (map[regionYearKey]float64, map[string]regionInfo, error) {
+	series, err := cube.Series(LevelRegency, CubeFilter{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make(map[regionYearKey]float64)
+	regions := make(map[string]regionInfo, len(series))
+	for _, regency := range series {
+		regions[regency.Key] = regionInfo{Region: regency.Name, Province: regency.Province, Years: len(regency.Values)}
+		for year, area := range regency.Values {
+			values[regionYearKey{Year: year, RegionID: regency.Key}] = area
+		}
+	}
+	return values, regions, nil
+}
+
+func compareProvinceModels(oldModels, newModels []ProvinceModel) []ProvinceModelChange {
+	byProvince := make(map[string]*ProvinceModelChange)
+	var order []string
+	change := func(province string) *ProvinceModelChange {
+		if byProvince[province] == nil {
+			byProvince[province] = &ProvinceModelChange{Province: province}
+			order = append(order, province)
+		}
+		return byProvince[province]
+	}
+
+	for _, model := range newModels {
+		c := change(model.Province)
+		c.InNew, c.New, c.NewCategories = true, model, modelCategories(model)
+	}
+	for _, model := range oldModels {
+		c := change(model.Province)
+		c.InOld, c.Old, c.OldCategories = true, model, modelCategories(model)
+	}
+
+	changes := make([]ProvinceModelChange, 0, len(order))
+	for _, province := range order {
+		changes = append(changes, *byProvince[province])
+	}
+	return changes
+}
+
+// changed menilai apakah model provinsi berubah secara berarti: keberadaan,
+// area di atas toleransi, peringkat, kategori, potensi investasi atau risiko.
+// Peringkat provinsi tanpa area di kedua dataset diabaikan karena urutan
+// antarprovinsi berarea nol tidak bermakna.
+func (c ProvinceModelChange) changed(tolerance float64) bool {
+	rankChanged := c.New.Rank2022 != c.Old.Rank2022 && (c.Old.TotalArea2022 > 0 || c.New.TotalArea2022 > 0)
+	return c.InOld != c.InNew ||
+		math.Abs(c.New.TotalArea2022-c.Old.TotalArea2022) > tolerance ||
+		math.Abs(c.New.TotalArea2003-c.Old.TotalArea2003) > tolerance ||
+		rankChanged ||
+		!slices.Equal(c.OldCategories, c.NewCategories) ||
+		c.New.InvestmentPotential != c.Old.InvestmentPotential ||
+		c.New.RiskLevel != c.Old.RiskLevel
+}
+
+func countChangedProvinces(changes []ProvinceModelChange, tolerance float64) int {
+	count := 0
+	for _, c := range changes {
+		if c.changed(tolerance) {
+			count++
+		}
+	}
+	return count
+}
+
+func translateCategories(categories []string) string {
+	if len(categories) == 0 {
+		return "-"
+	}
+	translated := make([]string, len(categories))
+	for i, category := range categories {
+		translated[i] = tr(category)
+	}
+	return strings.Join(translated, ", ")
+}
+
+func createComparisonExcel(comparison VintageComparison) error {
+	f := excelize.NewFile()
+
+	summary := tr("Ringkasan_Perbandingan")
+	f.SetSheetName("Sheet1", summary)
+	f.SetColWidth(summary, "A", "A", 40)
+	f.SetColWidth(summary, "B", "B", 60)
+	rows := [][]any{
+		{tr("Dataset lama"), comparison.OldFile},
+		{tr("Dataset baru"), comparison.NewFile},
+		{tr("Baris (tahun × kabupaten) lama"), comparison.OldRows},
+		{tr("Baris (tahun × kabupaten) baru"), comparison.NewRows},
+		{tr("Baris ditambahkan"), comparison.AddedRows},
+		{tr("Baris dihapus"), comparison.RemovedRows},
+		{tr("Toleransi revisi ({{unit}})"), comparison.Tolerance},
+		{tr("Nilai direvisi"), len(comparison.Revisions)},
+		{tr("Selisih bersih revisi ({{unit}})"), formatSignedNumber(comparison.NetRevision)},
+		{tr("Kabupaten berubah"), len(comparison.Regions)},
+		{tr("Provinsi terdampak"), countChangedProvinces(comparison.Provinces, comparison.Tolerance)},
+	}
+	for i, row := range rows {
+		f.SetCellValue(summary, fmt.Sprintf("A%d", i+1), row[0])
+		f.SetCellValue(summary, fmt.Sprintf("B%d", i+1), row[1])
+	}
+
+	regions := tr("Perubahan_Kabupaten")
+	f.NewSheet(regions)
+	writeComparisonHeaders(f, regions, []string{"Status", "Region ID", "Kabupaten", "Provinsi", "Kabupaten Lama", "Provinsi Lama", "Jumlah Tahun"})
+	for i, region := range comparison.Regions {
+		row := i + 2
+		f.SetCellValue(regions, fmt.Sprintf("A%d", row), tr(region.Status))
+		f.SetCellValue(regions, fmt.Sprintf("B%d", row), region.RegionID)
+		f.SetCellValue(regions, fmt.Sprintf("C%d", row), region.Region)
+		f.SetCellValue(regions, fmt.Sprintf("D%d", row), region.Province)
+		f.SetCellValue(regions, fmt.Sprintf("E%d", row), region.OldRegion)
+		f.SetCellValue(regions, fmt.Sprintf("F%d", row), region.OldProvince)
+		f.SetCellValue(regions, fmt.Sprintf("G%d", row), region.Years)
+	}
+
+	revisions := tr("Revisi_Nilai")
+	f.NewSheet(revisions)
+	writeComparisonHeaders(f, revisions, []string{"Tahun", "Region ID", "Kabupaten", "Provinsi", "{{Metric}} Lama ({{unit}})", "{{Metric}} Baru ({{unit}})", "Selisih ({{unit}})", "Selisih (%)"})
+	for i, revision := range comparison.Revisions {
+		row := i + 2
+		f.SetCellValue(revisions, fmt.Sprintf("A%d", row), revision.Year)
+		f.SetCellValue(revisions, fmt.Sprintf("B%d", row), revision.RegionID)
+		f.SetCellValue(revisions, fmt.Sprintf("C%d", row), revision.Region)
+		f.SetCellValue(revisions, fmt.Sprintf("D%d", row), revision.Province)
+		f.SetCellValue(revisions, fmt.Sprintf("E%d", row), math.Round(revision.OldArea*100)/100)
+		f.SetCellValue(revisions, fmt.Sprintf("F%d", row), math.Round(revision.NewArea*100)/100)
+		f.SetCellValue(revisions, fmt.Sprintf("G%d", row), math.Round(revision.Difference*100)/100)
+		if revision.OldArea != 0 {
+			f.SetCellValue(revisions, fmt.Sprintf("H%d", row), fmt.Sprintf("%+.2f%%", revision.DifferencePct))
+		} else {
+			f.SetCellValue(revisions, fmt.Sprintf("H%d", row), "-")
+		}
+	}
+
+	provinces := tr("Dampak_Provinsi")
+	f.NewSheet(provinces)
+	writeComparisonHeaders(f, provinces, []string{"Provinsi", "Status",
+		"{{Metric}} 2022 Lama ({{unit}})", "{{Metric}} 2022 Baru ({{unit}})", "Pertumbuhan Lama (%)", "Pertumbuhan Baru (%)",
+		"Pangsa Pasar Lama (%)", "Pangsa Pasar Baru (%)", "Peringkat Lama", "Peringkat Baru",
+		"Daya Saing Lama", "Daya Saing Baru", "Investasi Lama", "Investasi Baru",
+		"Risiko Lama", "Risiko Baru", "Kategori Lama", "Kategori Baru"})
+	for i, c := range comparison.Provinces {
+		row := i + 2
+		status := "UNCHANGED"
+		switch {
+		case !c.InOld:
+			status = "ADDED"
+		case !c.InNew:
+			status = "REMOVED"
+		case c.changed(comparison.Tolerance):
+			status = "CHANGED"
+		}
+		values := []any{c.Province, tr(status),
+			math.Round(c.Old.TotalArea2022), math.Round(c.New.TotalArea2022),
+			fmt.Sprintf("%.1f%%", c.Old.GrowthRate20Years), fmt.Sprintf("%.1f%%", c.New.GrowthRate20Years),
+			fmt.Sprintf("%.2f%%", c.Old.MarketShare2022), fmt.Sprintf("%.2f%%", c.New.MarketShare2022),
+			c.Old.Rank2022, c.New.Rank2022,
+			fmt.Sprintf("%.2f", c.Old.Competitiveness), fmt.Sprintf("%.2f", c.New.Competitiveness),
+			tr(c.Old.InvestmentPotential), tr(c.New.InvestmentPotential),
+			tr(c.Old.RiskLevel), tr(c.New.RiskLevel),
+			translateCategories(c.OldCategories), translateCategories(c.NewCategories)}
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, row)
+			f.SetCellValue(provinces, cell, value)
+		}
+	}
+
+	if err := f.SaveAs(comparisonExcelFile); err != nil {
+		return fmt.Errorf("error menyimpan Excel perbandingan: %w", err)
+	}
+	fmt.Fprintln(progressOutput, "📊 Workbook perbandingan berhasil dibuat:", comparisonExcelFile)
+	return nil
+}
+
+func writeComparisonHeaders(f *excelize.File, sheet string, headers []string) {
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		column, _ := excelize.ColumnNumberToName(i + 1)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, column, column, 18)
+	}
+}
+
+func buildComparisonReport(comparison VintageComparison) string {
+	report := tr("# PERBANDINGAN VINTAGE DATASET KELAPA SAWIT\n\n")
+	report += trf("- **Dataset lama**: %s (%d baris tahun × kabupaten)\n", comparison.OldFile, comparison.OldRows)
+	report += trf("- **Dataset baru**: %s (%d baris tahun × kabupaten)\n", comparison.NewFile, comparison.NewRows)
+	report += trf("- **Baris ditambahkan / dihapus**: %d / %d\n", comparison.AddedRows, comparison.RemovedRows)
+
+	counts := make(map[string]int)
+	for _, region := range comparison.Regions {
+		counts[region.Status]++
+	}
+	report += trf("- **Kabupaten ditambahkan / dihapus / berganti nama**: %d / %d / %d\n", counts["ADDED"], counts["REMOVED"], counts["RENAMED"])
+	report += trf("- **Nilai direvisi melebihi toleransi %s {{unit}}**: %d (selisih bersih %s {{unit}})\n",
+		formatNumber(comparison.Tolerance), len(comparison.Revisions), formatSignedNumber(comparison.NetRevision))
+
+	if len(comparison.Regions) > 0 {
+		report += tr("\n### 🗺️ PERUBAHAN KABUPATEN\n\n")
+		report += tr("| Status | Region ID | Kabupaten | Provinsi | Sebelumnya |\n")
+		report += "|--------|-----------|-----------|----------|------------|\n"
+		for _, region := range comparison.Regions {
+			previous := "-"
+			if region.Status == "RENAMED" {
+				previous = fmt.Sprintf("%s (%s)", region.OldRegion, region.OldProvince)
+			}
+			report += fmt.Sprintf("| %s | %s | %s | %s | %s |\n", tr(region.Status), region.RegionID, region.Region, region.Province, previous)
+		}
+	}
+
+	if len(comparison.Revisions) > 0 {
+		top := comparison.Revisions
+		if len(top) > comparisonTopRevisions {
+			top = top[:comparisonTopRevisions]
+		}
+		report += trf("\n### ✏️ %d REVISI NILAI TERBESAR\n\n", len(top))
+		report += tr("| Tahun | Kabupaten | Provinsi | {{Metric}} Lama ({{unit}}) | {{Metric}} Baru ({{unit}}) | Selisih ({{unit}}) | Selisih (%) |\n")
+		report += "|-------|-----------|----------|----------------|----------------|--------------|-------------|\n"
+		for _, revision := range top {
+			pct := "-"
+			if revision.OldArea != 0 {
+				pct = fmt.Sprintf("%+.1f%%", revision.DifferencePct)
+			}
+			report += fmt.Sprintf("| %d | %s | %s | %s | %s | %s | %s |\n", revision.Year, revision.Region, revision.Province,
+				formatNumber(revision.OldArea), formatNumber(revision.NewArea), formatSignedNumber(revision.Difference), pct)
+		}
+		if len(comparison.Revisions) > len(top) {
+			report += trf("\nDaftar lengkap %d revisi ada di %s.\n", len(comparison.Revisions), comparisonExcelFile)
+		}
+	}
+
+	report += tr("\n### 🏛️ DAMPAK PADA MODEL PROVINSI\n\n")
+	var changed []ProvinceModelChange
+	for _, c := range comparison.Provinces {
+		if c.changed(comparison.Tolerance) {
+			changed = append(changed, c)
+		}
+	}
+	if len(changed) == 0 {
+		report += tr("Tidak ada perubahan berarti pada {{metric}}, peringkat, kategori, potensi investasi maupun risiko provinsi.\n")
+		return report
+	}
+
+	report += tr("| Provinsi | {{Metric}} 2022 ({{unit}}) | Pertumbuhan 20 Tahun | Peringkat | Kategori | Potensi Investasi | Risiko |\n")
+	report += "|----------|----------------|----------------------|-----------|----------|-------------------|--------|\n"
+	for _, c := range changed {
+		report += fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n", c.Province,
+			comparisonArrow(c, formatNumber(c.Old.TotalArea2022), formatNumber(c.New.TotalArea2022)),
+			comparisonArrow(c, fmt.Sprintf("%.1f%%", c.Old.GrowthRate20Years), fmt.Sprintf("%.1f%%", c.New.GrowthRate20Years)),
+			comparisonArrow(c, fmt.Sprint(c.Old.Rank2022), fmt.Sprint(c.New.Rank2022)),
+			comparisonArrow(c, translateCategories(c.OldCategories), translateCategories(c.NewCategories)),
+			comparisonArrow(c, tr(c.Old.InvestmentPotential), tr(c.New.InvestmentPotential)),
+			comparisonArrow(c, tr(c.Old.RiskLevel), tr(c.New.RiskLevel)))
+	}
+	return report
+}
+
+// comparisonArrow menulis "lama → baru" bila nilai berubah, satu nilai bila
+// sama, dan menandai provinsi yang hanya ada di salah satu dataset.
+func comparisonArrow(c ProvinceModelChange, before, after string) string {
+	switch {
+	case !c.InOld:
+		return tr("baru") + ": " + after
+	case !c.InNew:
+		return tr("dihapus")
+	case before == after:
+		return after
+	}
+	return before + " → " + after
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+// comparisonTestCubes: ID-1102 hilang, ID-1501 baru, ID-1401 berganti nama
+// dan mendapat tahun 2022, sedangkan ID-1101 direvisi +1 (2020) dan +2
+// (2021) serta ID-1401 -10 (2021).
+func comparisonTestCubes() (*DataCube, *DataCube) {
+	oldData := []RawPalmOilData{
+		{Year: 2020, Region: "KAB A", RegionID: "ID-1101", ParentRegion: "ACEH", Values: []float64{100}},
+		{Year: 2021, Region: "KAB A", RegionID: "ID-1101", ParentRegion: "ACEH", Values: []float64{150}},
+		{Year: 2020, Region: "KAB B", RegionID: "ID-1102", ParentRegion: "ACEH", Values: []float64{50}},
+		{Year: 2021, Region: "KAB B", RegionID: "ID-1102", ParentRegion: "ACEH", Values: []float64{60}},
+		{Year: 2020, Region: "KAB C", RegionID: "ID-1401", ParentRegion: "RIAU", Values: []float64{300}},
+		{Year: 2021, Region: "KAB C", RegionID: "ID-1401", ParentRegion: "RIAU", Values: []float64{200}},
+	}
+	newData := []RawPalmOilData{
+		{Year: 2020, Region: "KAB A", RegionID: "ID-1101", ParentRegion: "ACEH", Values: []float64{101}},
+		{Year: 2021, Region: "KAB A", RegionID: "ID-1101", ParentRegion: "ACEH", Values: []float64{152}},
+		{Year: 2020, Region: "KAB C BARU", RegionID: "ID-1401", ParentRegion: "RIAU", Values: []float64{300}},
+		{Year: 2021, Region: "KAB C BARU", RegionID: "ID-1401", ParentRegion: "RIAU", Values: []float64{190}},
+		{Year: 2022, Region: "KAB C BARU", RegionID: "ID-1401", ParentRegion: "RIAU", Values: []float64{210}},
+		{Year: 2021, Region: "KAB D", RegionID: "ID-1501", ParentRegion: "JAMBI", Values: []float64{40}},
+	}
+	return newDataCube(oldData, 0), newDataCube(newData, 0)
+}
+
+func TestCompareDatasets(t *testing.T) {
+	tests := []struct {
+		name          string
+		tolerance     float64
+		wantRevisions []string
+		wantNet       float64
+	}{
+		{"tanpa toleransi", 0, []string{"ID-1401/2021", "ID-1101/2021", "ID-1101/2020"}, -7},
+		// Selisih yang sama dengan toleransi tidak dihitung sebagai revisi.
+		{"tepat toleransi 1", 1, []string{"ID-1401/2021", "ID-1101/2021"}, -8},
+		{"tepat toleransi 2", 2, []string{"ID-1401/2021"}, -10},
+		{"toleransi besar", 10, nil, 0},
+	}
+	oldCube, newCube := comparisonTestCubes()
+	for _, test := range tests {
+		comparison, err := compareDatasets(oldCube, newCube, test.tolerance)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if comparison.OldRows != 6 || comparison.NewRows != 6 || comparison.AddedRows != 2 || comparison.RemovedRows != 2 {
+			t.Errorf("%s: baris lama %d, baru %d, tambah %d, hilang %d, seharusnya 6, 6, 2, 2", test.name,
+				comparison.OldRows, comparison.NewRows, comparison.AddedRows, comparison.RemovedRows)
+		}
+
+		var regions []string
+		for _, region := range comparison.Regions {
+			regions = append(regions, fmt.Sprintf("%s %s %s/%s %d", region.Status, region.RegionID, region.OldRegion, region.Region, region.Years))
+		}
+		wantRegions := []string{"ADDED ID-1501 /KAB D 1", "REMOVED ID-1102 /KAB B 2", "RENAMED ID-1401 KAB C/KAB C BARU 3"}
+		if !slices.Equal(regions, wantRegions) {
+			t.Errorf("%s: wilayah berubah %q, seharusnya %q", test.name, regions, wantRegions)
+		}
+
+		var revisions []string
+		for _, revision := range comparison.Revisions {
+			revisions = append(revisions, fmt.Sprintf("%s/%d", revision.RegionID, revision.Year))
+		}
+		if !slices.Equal(revisions, test.wantRevisions) {
+			t.Errorf("%s: revisi %v, seharusnya %v", test.name, revisions, test.wantRevisions)
+		}
+		if math.Abs(comparison.NetRevision-test.wantNet) > 1e-9 {
+			t.Errorf("%s: revisi bersih %v, seharusnya %v", test.name, comparison.NetRevision, test.wantNet)
+		}
+		if len(comparison.Revisions) > 0 {
+			first := comparison.Revisions[0]
+			if first.OldArea != 200 || first.NewArea != 190 || first.Region != "KAB C BARU" || math.Abs(first.DifferencePct+5) > 1e-9 {
+				t.Errorf("%s: revisi terbesar %+v, seharusnya KAB C BARU 200 -> 190 (-5%%)", test.name, first)
+			}
+		}
+	}
+}
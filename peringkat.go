@@ -0,0 +1,412 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Peringkat pangsa pasar dalam satu tahun selalu sama dengan peringkat area,
+// jadi dimensi pangsa memeringkat kenaikan pangsa (poin persen) terhadap tahun
+// sebelumnya.
+const bumpChartProvinces = 15
+
+type RankHistory struct {
+	Area      map[int]int
+	Growth    map[int]int
+	ShareGain map[int]int
+}
+
+type RankCorrelation struct {
+	FromYear int
+	ToYear   int
+	Spearman float64
+	Kendall  float64
+}
+
+type RankMovement struct {
+	Province string
+	FromRank int
+	ToRank   int
+	Change   int
+}
+
+type RankMobility struct {
+	Correlations []RankCorrelation
+	Climbers     []RankMovement
+	Fallers      []RankMovement
+}
+
+func assignRankHistory(models []ProvinceModel) {
+	for i := range models {
+		models[i].Ranks = RankHistory{
+			Area:      make(map[int]int),
+			Growth:    make(map[int]int),
+			ShareGain: make(map[int]int),
+		}
+	}
+
+	for year := 2003; year <= 2022; year++ {
+		areas := make([]float64, len(models))
+		growths := make([]float64, len(models))
+		shareGains := make([]float64, len(models))
+		for i, model := range models {
+			areas[i] = model.YearlyData[year]
+			if previous := model.YearlyData[year-1]; previous > 0 {
+				growths[i] = (areas[i] - previous) / previous * 100
+			} else {
+				growths[i] = math.Inf(-1)
+			}
+			shareGains[i] = model.MarketShareHistory[year] - model.MarketShareHistory[year-1]
+		}
+
+		areaRanks := ordinalRanks(models, areas)
+		for i := range models {
+			models[i].Ranks.Area[year] = areaRanks[i]
+		}
+		if year > 2003 {
+			growthRanks := ordinalRanks(models, growths)
+			shareRanks := ordinalRanks(models, shareGains)
+			for i := range models {
+				models[i].Ranks.Growth[year] = growthRanks[i]
+				models[i].Ranks.ShareGain[year] = shareRanks[i]
+			}
+		}
+	}
+}
+
+func ordinalRanks(models []ProvinceModel, values []float64) []int {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if values[order[a]] != values[order[b]] {
+			return values[order[a]] > values[order[b]]
+		}
+		return models[order[a]].Province < models[order[b]].Province
+	})
+
+	ranks := make([]int, len(values))
+	for rank, i := range order {
+		ranks[i] = rank + 1
+	}
+	return ranks
+}
+
+func analyzeRankMobility(models []ProvinceModel) RankMobility {
+	var mobility RankMobility
+
+	pairs := [][2]int{{2003, 2022}, {2003, 2012}, {2013, 2022}}
+	for year := 2004; year <= 2022; year++ {
+		pairs = append(pairs, [2]int{year - 1, year})
+	}
+
+	for _, pair := range pairs {
+		from := make([]float64, len(models))
+		to := make([]float64, len(models))
+		for i, model := range models {
+			from[i] = model.YearlyData[pair[0]]
+			to[i] = model.YearlyData[pair[1]]
+		}
+		mobility.Correlations = append(mobility.Correlations, RankCorrelation{
+			FromYear: pair[0],
+			ToYear:   pair[1],
+			Spearman: spearmanCorrelation(from, to),
+			Kendall:  kendallTauB(from, to),
+		})
+	}
+
+	var movements []RankMovement
+	for _, model := range models {
+		movements = append(movements, RankMovement{
+			Province: model.Province,
+			FromRank: model.Ranks.Area[2003],
+			ToRank:   model.Ranks.Area[2022],
+			Change:   model.Ranks.Area[2003] - model.Ranks.Area[2022],
+		})
+	}
+	sort.SliceStable(movements, func(i, j int) bool {
+		return movements[i].Change > movements[j].Change
+	})
+	for i := 0; i < 5 && i < len(movements) && movements[i].Change > 0; i++ {
+		mobility.Climbers = append(mobility.Climbers, movements[i])
+	}
+	for i := len(movements) - 1; i >= len(movements)-5 && i >= 0 && movements[i].Change < 0; i-- {
+		mobility.Fallers = append(mobility.Fallers, movements[i])
+	}
+
+	return mobility
+}
+
+func averageRanks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return values[order[a]] > values[order[b]]
+	})
+
+	ranks := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start
+		for end+1 < len(order) && values[order[end+1]] == values[order[start]] {
+			end++
+		}
+		average := float64(start+end)/2 + 1
+		for k := start; k <= end; k++ {
+			ranks[order[k]] = average
+		}
+		start = end + 1
+	}
+	return ranks
+}
+
+func spearmanCorrelation(x, y []float64) float64 {
+	return pearsonCorrelation(averageRanks(x), averageRanks(y))
+}
+
+func pearsonCorrelation(x, y []float64) float64 {
+	n := float64(len(x))
+	if n < 2 {
+		return 0
+	}
+	meanX, meanY := 0.0, 0.0
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	covariance, varianceX, varianceY := 0.0, 0.0, 0.0
+	for i := range x {
+		covariance += (x[i] - meanX) * (y[i] - meanY)
+		varianceX += (x[i] - meanX) * (x[i] - meanX)
+		varianceY += (y[i] - meanY) * (y[i] - meanY)
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return 0
+	}
+	return covariance / math.Sqrt(varianceX*varianceY)
+}
+
+// kendallTauB menghitung tau-b = (nc - nd) / sqrt((n0 - n1)(n0 - n2)), dengan
+// n1 dan n2 jumlah pasangan yang seri di x dan di y; pasangan yang seri di
+// keduanya (mis. provinsi dengan area nol di kedua tahun) masuk ke n1 dan n2.
+func kendallTauB(x, y []float64) float64 {
+	concordant, discordant := 0.0, 0.0
+	tiesX, tiesY := 0.0, 0.0
+	for i := 0; i < len(x); i++ {
+		for j := i + 1; j < len(x); j++ {
+			dx := x[i] - x[j]
+			dy := y[i] - y[j]
+			if dx == 0 {
+				tiesX++
+			}
+			if dy == 0 {
+				tiesY++
+			}
+			switch {
+			case dx*dy > 0:
+				concordant++
+			case dx*dy < 0:
+				discordant++
+			}
+		}
+	}
+	pairs := float64(len(x)*(len(x)-1)) / 2
+	denominator := math.Sqrt((pairs - tiesX) * (pairs - tiesY))
+	if denominator == 0 {
+		return 0
+	}
+	return (concordant - discordant) / denominator
+}
+
+func writeRankHistorySheets(f *excelize.File, models []ProvinceModel, mobility RankMobility) {
+	sheet := tr("Riwayat_Peringkat")
+	f.NewSheet(sheet)
+
+	row := 1
+	for _, block := range []struct {
+		title string
+		ranks func(model ProvinceModel) map[int]int
+		start int
+	}{
+		{"PERINGKAT BERDASARKAN {{METRIC}}", func(m ProvinceModel) map[int]int { return m.Ranks.Area }, 2003},
+		{"PERINGKAT BERDASARKAN PERTUMBUHAN TAHUNAN", func(m ProvinceModel) map[int]int { return m.Ranks.Growth }, 2004},
+		{"PERINGKAT BERDASARKAN KENAIKAN PANGSA PASAR", func(m ProvinceModel) map[int]int { return m.Ranks.ShareGain }, 2004},
+	} {
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), tr(block.title))
+		row++
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), tr("Provinsi"))
+		f.SetColWidth(sheet, "A", "A", 24)
+		for year := block.start; year <= 2022; year++ {
+			cell, _ := excelize.CoordinatesToCellName(year-block.start+2, row)
+			f.SetCellValue(sheet, cell, year)
+		}
+		row++
+
+		for _, model := range models {
+			f.SetCellValue(sheet, fmt.Sprintf("A%d", row), model.Province)
+			ranks := block.ranks(model)
+			for year := block.start; year <= 2022; year++ {
+				cell, _ := excelize.CoordinatesToCellName(year-block.start+2, row)
+				f.SetCellValue(sheet, cell, ranks[year])
+			}
+			row++
+		}
+		row++
+	}
+
+	sheet = tr("Mobilitas_Peringkat")
+	f.NewSheet(sheet)
+
+	headers := []string{"Dari Tahun", "Ke Tahun", "Spearman ρ", "Kendall τ-b"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 16)
+	}
+	for i, correlation := range mobility.Correlations {
+		row := i + 2
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), correlation.FromYear)
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), correlation.ToYear)
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), fmt.Sprintf("%.3f", correlation.Spearman))
+		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), fmt.Sprintf("%.3f", correlation.Kendall))
+	}
+
+	row = len(mobility.Correlations) + 3
+	for _, group := range []struct {
+		title     string
+		movements []RankMovement
+	}{
+		{"PENDAKI TERBESAR 2003-2022", mobility.Climbers},
+		{"PENURUNAN TERBESAR 2003-2022", mobility.Fallers},
+	} {
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), tr(group.title))
+		row++
+		for i, header := range []string{"Provinsi", "Rank 2003", "Rank 2022", "Perubahan"} {
+			cell, _ := excelize.CoordinatesToCellName(i+1, row)
+			f.SetCellValue(sheet, cell, tr(header))
+		}
+		row++
+		for _, movement := range group.movements {
+			f.SetCellValue(sheet, fmt.Sprintf("A%d", row), movement.Province)
+			f.SetCellValue(sheet, fmt.Sprintf("B%d", row), movement.FromRank)
+			f.SetCellValue(sheet, fmt.Sprintf("C%d", row), movement.ToRank)
+			f.SetCellValue(sheet, fmt.Sprintf("D%d", row), fmt.Sprintf("%+d", movement.Change))
+			row++
+		}
+		row++
+	}
+}
+
+func createBumpChart(models []ProvinceModel) error {
+	p := plot.New()
+	p.Title.Text = trf("PERGERAKAN PERINGKAT %d PROVINSI TERATAS 2003-2022", bumpChartProvinces)
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Tahun")
+	p.Y.Label.Text = tr("Peringkat {{Metric}}")
+
+	top := models
+	if len(top) > bumpChartProvinces {
+		top = top[:bumpChartProvinces]
+	}
+
+	maxRank := 0
+	var endPoints plotter.XYs
+	var endLabels []string
+	for i, model := range top {
+		points := make(plotter.XYs, 0, 20)
+		for year := 2003; year <= 2022; year++ {
+			rank := model.Ranks.Area[year]
+			if rank > maxRank {
+				maxRank = rank
+			}
+			points = append(points, plotter.XY{X: float64(year), Y: -float64(rank)})
+		}
+
+		line, glyphs, err := plotter.NewLinePoints(points)
+		if err != nil {
+			return err
+		}
+		line.Color = bumpColor(i)
+		line.Width = vg.Points(2.5)
+		glyphs.Color = bumpColor(i)
+		glyphs.Shape = draw.CircleGlyph{}
+		glyphs.Radius = vg.Points(3)
+		p.Add(line, glyphs)
+
+		endPoints = append(endPoints, plotter.XY{X: 2022.2, Y: -float64(model.Ranks.Area[2022])})
+		endLabels = append(endLabels, getShortProvinceName(model.Province))
+	}
+
+	labels, err := plotter.NewLabels(plotter.XYLabels{XYs: endPoints, Labels: endLabels})
+	if err != nil {
+		return err
+	}
+	for i := range labels.TextStyle {
+		labels.TextStyle[i].YAlign = draw.YCenter
+	}
+	p.Add(labels)
+
+	var ticks []plot.Tick
+	for rank := 1; rank <= maxRank; rank++ {
+		ticks = append(ticks, plot.Tick{Value: -float64(rank), Label: fmt.Sprintf("%d", rank)})
+	}
+	p.Y.Tick.Marker = plot.ConstantTicks(ticks)
+	p.Y.Min = -float64(maxRank) - 0.5
+	p.Y.Max = -0.5
+	p.X.Min = 2002.5
+	p.X.Max = 2024
+	p.Add(plotter.NewGrid())
+
+	return saveChart(p, 20*vg.Inch, 12*vg.Inch, "bump_chart_peringkat_20tahun")
+}
+
+func bumpColor(index int) color.RGBA {
+	return chartTheme.Series[index%len(chartTheme.Series)]
+}
+
+func buildRankMobilityReport(mobility RankMobility) string {
+	report := tr("\n### 🔀 MOBILITAS PERINGKAT PROVINSI 2003-2022\n\n")
+
+	for _, correlation := range mobility.Correlations {
+		if correlation.ToYear-correlation.FromYear > 1 {
+			report += trf("- Korelasi peringkat %d vs %d: Spearman ρ = %.3f, Kendall τ-b = %.3f\n",
+				correlation.FromYear, correlation.ToYear, correlation.Spearman, correlation.Kendall)
+		}
+	}
+
+	if len(mobility.Climbers) > 0 {
+		report += tr("\n**Pendaki terbesar:** ")
+		for i, movement := range mobility.Climbers {
+			if i > 0 {
+				report += ", "
+			}
+			report += fmt.Sprintf("%s (#%d → #%d)", movement.Province, movement.FromRank, movement.ToRank)
+		}
+		report += "\n"
+	}
+	if len(mobility.Fallers) > 0 {
+		report += tr("\n**Penurunan terbesar:** ")
+		for i, movement := range mobility.Fallers {
+			if i > 0 {
+				report += ", "
+			}
+			report += fmt.Sprintf("%s (#%d → #%d)", movement.Province, movement.FromRank, movement.ToRank)
+		}
+		report += "\n"
+	}
+
+	return report
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKendallTauB(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		{"tanpa seri", []float64{1, 2, 3, 4, 5}, []float64{3, 1, 2, 5, 4}, 0.4},
+		{"urutan sama", []float64{1, 2, 3, 4}, []float64{10, 20, 30, 40}, 1},
+		{"urutan terbalik", []float64{1, 2, 3, 4}, []float64{40, 30, 20, 10}, -1},
+		// Contoh dokumentasi scipy.stats.kendalltau, dengan satu pasangan
+		// yang seri di x dan y sekaligus.
+		{"seri bersama", []float64{12, 2, 1, 12, 2}, []float64{1, 4, 7, 1, 0}, -0.47140452079103173},
+		// Tiga provinsi berarea nol pada tahun pertama, dua di antaranya
+		// masih nol pada tahun kedua (y = 0, 0, 1): satu pasangan seri
+		// bersama, dua pasangan seri hanya di x.
+		{"area nol", []float64{0, 0, 0, 1, 2, 3}, []float64{0, 0, 1, 1, 3, 2}, 9 / math.Sqrt(12*13)},
+		{"x konstan", []float64{5, 5, 5}, []float64{1, 2, 3}, 0},
+	}
+	for _, test := range tests {
+		if got := kendallTauB(test.x, test.y); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("%s: kendallTauB = %.15f, seharusnya %.15f", test.name, got, test.want)
+		}
+	}
+}
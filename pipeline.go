@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Pipeline keluaran dijalankan sebagai graf dependensi: setiap tahap mulai
+// segera setelah semua tahap di Needs selesai, dibatasi pipelineParallelism
+// tahap sekaligus (flag -jobs). Hasil antar tahap dibagi lewat variabel yang
+// ditangkap closure; selesainya tahap (penutupan channel done) menjamin
+// penulisan hasil terlihat oleh tahap yang bergantung padanya. Error pertama
+// membatalkan context sehingga tahap yang belum mulai dilewati, dan error
+// tersebut dikembalikan dengan nama tahapnya; Ctrl+C membatalkan dengan cara
+// yang sama. Validasi flag dan file konfigurasi tetap berhenti lewat log.Fatal
+// sebelum pipeline dimulai.
+var pipelineParallelism = runtime.NumCPU()
+
+type pipelineStage struct {
+	Name  string
+	Needs []string
+	Run   func(ctx context.Context) error
+}
+
+func runPipeline(ctx context.Context, stages []pipelineStage) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(map[string]chan struct{}, len(stages))
+	for _, stage := range stages {
+		if done[stage.Name] != nil {
+			return fmt.Errorf("tahap pipeline %q didefinisikan dua kali", stage.Name)
+		}
+		done[stage.Name] = make(chan struct{})
+	}
+	for _, stage := range stages {
+		for _, need := range stage.Needs {
+			if done[need] == nil {
+				return fmt.Errorf("tahap pipeline %q membutuhkan tahap yang tidak ada: %q", stage.Name, need)
+			}
+		}
+	}
+
+	slots := make(chan struct{}, max(1, pipelineParallelism))
+	var wg sync.WaitGroup
+	for _, stage := range stages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[stage.Name])
+
+			for _, need := range stage.Needs {
+				select {
+				case <-done[need]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slots }()
+
+			if ctx.Err() != nil {
+				return
+			}
+			if err := stage.Run(ctx); err != nil {
+				cancel(fmt.Errorf("%s: %w", stage.Name, err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return nil
+}
+
+// parallelFor menjalankan fn(i) untuk i = 0..n-1 dengan paling banyak
+// pipelineParallelism goroutine, dan berhenti membagikan pekerjaan baru
+// setelah error pertama atau pembatalan context.
+func parallelFor(ctx context.Context, n int, fn func(i int) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(n, max(1, pipelineParallelism)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(i); err != nil {
+					cancel(err)
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range n {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return nil
+}
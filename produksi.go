@@ -0,0 +1,427 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Data produksi opsional: produksi TBS (tandan buah segar) dan CPO dalam
+// ton per provinsi atau kabupaten per tahun. Kolom wajib year, province dan
+// ffb_tonnes; cpo_tonnes dan region_trase_id opsional. Baris tanpa
+// region_trase_id adalah total provinsi; bila suatu provinsi-tahun hanya
+// memiliki baris kabupaten, totalnya dijumlahkan dari baris tersebut. Hasil
+// per hektar dihitung terhadap area tertanam, sehingga hanya tersedia bila
+// metrik utama berupa luas (ha).
+const productionFile = "produksi_sawit.csv"
+
+// yieldBenchmarkSize adalah jumlah provinsi dengan hasil tertinggi yang
+// dirata-ratakan sebagai patokan kesenjangan hasil.
+const yieldBenchmarkSize = 3
+
+type ProductionRecord struct {
+	FFB float64
+	CPO float64
+}
+
+type ProductionData struct {
+	Provinces map[string]map[int]ProductionRecord
+	Regencies map[string]map[int]ProductionRecord
+}
+
+type YieldPoint struct {
+	Year     int
+	FFB      float64
+	CPO      float64
+	Area     float64
+	FFBYield float64
+	CPOYield float64
+}
+
+// ProvinceYield adalah produktivitas satu provinsi: hasil tahun terakhir
+// yang memiliki data produksi, tren hasil TBS (t/ha per tahun, regresi
+// linear) dan kesenjangan terhadap rata-rata provinsi terbaik tahun itu.
+type ProvinceYield struct {
+	Points         []YieldPoint
+	LatestYear     int
+	FFBYield       float64
+	CPOYield       float64
+	ExtractionRate float64
+	Trend          float64
+	Benchmark      float64
+	Gap            float64
+}
+
+type RegencyYield struct {
+	RegionID string
+	Regency  string
+	Province string
+	YieldPoint
+}
+
+// loadProductionData mengembalikan nil tanpa error bila file tidak ada.
+func loadProductionData() (*ProductionData, error) {
+	records, err := readOptionalCSV(productionFile)
+	if err != nil || records == nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%s tidak memiliki baris data", productionFile)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"year", "province", "ffb_tonnes"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("kolom %q tidak ada di header %s", required, productionFile)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	data := &ProductionData{
+		Provinces: make(map[string]map[int]ProductionRecord),
+		Regencies: make(map[string]map[int]ProductionRecord),
+	}
+	regencyTotals := make(map[string]map[int]ProductionRecord)
+	for line, record := range records[1:] {
+		year, err := strconv.Atoi(field(record, "year"))
+		if err != nil {
+			return nil, fmt.Errorf("%s baris %d: tahun tidak valid %q", productionFile, line+2, field(record, "year"))
+		}
+		ffb, err := strconv.ParseFloat(field(record, "ffb_tonnes"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s baris %d: ffb_tonnes tidak valid %q", productionFile, line+2, field(record, "ffb_tonnes"))
+		}
+		cpo, _ := strconv.ParseFloat(field(record, "cpo_tonnes"), 64)
+
+		province := strings.ToUpper(field(record, "province"))
+		value := ProductionRecord{FFB: ffb, CPO: cpo}
+		if regency := field(record, "region_trase_id"); regency != "" {
+			addProduction(data.Regencies, regency, year, value)
+			addProduction(regencyTotals, province, year, value)
+		} else {
+			addProduction(data.Provinces, province, year, value)
+		}
+	}
+	for province, years := range regencyTotals {
+		for year, value := range years {
+			if _, ok := data.Provinces[province][year]; !ok {
+				addProduction(data.Provinces, province, year, value)
+			}
+		}
+	}
+
+	fmt.Fprintf(progressOutput, "🌾 Data produksi dibaca: %d provinsi, %d kabupaten dari %s\n", len(data.Provinces), len(data.Regencies), productionFile)
+	return data, nil
+}
+
+func addProduction(target map[string]map[int]ProductionRecord, key string, year int, value ProductionRecord) {
+	if target[key] == nil {
+		target[key] = make(map[int]ProductionRecord)
+	}
+	current := target[key][year]
+	target[key][year] = ProductionRecord{FFB: current.FFB + value.FFB, CPO: current.CPO + value.CPO}
+}
+
+func newYieldPoint(year int, production ProductionRecord, area float64) YieldPoint {
+	return YieldPoint{
+		Year:     year,
+		FFB:      production.FFB,
+		CPO:      production.CPO,
+		Area:     area,
+		FFBYield: production.FFB / area,
+		CPOYield: production.CPO / area,
+	}
+}
+
+// applyProvinceYields mengisi ProvinceModel.Yield untuk provinsi yang
+// memiliki data produksi, lalu menghitung patokan dan kesenjangan hasil
+// per tahun terakhir masing-masing provinsi.
+func applyProvinceYields(models []ProvinceModel, production *ProductionData) {
+	if production == nil {
+		return
+	}
+	if metricUnit() != defaultMetric.Unit {
+		fmt.Fprintf(progressOutput, "⚠️  Hasil per hektar dilewati: metrik utama %s bukan luas (%s)\n", primaryMetric().Name, defaultMetric.Unit)
+		return
+	}
+
+	yieldsByYear := make(map[int][]float64)
+	for i := range models {
+		years, ok := production.Provinces[strings.ToUpper(models[i].Province)]
+		if !ok {
+			continue
+		}
+		var points []YieldPoint
+		for _, year := range getSortedYears(models[i].YearlyData) {
+			value, ok := years[year]
+			if area := models[i].YearlyData[year]; ok && area > 0 && value.FFB > 0 {
+				points = append(points, newYieldPoint(year, value, area))
+				yieldsByYear[year] = append(yieldsByYear[year], points[len(points)-1].FFBYield)
+			}
+		}
+		if len(points) == 0 {
+			continue
+		}
+
+		latest := points[len(points)-1]
+		series := make(map[int]float64, len(points))
+		for _, point := range points {
+			series[point.Year] = point.FFBYield
+		}
+		yield := &ProvinceYield{
+			Points:     points,
+			LatestYear: latest.Year,
+			FFBYield:   latest.FFBYield,
+			CPOYield:   latest.CPOYield,
+		}
+		yield.Trend, _ = linearTrend(series)
+		if latest.FFB > 0 {
+			yield.ExtractionRate = latest.CPO / latest.FFB * 100
+		}
+		models[i].Yield = yield
+	}
+
+	for i := range models {
+		yield := models[i].Yield
+		if yield == nil {
+			continue
+		}
+		yield.Benchmark = yieldBenchmark(yieldsByYear[yield.LatestYear])
+		if yield.Benchmark > 0 {
+			yield.Gap = (yield.Benchmark - yield.FFBYield) / yield.Benchmark * 100
+		}
+	}
+}
+
+// yieldBenchmark adalah rata-rata yieldBenchmarkSize hasil tertinggi.
+func yieldBenchmark(yields []float64) float64 {
+	sorted := append([]float64(nil), yields...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+	if len(sorted) > yieldBenchmarkSize {
+		sorted = sorted[:yieldBenchmarkSize]
+	}
+	total, _ := aggregateValues(sorted, "mean")
+	return total
+}
+
+func generateYieldRecommendations(model ProvinceModel) []string {
+	yield := model.Yield
+	if yield == nil {
+		return nil
+	}
+
+	var recs []string
+	switch {
+	case yield.Gap > 25:
+		recs = append(recs, trf("Close the yield gap: %.1f t FFB/ha is %.0f%% below the best performers (%.1f t/ha); prioritise replanting and best agronomic practice",
+			yield.FFBYield, yield.Gap, yield.Benchmark))
+	case yield.Gap <= 0:
+		recs = append(recs, tr("Share cultivation practices as a yield benchmark for other provinces"))
+	}
+	if yield.Trend < 0 && len(yield.Points) >= 3 {
+		recs = append(recs, trf("Yield per hectare is falling by %.2f t/ha per year; review tree age profile and fertiliser inputs", -yield.Trend))
+	}
+	return recs
+}
+
+func analyzeRegencyYields(cube *DataCube, production *ProductionData) []RegencyYield {
+	if production == nil || metricUnit() != defaultMetric.Unit {
+		return nil
+	}
+
+	var yields []RegencyYield
+	for _, regency := range cube.Regencies {
+		years, ok := production.Regencies[regency.ID]
+		if !ok {
+			continue
+		}
+		latest := 0
+		for year, value := range years {
+			if year > latest && value.FFB > 0 && cube.RegencyArea(regency.ID, year) > 0 {
+				latest = year
+			}
+		}
+		if latest == 0 {
+			continue
+		}
+		yields = append(yields, RegencyYield{
+			RegionID:   regency.ID,
+			Regency:    regency.Name,
+			Province:   regency.Province,
+			YieldPoint: newYieldPoint(latest, years[latest], cube.RegencyArea(regency.ID, latest)),
+		})
+	}
+	sort.Slice(yields, func(i, j int) bool {
+		if yields[i].FFBYield != yields[j].FFBYield {
+			return yields[i].FFBYield > yields[j].FFBYield
+		}
+		return yields[i].RegionID < yields[j].RegionID
+	})
+	if len(yields) > 0 {
+		fmt.Fprintf(progressOutput, "🌾 Hasil per hektar kabupaten dihitung: %d kabupaten\n", len(yields))
+	}
+	return yields
+}
+
+// yieldModels mengembalikan model yang memiliki data produksi, urut hasil
+// TBS tertinggi.
+func yieldModels(models []ProvinceModel) []ProvinceModel {
+	var result []ProvinceModel
+	for _, model := range models {
+		if model.Yield != nil {
+			result = append(result, model)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Yield.FFBYield > result[j].Yield.FFBYield
+	})
+	return result
+}
+
+func writeYieldSheets(f *excelize.File, models []ProvinceModel, regencies []RegencyYield) {
+	withYield := yieldModels(models)
+	if len(withYield) == 0 {
+		return
+	}
+
+	sheet := tr("Produktivitas")
+	f.NewSheet(sheet)
+	headers := []string{"Provinsi", "Tahun", "Produksi TBS (t)", "Produksi CPO (t)", "Area (ha)", "Hasil TBS (t/ha)",
+		"Hasil CPO (t/ha)", "Rendemen CPO (%)", "Tren Hasil (t/ha/tahun)", "Patokan (t/ha)", "Kesenjangan (%)", "Efisiensi Produksi (0-10)"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell[:1], cell[:1], 18)
+	}
+	for i, model := range withYield {
+		row := i + 2
+		yield := model.Yield
+		latest := yield.Points[len(yield.Points)-1]
+		values := []interface{}{model.Province, yield.LatestYear, math.Round(latest.FFB), math.Round(latest.CPO), math.Round(latest.Area),
+			math.Round(yield.FFBYield*100) / 100, math.Round(yield.CPOYield*100) / 100, math.Round(yield.ExtractionRate*10) / 10,
+			math.Round(yield.Trend*1000) / 1000, math.Round(yield.Benchmark*100) / 100, math.Round(yield.Gap*10) / 10,
+			math.Round(model.ProductionEfficiency*100) / 100}
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, row)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+
+	if len(regencies) == 0 {
+		return
+	}
+	sheet = tr("Produktivitas_Kabupaten")
+	f.NewSheet(sheet)
+	headers = []string{"Region ID", "Kabupaten", "Provinsi", "Tahun", "Produksi TBS (t)", "Area (ha)", "Hasil TBS (t/ha)", "Hasil CPO (t/ha)"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell[:1], cell[:1], 18)
+	}
+	for i, regency := range regencies {
+		row := i + 2
+		values := []interface{}{regency.RegionID, regency.Regency, regency.Province, regency.Year, math.Round(regency.FFB),
+			math.Round(regency.Area), math.Round(regency.FFBYield*100) / 100, math.Round(regency.CPOYield*100) / 100}
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, row)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+}
+
+// createYieldTrendChart menggambar tren hasil TBS provinsi yang memiliki
+// data produksi (maksimal delapan provinsi dengan area terbesar).
+func createYieldTrendChart(models []ProvinceModel) error {
+	var withYield []ProvinceModel
+	for _, model := range models {
+		if model.Yield != nil && len(model.Yield.Points) > 0 {
+			withYield = append(withYield, model)
+		}
+	}
+	if len(withYield) == 0 {
+		return nil
+	}
+	sort.SliceStable(withYield, func(i, j int) bool { return withYield[i].TotalArea2022 > withYield[j].TotalArea2022 })
+	if len(withYield) > 8 {
+		withYield = withYield[:8]
+	}
+
+	p := plot.New()
+	p.Title.Text = tr("TREN HASIL TBS PER HEKTAR PROVINSI")
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Tahun")
+	p.Y.Label.Text = tr("Hasil TBS (t/ha)")
+
+	for i, model := range withYield {
+		points := make(plotter.XYs, len(model.Yield.Points))
+		for j, point := range model.Yield.Points {
+			points[j].X = float64(point.Year)
+			points[j].Y = point.FFBYield
+		}
+		line, scatter, err := plotter.NewLinePoints(points)
+		if err != nil {
+			return err
+		}
+		lineColor := chartTheme.Series[i%len(chartTheme.Series)]
+		line.Color = lineColor
+		line.Width = vg.Points(2)
+		scatter.Color = lineColor
+		p.Add(line, scatter)
+		p.Legend.Add(getShortProvinceName(model.Province), line)
+	}
+
+	p.Legend.Top = true
+	p.Add(plotter.NewGrid())
+	return saveChart(p, 14*vg.Inch, 8*vg.Inch, "tren_hasil_tbs_provinsi")
+}
+
+func buildYieldReport(models []ProvinceModel, regencies []RegencyYield) string {
+	withYield := yieldModels(models)
+	if len(withYield) == 0 {
+		return ""
+	}
+
+	report := tr("\n### 🌾 PRODUKTIVITAS (HASIL PER HEKTAR)\n\n")
+	report += trf("Data produksi tersedia untuk %d provinsi. Untuk provinsi tersebut skor efisiensi produksi (0-10) dihitung dari hasil TBS, tren hasil dan rendemen CPO terhadap rentang patokan tetap, menggantikan efisiensi berbasis area, sehingga ikut menentukan skor daya saing dan potensi investasi.\n\n", len(withYield))
+	report += tr("| Provinsi | Tahun | Hasil TBS (t/ha) | Hasil CPO (t/ha) | Rendemen CPO | Tren (t/ha/tahun) | Kesenjangan vs Patokan | Efisiensi Produksi |\n")
+	report += "|----------|-------|------------------|------------------|--------------|-------------------|------------------------|--------------------|\n"
+	for _, model := range withYield {
+		yield := model.Yield
+		cpoYield, extraction := "-", "-"
+		if yield.CPOYield > 0 {
+			cpoYield, extraction = fmt.Sprintf("%.2f", yield.CPOYield), fmt.Sprintf("%.1f%%", yield.ExtractionRate)
+		}
+		report += fmt.Sprintf("| %s | %d | %.2f | %s | %s | %+.3f | %.1f%% | %.2f |\n", model.Province, yield.LatestYear,
+			yield.FFBYield, cpoYield, extraction, yield.Trend, yield.Gap, model.ProductionEfficiency)
+	}
+
+	best, worst := withYield[0], withYield[len(withYield)-1]
+	report += trf("\nHasil tertinggi dicapai **%s** (%.2f t TBS/ha pada %d), terendah **%s** (%.2f t/ha). Patokan dihitung dari rata-rata %d provinsi dengan hasil tertinggi pada tahun yang sama.\n",
+		best.Province, best.Yield.FFBYield, best.Yield.LatestYear, worst.Province, worst.Yield.FFBYield, yieldBenchmarkSize)
+
+	if len(regencies) > 0 {
+		report += tr("\n**Kabupaten dengan hasil TBS tertinggi:**\n\n")
+		report += tr("| Kabupaten | Provinsi | Tahun | Hasil TBS (t/ha) |\n")
+		report += "|-----------|----------|-------|------------------|\n"
+		for i := 0; i < len(regencies) && i < 10; i++ {
+			regency := regencies[i]
+			report += fmt.Sprintf("| %s | %s | %d | %.2f |\n", regency.Regency, regency.Province, regency.Year, regency.FFBYield)
+		}
+	}
+	return report
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"gonum.org/v1/plot/vg"
+)
+
+// Profil provinsi: satu dokumen per provinsi (Markdown, HTML, PDF) untuk
+// dinas provinsi, ditulis ke profileDir beserta indeks. Isi Markdown berasal
+// dari template profileTemplate sehingga bisa diganti lewat -template-dir
+// seperti laporan strategis; HTML dan PDF dirender dari Markdown yang sama.
+const (
+	profileDir             = "profiles"
+	profileChartDir        = "grafik"
+	profileTemplate        = "profil_provinsi.md.tmpl"
+	profileIndexTemplate   = "profil_indeks.md.tmpl"
+	profilePeerRange       = 2
+	profileTopContributors = 5
+)
+
+// ProfileData adalah konteks template profil satu provinsi.
+type ProfileData struct {
+	GeneratedAt         string
+	StartYear           int
+	EndYear             int
+	TotalProvinces      int
+	Model               ProvinceModel
+	Categories          []string
+	Years               []ProfileYear
+	Chart               string
+	Regencies           []RegencyShare
+	TopContributors     []RegencyShare
+	Peers               []ProvinceModel
+	RiskReasons         []string
+	InvestmentThreshold float64
+	Scoring             ScoringConfig
+}
+
+type ProfileYear struct {
+	Year   int
+	Area   float64
+	Change float64
+	Share  float64
+	Rank   int
+	IsPeak bool
+}
+
+// RegencyShare adalah baris rincian kabupaten: Change adalah perubahan area
+// 2003-2022 (ha), Share porsi area provinsi tahun akhir dan Contribution
+// porsi perubahan area provinsi yang berasal dari kabupaten tersebut.
+type RegencyShare struct {
+	Name         string
+	Area2003     float64
+	Area2022     float64
+	Change       float64
+	Growth       float64
+	Share        float64
+	Contribution float64
+}
+
+type ProfileIndexData struct {
+	GeneratedAt string
+	StartYear   int
+	EndYear     int
+	Entries     []ProfileIndexEntry
+}
+
+type ProfileIndexEntry struct {
+	Model ProvinceModel
+	Slug  string
+}
+
+// createProvinceProfiles membuat profil per provinsi secara paralel;
+// templates adalah hash isi templateDir untuk kunci cache tiap profil.
+func createProvinceProfiles(ctx context.Context, models []ProvinceModel, cube *DataCube, scoring ScoringConfig, templateDir, templates string) error {
+	if err := os.MkdirAll(filepath.Join(profileDir, profileChartDir), 0o755); err != nil {
+		return fmt.Errorf("error membuat direktori profil: %w", err)
+	}
+
+	tmpl, err := loadReportTemplates(templateDir)
+	if err != nil {
+		return err
+	}
+	generatedAt := time.Now().Format("2 January 2006")
+	index := ProfileIndexData{GeneratedAt: generatedAt, StartYear: 2003, EndYear: 2022}
+	for _, model := range models {
+		index.Entries = append(index.Entries, ProfileIndexEntry{Model: model, Slug: fileSlug(model.Province)})
+	}
+
+	var created atomic.Int32
+	err = parallelFor(ctx, len(models), func(i int) error {
+		model, slug := models[i], index.Entries[i].Slug
+		outputs := []string{filepath.Join(profileDir, profileChartDir, slug+".png")}
+		for _, extension := range []string{".md", ".html", ".pdf"} {
+			outputs = append(outputs, filepath.Join(profileDir, slug+extension))
+		}
+		key := buildCache.key(buildCache.Inputs, model.Province, templates, chartSettings)
+		rebuilt, err := buildCache.update(filepath.Join(profileDir, slug), key, outputs, func() error {
+			data, err := buildProfileData(ctx, models, i, cube, scoring)
+			if err != nil {
+				return err
+			}
+			data.GeneratedAt = generatedAt
+			if data.Chart, err = createProfileChart(model, slug); err != nil {
+				return err
+			}
+
+			markdown, err := renderProfileTemplate(tmpl, profileTemplate, data)
+			if err != nil {
+				return err
+			}
+			return writeProfileDocuments(markdown, slug, model.Province)
+		})
+		if rebuilt {
+			created.Add(1)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	outputs := []string{filepath.Join(profileDir, "index.md"), filepath.Join(profileDir, "index.html")}
+	err = buildCache.build(filepath.Join(profileDir, "index"), buildCache.key(buildCache.Inputs, templates), outputs, func() error {
+		markdown, err := renderProfileTemplate(tmpl, profileIndexTemplate, index)
+		if err != nil {
+			return err
+		}
+		if err := writeProfileFile("index.md", markdown); err != nil {
+			return err
+		}
+		return writeProfileFile("index.html", renderMarkdownHTML(markdown, tr("Profil Provinsi Kelapa Sawit")))
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(progressOutput, "🗂️  Profil provinsi berhasil dibuat: %d provinsi di %s/ (%d dari cache)\n", created.Load(), profileDir, len(models)-int(created.Load()))
+	return nil
+}
+
+func buildProfileData(ctx context.Context, models []ProvinceModel, index int, cube *DataCube, scoring ScoringConfig) (ProfileData, error) {
+	model := models[index]
+	data := ProfileData{
+		StartYear:           2003,
+		EndYear:             2022,
+		TotalProvinces:      len(models),
+		Model:               model,
+		RiskReasons:         riskRationale(model, defaultRiskThresholds),
+		InvestmentThreshold: scoring.InvestmentThresholds[model.InvestmentPotential],
+		Categories:          modelCategories(model),
+		Scoring:             scoring,
+	}
+
+	for _, year := range getSortedYears(model.YearlyData) {
+		row := ProfileYear{
+			Year:   year,
+			Area:   model.YearlyData[year],
+			Share:  model.MarketShareHistory[year],
+			Rank:   model.Ranks.Area[year],
+			IsPeak: year == model.PeakYear && model.PeakArea > 0,
+		}
+		if previous := model.YearlyData[year-1]; previous > 0 {
+			row.Change = (row.Area - previous) / previous * 100
+		}
+		data.Years = append(data.Years, row)
+	}
+
+	regencies, _, err := buildRegencyTrajectories(ctx, cube, model.Province)
+	if err != nil {
+		return data, err
+	}
+	provinceChange := model.TotalArea2022 - model.TotalArea2003
+	for _, regency := range regencies {
+		share := RegencyShare{
+			Name:     regency.Province,
+			Area2003: regency.TotalArea2003,
+			Area2022: regency.TotalArea2022,
+			Change:   regency.TotalArea2022 - regency.TotalArea2003,
+			Growth:   regency.GrowthRate20Years,
+		}
+		if model.TotalArea2022 > 0 {
+			share.Share = regency.TotalArea2022 / model.TotalArea2022 * 100
+		}
+		if provinceChange != 0 {
+			share.Contribution = share.Change / provinceChange * 100
+		}
+		data.Regencies = append(data.Regencies, share)
+	}
+
+	data.TopContributors = append([]RegencyShare(nil), data.Regencies...)
+	sort.SliceStable(data.TopContributors, func(i, j int) bool {
+		return data.TopContributors[i].Change > data.TopContributors[j].Change
+	})
+	if len(data.TopContributors) > profileTopContributors {
+		data.TopContributors = data.TopContributors[:profileTopContributors]
+	}
+
+	data.Peers = models[max(0, index-profilePeerRange):min(len(models), index+profilePeerRange+1)]
+	return data, nil
+}
+
+// riskRationale menjelaskan pemicu yang diperiksa classifyRiskLevel, dengan
+// urutan yang sama; pemicu pertama yang terpenuhi menentukan tingkat risiko.
+func riskRationale(model ProvinceModel, t RiskThresholds) []string {
+	var reasons []string
+	if model.Deforestation != nil && model.Deforestation.Risk == "HIGH" {
+		reasons = append(reasons, trf("%.0f%% ekspansi bertepatan dengan kehilangan hutan (%d kabupaten ditandai): risiko deforestasi tinggi", model.Deforestation.CoincidingShare, model.Deforestation.FlaggedRegencies))
+	}
+	if model.GrowthRate20Years > t.HighGrowth {
+		reasons = append(reasons, trf("Pertumbuhan 20 tahun %.0f%% melebihi %.0f%%: ekspansi sangat cepat menuntut pengawasan tata kelola dan keberlanjutan", model.GrowthRate20Years, t.HighGrowth))
+	}
+	if model.StabilityIndex < t.LowStability {
+		reasons = append(reasons, trf("Indeks stabilitas %.1f di bawah %.1f: pertumbuhan tahunan sangat fluktuatif", model.StabilityIndex, t.LowStability))
+	}
+	if model.GrowthRate20Years < 0 {
+		reasons = append(reasons, trf("{{Metric}} menyusut %.0f%% selama 20 tahun", -model.GrowthRate20Years))
+	}
+	if model.MarketShare2022 < t.LowMarketShare && model.GrowthRate20Years < t.LowShareGrowth {
+		reasons = append(reasons, trf("Pangsa pasar %.2f%% di bawah %.0f%% dengan pertumbuhan %.0f%% di bawah %.0f%%: skala kecil dan momentum lemah", model.MarketShare2022, t.LowMarketShare, model.GrowthRate20Years, t.LowShareGrowth))
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, tr("Tidak ada pemicu risiko: pertumbuhan, stabilitas dan pangsa pasar dalam batas normal"))
+	}
+	return reasons
+}
+
+// createProfileChart menyimpan grafik trajektori provinsi sebagai PNG di
+// direktori profil dan mengembalikan path relatifnya untuk Markdown.
+func createProfileChart(model ProvinceModel, slug string) (string, error) {
+	p, err := createTrajectoryPanel(model, model.Province, true)
+	if err != nil {
+		return "", err
+	}
+	p.Title.TextStyle.Font.Size = vg.Points(14)
+	p.Legend.TextStyle.Font.Size = vg.Points(9)
+	p.X.Tick.Label.Font.Size = vg.Points(9)
+	p.Y.Tick.Label.Font.Size = vg.Points(9)
+	p.Y.Label.TextStyle.Font.Size = vg.Points(10)
+
+	path := filepath.Join(profileChartDir, slug+".png")
+	writer, err := chartWriter(p.Draw, 9*vg.Inch, 5*vg.Inch, "png", chartDPI())
+	if err != nil {
+		return "", err
+	}
+	if err := writeChartFile(filepath.Join(profileDir, path), writer); err != nil {
+		return "", fmt.Errorf("error menyimpan grafik profil %s: %w", path, err)
+	}
+	return filepath.ToSlash(path), nil
+}
+
+func renderProfileTemplate(tmpl *template.Template, name string, data any) (string, error) {
+	var markdown strings.Builder
+	if err := tmpl.ExecuteTemplate(&markdown, name, data); err != nil {
+		return "", fmt.Errorf("error merender template %s: %w", name, err)
+	}
+	return markdown.String(), nil
+}
+
+func writeProfileDocuments(markdown, slug, province string) error {
+	if err := writeProfileFile(slug+".md", markdown); err != nil {
+		return err
+	}
+	if err := writeProfileFile(slug+".html", renderMarkdownHTML(markdown, trf("Profil Kelapa Sawit %s", province))); err != nil {
+		return err
+	}
+
+	pdf := newReportPDF(false)
+	pdf.AddPage()
+	for _, block := range parseMarkdownBlocks(markdown, stripEmoji) {
+		switch {
+		case block.Kind == "heading" && block.Level == 1:
+			pdf.SetFont("Liberation", "B", 20)
+			pdf.MultiCell(0, 9, block.Text, "", "C", false)
+		case block.Kind == "heading" && block.Level == 2:
+			pdf.SetFont("Liberation", "", 13)
+			pdf.MultiCell(0, 7, block.Text, "", "C", false)
+			pdf.Ln(3)
+		case block.Kind == "heading":
+			writePDFHeading(pdf, block)
+		default:
+			writePDFBlock(pdf, block, profileDir)
+		}
+	}
+	if pdf.Err() {
+		return fmt.Errorf("error membuat profil PDF %s: %w", province, pdf.Error())
+	}
+	if err := pdf.OutputFileAndClose(filepath.Join(profileDir, slug+".pdf")); err != nil {
+		return fmt.Errorf("error menyimpan profil PDF %s: %w", province, err)
+	}
+	return nil
+}
+
+func writeProfileFile(name, content string) error {
+	if err := os.WriteFile(filepath.Join(profileDir, name), []byte(content), 0o644); err != nil {
+		return fmt.Errorf("error menyimpan %s: %w", name, err)
+	}
+	return nil
+}
+
+// fileSlug mengubah nama wilayah menjadi nama file, mis. "KEP. BANGKA
+// BELITUNG" menjadi "kep_bangka_belitung".
+func fileSlug(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "_", ".", "", "/", "_").Replace(strings.TrimSpace(name)))
+}
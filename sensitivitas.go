@@ -0,0 +1,639 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Analisis sensitivitas menggeser setiap bobot skor dan ambang klasifikasi
+// sebesar ±sensitivityPerturbation (one-at-a-time) lalu menjalankan screening
+// global Morris pada rentang yang sama. Rank2022 hanya bergantung pada area
+// sehingga tidak terpengaruh; stabilitas peringkat diukur pada peringkat daya
+// saing. Bila ada data produksi, bobot efisiensi hasil ikut digeser karena
+// skor efisiensi provinsi tersebut, dan lewat itu daya saingnya, dihitung dari
+// hasil per hektar; pergeseran peringkat efisiensi di antara provinsi itu
+// dicatat terpisah. Indeks varians Sobol tidak dihitung, Morris dipakai
+// sebagai screening global.
+const (
+	sensitivityPerturbation = 0.2
+	morrisTrajectories      = 20
+	morrisLevels            = 4
+	morrisSeed              = 7
+	tornadoTopParameters    = 15
+)
+
+var provinceCategories = []string{"PRIME", "GROWTH", "EMERGING", "STABLE", "MATURE"}
+
+type SensitivitySettings struct {
+	Scoring    ScoringConfig
+	Categories CategoryThresholds
+	Risk       RiskThresholds
+}
+
+type SensitivityParameter struct {
+	Name  string
+	Group string
+	Base  float64
+	set   func(settings *SensitivitySettings, value float64)
+}
+
+type ParameterSensitivity struct {
+	Parameter           string
+	Group               string
+	Base                float64
+	LowRankShift        float64
+	HighRankShift       float64
+	LowYieldRankShift   float64
+	HighYieldRankShift  float64
+	LowClassChanges     int
+	HighClassChanges    int
+	MorrisRankMuStar    float64
+	MorrisRankSigma     float64
+	MorrisClassMuStar   float64
+	MorrisClassSigma    float64
+	LowInvestmentShift  int
+	HighInvestmentShift int
+}
+
+type ProvinceStability struct {
+	Province          string
+	BaseRank          int
+	MinRank           int
+	MaxRank           int
+	BaseInvestment    string
+	InvestmentStable  float64
+	BaseCategories    string
+	CategoryStable    float64
+	BaseRisk          string
+	RiskStable        float64
+	AlternativeLevels []string
+}
+
+type SensitivityAnalysis struct {
+	Runs           int
+	YieldProvinces int
+	Parameters     []ParameterSensitivity
+	Provinces      []ProvinceStability
+}
+
+// sensitivityOutcome.YieldRank adalah peringkat efisiensi hasil, hanya untuk
+// provinsi yang memiliki data produksi.
+type sensitivityOutcome struct {
+	Rank       map[string]int
+	YieldRank  map[string]int
+	Investment map[string]string
+	Categories map[string]string
+	Risk       map[string]string
+}
+
+func analyzeSensitivity(models []ProvinceModel, scoring ScoringConfig) *SensitivityAnalysis {
+	base := SensitivitySettings{
+		Scoring:    copyScoringConfig(scoring),
+		Categories: defaultCategoryThresholds,
+		Risk:       defaultRiskThresholds,
+	}
+	yieldProvinces := len(yieldModels(models))
+	parameters := sensitivityParameters(base, yieldProvinces > 0)
+	baseline := evaluateSensitivity(models, base)
+
+	tracker := newStabilityTracker(models, baseline)
+	analysis := &SensitivityAnalysis{YieldProvinces: yieldProvinces}
+
+	for _, parameter := range parameters {
+		result := ParameterSensitivity{Parameter: parameter.Name, Group: parameter.Group, Base: parameter.Base}
+
+		for _, direction := range []float64{-1, 1} {
+			settings := cloneSettings(base)
+			parameter.set(&settings, parameter.Base*(1+direction*sensitivityPerturbation))
+			outcome := evaluateSensitivity(models, settings)
+			tracker.record(outcome)
+
+			rankShift, yieldShift, investment, changes := compareOutcomes(baseline, outcome)
+			if direction < 0 {
+				result.LowRankShift, result.LowYieldRankShift = rankShift, yieldShift
+				result.LowInvestmentShift, result.LowClassChanges = investment, changes
+			} else {
+				result.HighRankShift, result.HighYieldRankShift = rankShift, yieldShift
+				result.HighInvestmentShift, result.HighClassChanges = investment, changes
+			}
+		}
+
+		analysis.Parameters = append(analysis.Parameters, result)
+	}
+
+	runMorrisScreening(models, base, parameters, baseline, analysis, tracker)
+
+	analysis.Runs = tracker.runs
+	analysis.Provinces = tracker.summarize()
+
+	fmt.Fprintf(progressOutput, "🎚️  Analisis sensitivitas selesai: %d parameter, %d evaluasi\n", len(parameters), analysis.Runs)
+	return analysis
+}
+
+// sensitivityParameters memasukkan bobot efisiensi hasil hanya bila ada
+// provinsi dengan data produksi; tanpa itu bobot tersebut tidak berpengaruh.
+func sensitivityParameters(base SensitivitySettings, withYield bool) []SensitivityParameter {
+	var parameters []SensitivityParameter
+
+	for i, component := range base.Scoring.Efficiency {
+		parameters = append(parameters, SensitivityParameter{
+			Name: trf("Bobot Efisiensi: %s", tr(component.Name)), Group: tr("Bobot"), Base: component.Weight,
+			set: func(s *SensitivitySettings, v float64) { s.Scoring.Efficiency[i].Weight = v },
+		})
+	}
+	if withYield {
+		for i, component := range base.Scoring.YieldEfficiency {
+			parameters = append(parameters, SensitivityParameter{
+				Name: trf("Bobot Efisiensi Hasil: %s", tr(component.Name)), Group: tr("Bobot"), Base: component.Weight,
+				set: func(s *SensitivitySettings, v float64) { s.Scoring.YieldEfficiency[i].Weight = v },
+			})
+		}
+	}
+	for i, component := range base.Scoring.Competitiveness {
+		parameters = append(parameters, SensitivityParameter{
+			Name: trf("Bobot Daya Saing: %s", tr(component.Name)), Group: tr("Bobot"), Base: component.Weight,
+			set: func(s *SensitivitySettings, v float64) { s.Scoring.Competitiveness[i].Weight = v },
+		})
+	}
+	for _, level := range investmentLevels {
+		parameters = append(parameters, SensitivityParameter{
+			Name: trf("Ambang Investasi %s", tr(level)), Group: tr("Investasi"), Base: base.Scoring.InvestmentThresholds[level],
+			set: func(s *SensitivitySettings, v float64) { s.Scoring.InvestmentThresholds[level] = v },
+		})
+	}
+
+	c := base.Categories
+	categoryParameters := []struct {
+		name  string
+		value float64
+		field func(t *CategoryThresholds) *float64
+	}{
+		{"PRIME {{Metric}} Min", c.PrimeMinArea, func(t *CategoryThresholds) *float64 { return &t.PrimeMinArea }},
+		{"PRIME Growth Min", c.PrimeMinGrowth, func(t *CategoryThresholds) *float64 { return &t.PrimeMinGrowth }},
+		{"GROWTH Growth Min", c.GrowthMinGrowth, func(t *CategoryThresholds) *float64 { return &t.GrowthMinGrowth }},
+		{"EMERGING {{Metric}} Max", c.EmergingMaxArea, func(t *CategoryThresholds) *float64 { return &t.EmergingMaxArea }},
+		{"EMERGING Growth Min", c.EmergingMinGrowth, func(t *CategoryThresholds) *float64 { return &t.EmergingMinGrowth }},
+		{"STABLE {{Metric}} Min", c.StableMinArea, func(t *CategoryThresholds) *float64 { return &t.StableMinArea }},
+		{"STABLE Growth Min", c.StableMinGrowth, func(t *CategoryThresholds) *float64 { return &t.StableMinGrowth }},
+		{"STABLE Growth Max", c.StableMaxGrowth, func(t *CategoryThresholds) *float64 { return &t.StableMaxGrowth }},
+		{"MATURE {{Metric}} Min", c.MatureMinArea, func(t *CategoryThresholds) *float64 { return &t.MatureMinArea }},
+		{"MATURE Growth Max", c.MatureMaxGrowth, func(t *CategoryThresholds) *float64 { return &t.MatureMaxGrowth }},
+	}
+	for _, p := range categoryParameters {
+		parameters = append(parameters, SensitivityParameter{
+			Name: trf("Kategori %s", tr(p.name)), Group: tr("Kategori"), Base: p.value,
+			set: func(s *SensitivitySettings, v float64) { *p.field(&s.Categories) = v },
+		})
+	}
+
+	r := base.Risk
+	riskParameters := []struct {
+		name  string
+		value float64
+		field func(t *RiskThresholds) *float64
+	}{
+		{"Growth Tinggi", r.HighGrowth, func(t *RiskThresholds) *float64 { return &t.HighGrowth }},
+		{"Stabilitas Rendah", r.LowStability, func(t *RiskThresholds) *float64 { return &t.LowStability }},
+		{"Market Share Rendah", r.LowMarketShare, func(t *RiskThresholds) *float64 { return &t.LowMarketShare }},
+		{"Growth Rendah", r.LowShareGrowth, func(t *RiskThresholds) *float64 { return &t.LowShareGrowth }},
+	}
+	for _, p := range riskParameters {
+		parameters = append(parameters, SensitivityParameter{
+			Name: trf("Risiko %s", tr(p.name)), Group: tr("Risiko"), Base: p.value,
+			set: func(s *SensitivitySettings, v float64) { *p.field(&s.Risk) = v },
+		})
+	}
+
+	return parameters
+}
+
+func evaluateSensitivity(models []ProvinceModel, settings SensitivitySettings) sensitivityOutcome {
+	scored := make([]ProvinceModel, len(models))
+	copy(scored, models)
+	applyCompositeScores(scored, settings.Scoring)
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Competitiveness > scored[j].Competitiveness
+	})
+
+	outcome := sensitivityOutcome{
+		Rank:       make(map[string]int),
+		YieldRank:  make(map[string]int),
+		Investment: make(map[string]string),
+		Categories: make(map[string]string),
+		Risk:       make(map[string]string),
+	}
+	for i, model := range scored {
+		outcome.Rank[model.Province] = i + 1
+		outcome.Investment[model.Province] = model.InvestmentPotential
+		outcome.Risk[model.Province] = classifyRiskLevel(model, settings.Risk)
+
+		var categories []string
+		for _, category := range provinceCategories {
+			if matchesCategory(model, category, settings.Categories) {
+				categories = append(categories, category)
+			}
+		}
+		outcome.Categories[model.Province] = strings.Join(categories, ",")
+	}
+	for i, model := range rankYieldEfficiency(scored) {
+		outcome.YieldRank[model.Province] = i + 1
+	}
+	return outcome
+}
+
+// rankYieldEfficiency mengurutkan provinsi berdata produksi berdasarkan skor
+// efisiensi hasil tertinggi.
+func rankYieldEfficiency(models []ProvinceModel) []ProvinceModel {
+	ranked := yieldModels(models)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].ProductionEfficiency > ranked[j].ProductionEfficiency
+	})
+	return ranked
+}
+
+// compareOutcomes mengembalikan rata-rata pergeseran peringkat daya saing,
+// rata-rata pergeseran peringkat efisiensi provinsi berdata produksi, jumlah
+// provinsi yang potensi investasinya berubah dan jumlah provinsi yang
+// klasifikasinya berubah.
+func compareOutcomes(baseline, outcome sensitivityOutcome) (float64, float64, int, int) {
+	rankShift := 0.0
+	investment, changes := 0, 0
+	for province, rank := range baseline.Rank {
+		rankShift += math.Abs(float64(outcome.Rank[province] - rank))
+		changed := false
+		if outcome.Investment[province] != baseline.Investment[province] {
+			investment++
+			changed = true
+		}
+		if outcome.Categories[province] != baseline.Categories[province] || outcome.Risk[province] != baseline.Risk[province] {
+			changed = true
+		}
+		if changed {
+			changes++
+		}
+	}
+	if len(baseline.Rank) > 0 {
+		rankShift /= float64(len(baseline.Rank))
+	}
+	yieldShift := 0.0
+	for province, rank := range baseline.YieldRank {
+		yieldShift += math.Abs(float64(outcome.YieldRank[province] - rank))
+	}
+	if len(baseline.YieldRank) > 0 {
+		yieldShift /= float64(len(baseline.YieldRank))
+	}
+	return rankShift, yieldShift, investment, changes
+}
+
+func runMorrisScreening(models []ProvinceModel, base SensitivitySettings, parameters []SensitivityParameter,
+	baseline sensitivityOutcome, analysis *SensitivityAnalysis, tracker *stabilityTracker) {
+	rng := rand.New(rand.NewSource(morrisSeed))
+	delta := float64(morrisLevels) / (2 * float64(morrisLevels-1))
+
+	rankEffects := make([][]float64, len(parameters))
+	classEffects := make([][]float64, len(parameters))
+
+	evaluate := func(x []float64) (float64, float64) {
+		settings := cloneSettings(base)
+		for i, parameter := range parameters {
+			parameter.set(&settings, parameter.Base*(1+sensitivityPerturbation*(2*x[i]-1)))
+		}
+		outcome := evaluateSensitivity(models, settings)
+		tracker.record(outcome)
+		rankShift, _, _, changes := compareOutcomes(baseline, outcome)
+		return rankShift, float64(changes)
+	}
+
+	for r := 0; r < morrisTrajectories; r++ {
+		x := make([]float64, len(parameters))
+		for i := range x {
+			x[i] = float64(rng.Intn(morrisLevels/2)) / float64(morrisLevels-1)
+		}
+		previousRank, previousClass := evaluate(x)
+
+		for _, i := range rng.Perm(len(parameters)) {
+			x[i] += delta
+			rank, class := evaluate(x)
+			rankEffects[i] = append(rankEffects[i], (rank-previousRank)/delta)
+			classEffects[i] = append(classEffects[i], (class-previousClass)/delta)
+			previousRank, previousClass = rank, class
+		}
+	}
+
+	for i := range analysis.Parameters {
+		analysis.Parameters[i].MorrisRankMuStar, analysis.Parameters[i].MorrisRankSigma = morrisStatistics(rankEffects[i])
+		analysis.Parameters[i].MorrisClassMuStar, analysis.Parameters[i].MorrisClassSigma = morrisStatistics(classEffects[i])
+	}
+}
+
+func morrisStatistics(effects []float64) (float64, float64) {
+	if len(effects) == 0 {
+		return 0, 0
+	}
+	muStar, mean := 0.0, 0.0
+	for _, effect := range effects {
+		muStar += math.Abs(effect)
+		mean += effect
+	}
+	muStar /= float64(len(effects))
+	mean /= float64(len(effects))
+
+	variance := 0.0
+	for _, effect := range effects {
+		variance += math.Pow(effect-mean, 2)
+	}
+	if len(effects) > 1 {
+		variance /= float64(len(effects) - 1)
+	}
+	return muStar, math.Sqrt(variance)
+}
+
+func copyScoringConfig(config ScoringConfig) ScoringConfig {
+	clone := config
+	clone.Efficiency = append([]ScoreComponent(nil), config.Efficiency...)
+	clone.YieldEfficiency = append([]ScoreComponent(nil), config.YieldEfficiency...)
+	clone.Competitiveness = append([]ScoreComponent(nil), config.Competitiveness...)
+	clone.InvestmentThresholds = make(map[string]float64, len(config.InvestmentThresholds))
+	for level, threshold := range config.InvestmentThresholds {
+		clone.InvestmentThresholds[level] = threshold
+	}
+	return clone
+}
+
+func cloneSettings(settings SensitivitySettings) SensitivitySettings {
+	clone := settings
+	clone.Scoring = copyScoringConfig(settings.Scoring)
+	return clone
+}
+
+type stabilityTracker struct {
+	baseline   sensitivityOutcome
+	provinces  []string
+	runs       int
+	minRank    map[string]int
+	maxRank    map[string]int
+	investment map[string]int
+	categories map[string]int
+	risk       map[string]int
+	levels     map[string]map[string]bool
+}
+
+func newStabilityTracker(models []ProvinceModel, baseline sensitivityOutcome) *stabilityTracker {
+	tracker := &stabilityTracker{
+		baseline:   baseline,
+		minRank:    make(map[string]int),
+		maxRank:    make(map[string]int),
+		investment: make(map[string]int),
+		categories: make(map[string]int),
+		risk:       make(map[string]int),
+		levels:     make(map[string]map[string]bool),
+	}
+	for _, model := range models {
+		tracker.provinces = append(tracker.provinces, model.Province)
+		tracker.minRank[model.Province] = baseline.Rank[model.Province]
+		tracker.maxRank[model.Province] = baseline.Rank[model.Province]
+		tracker.levels[model.Province] = make(map[string]bool)
+	}
+	return tracker
+}
+
+func (t *stabilityTracker) record(outcome sensitivityOutcome) {
+	t.runs++
+	for _, province := range t.provinces {
+		rank := outcome.Rank[province]
+		if rank < t.minRank[province] {
+			t.minRank[province] = rank
+		}
+		if rank > t.maxRank[province] {
+			t.maxRank[province] = rank
+		}
+		if outcome.Investment[province] == t.baseline.Investment[province] {
+			t.investment[province]++
+		} else {
+			t.levels[province][outcome.Investment[province]] = true
+		}
+		if outcome.Categories[province] == t.baseline.Categories[province] {
+			t.categories[province]++
+		}
+		if outcome.Risk[province] == t.baseline.Risk[province] {
+			t.risk[province]++
+		}
+	}
+}
+
+func (t *stabilityTracker) summarize() []ProvinceStability {
+	var stability []ProvinceStability
+	for _, province := range t.provinces {
+		entry := ProvinceStability{
+			Province:       province,
+			BaseRank:       t.baseline.Rank[province],
+			MinRank:        t.minRank[province],
+			MaxRank:        t.maxRank[province],
+			BaseInvestment: t.baseline.Investment[province],
+			BaseCategories: t.baseline.Categories[province],
+			BaseRisk:       t.baseline.Risk[province],
+		}
+		if t.runs > 0 {
+			entry.InvestmentStable = float64(t.investment[province]) / float64(t.runs)
+			entry.CategoryStable = float64(t.categories[province]) / float64(t.runs)
+			entry.RiskStable = float64(t.risk[province]) / float64(t.runs)
+		}
+		for level := range t.levels[province] {
+			entry.AlternativeLevels = append(entry.AlternativeLevels, level)
+		}
+		sort.Strings(entry.AlternativeLevels)
+		stability = append(stability, entry)
+	}
+	return stability
+}
+
+func writeSensitivitySheets(f *excelize.File, analysis *SensitivityAnalysis) {
+	if analysis == nil {
+		return
+	}
+
+	sheet := tr("Sensitivitas_Parameter")
+	f.NewSheet(sheet)
+
+	headers := []string{"Parameter", "Kelompok", "Nilai Dasar",
+		trf("Geser Rank -%.0f%%", sensitivityPerturbation*100), trf("Geser Rank +%.0f%%", sensitivityPerturbation*100)}
+	if analysis.YieldProvinces > 0 {
+		headers = append(headers, trf("Geser Rank Efisiensi Hasil -%.0f%%", sensitivityPerturbation*100),
+			trf("Geser Rank Efisiensi Hasil +%.0f%%", sensitivityPerturbation*100))
+	}
+	headers = append(headers,
+		trf("Investasi Berubah -%.0f%%", sensitivityPerturbation*100), trf("Investasi Berubah +%.0f%%", sensitivityPerturbation*100),
+		trf("Klasifikasi Berubah -%.0f%%", sensitivityPerturbation*100), trf("Klasifikasi Berubah +%.0f%%", sensitivityPerturbation*100),
+		"Morris μ* Rank", "Morris σ Rank", "Morris μ* Klasifikasi", "Morris σ Klasifikasi")
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 18)
+	}
+
+	for i, p := range analysis.Parameters {
+		values := []interface{}{p.Parameter, p.Group, p.Base,
+			fmt.Sprintf("%.2f", p.LowRankShift), fmt.Sprintf("%.2f", p.HighRankShift)}
+		if analysis.YieldProvinces > 0 {
+			values = append(values, fmt.Sprintf("%.2f", p.LowYieldRankShift), fmt.Sprintf("%.2f", p.HighYieldRankShift))
+		}
+		values = append(values, p.LowInvestmentShift, p.HighInvestmentShift, p.LowClassChanges, p.HighClassChanges,
+			fmt.Sprintf("%.3f", p.MorrisRankMuStar), fmt.Sprintf("%.3f", p.MorrisRankSigma),
+			fmt.Sprintf("%.3f", p.MorrisClassMuStar), fmt.Sprintf("%.3f", p.MorrisClassSigma))
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+2)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+
+	sheet = tr("Stabilitas_Provinsi")
+	f.NewSheet(sheet)
+
+	f.SetCellValue(sheet, "A1", trf("STABILITAS KLASIFIKASI PER PROVINSI (%d evaluasi, Rank2022 berbasis {{metric}} tidak terpengaruh)", analysis.Runs))
+	headers = []string{"Provinsi", "Rank Daya Saing", "Rank Min", "Rank Max", "Potensi Investasi", "Investasi Stabil (%)",
+		"Alternatif Investasi", "Kategori", "Kategori Stabil (%)", "Risiko", "Risiko Stabil (%)"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 2)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 18)
+	}
+
+	for i, p := range analysis.Provinces {
+		values := []interface{}{p.Province, p.BaseRank, p.MinRank, p.MaxRank, tr(p.BaseInvestment),
+			fmt.Sprintf("%.0f%%", p.InvestmentStable*100), trList(strings.Join(p.AlternativeLevels, ", "), ", "),
+			trList(p.BaseCategories, ","), fmt.Sprintf("%.0f%%", p.CategoryStable*100),
+			tr(p.BaseRisk), fmt.Sprintf("%.0f%%", p.RiskStable*100)}
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+3)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+}
+
+func createTornadoChart(analysis *SensitivityAnalysis) error {
+	if analysis == nil {
+		return nil
+	}
+
+	parameters := make([]ParameterSensitivity, len(analysis.Parameters))
+	copy(parameters, analysis.Parameters)
+	sort.SliceStable(parameters, func(i, j int) bool {
+		return tornadoWidth(parameters[i]) > tornadoWidth(parameters[j])
+	})
+	if len(parameters) > tornadoTopParameters {
+		parameters = parameters[:tornadoTopParameters]
+	}
+
+	p := plot.New()
+	p.Title.Text = trf("TORNADO SENSITIVITAS KLASIFIKASI (±%.0f%% PARAMETER)", sensitivityPerturbation*100)
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = trf("Provinsi berubah klasifikasi (kiri: -%.0f%%, kanan: +%.0f%%)",
+		sensitivityPerturbation*100, sensitivityPerturbation*100)
+
+	low := make(plotter.Values, len(parameters))
+	high := make(plotter.Values, len(parameters))
+	labels := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		// Parameter paling berpengaruh digambar paling atas.
+		position := len(parameters) - 1 - i
+		low[position] = -float64(parameter.LowClassChanges)
+		high[position] = float64(parameter.HighClassChanges)
+		labels[position] = parameter.Parameter
+	}
+
+	for _, side := range []struct {
+		values plotter.Values
+		color  color.RGBA
+		label  string
+	}{
+		{low, chartTheme.Low, fmt.Sprintf("-%.0f%%", sensitivityPerturbation*100)},
+		{high, chartTheme.High, fmt.Sprintf("+%.0f%%", sensitivityPerturbation*100)},
+	} {
+		bars, err := plotter.NewBarChart(side.values, vg.Points(18))
+		if err != nil {
+			return err
+		}
+		bars.Horizontal = true
+		bars.Color = side.color
+		bars.LineStyle.Width = vg.Length(0)
+		p.Add(bars)
+		p.Legend.Add(side.label, bars)
+	}
+
+	p.NominalY(labels...)
+	p.Add(plotter.NewGrid())
+	p.Legend.Top = true
+
+	return saveChart(p, 16*vg.Inch, 10*vg.Inch, "tornado_sensitivitas")
+}
+
+func tornadoWidth(p ParameterSensitivity) float64 {
+	return float64(p.LowClassChanges+p.HighClassChanges) + p.MorrisClassMuStar/100
+}
+
+func buildSensitivityReport(analysis *SensitivityAnalysis) string {
+	if analysis == nil {
+		return ""
+	}
+
+	report := tr("\n### 🎚️ SENSITIVITAS PERINGKAT & KLASIFIKASI\n\n")
+	report += trf("Setiap bobot dan ambang digeser ±%.0f%% (one-at-a-time) dan disaring secara global dengan metode Morris "+
+		"(%d trajektori, total %d evaluasi). Rank2022 hanya bergantung pada {{metric}} 2022 sehingga tidak berubah; "+
+		"pergeseran peringkat diukur pada peringkat daya saing. Indeks varians Sobol berada di luar cakupan analisis ini; "+
+		"Morris dipakai sebagai screening global.\n\n",
+		sensitivityPerturbation*100, morrisTrajectories, analysis.Runs)
+	if analysis.YieldProvinces > 0 {
+		report += trf("Bobot efisiensi hasil hanya memengaruhi skor efisiensi %d provinsi berdata produksi, lalu lewat skor itu peringkat daya saing. "+
+			"Pergeseran peringkat efisiensi di antara provinsi tersebut dilaporkan pada kolom tersendiri.\n\n",
+			analysis.YieldProvinces)
+	}
+
+	parameters := make([]ParameterSensitivity, len(analysis.Parameters))
+	copy(parameters, analysis.Parameters)
+	sort.SliceStable(parameters, func(i, j int) bool {
+		return parameters[i].MorrisClassMuStar > parameters[j].MorrisClassMuStar
+	})
+
+	withYield := analysis.YieldProvinces > 0
+	report += tr("| Parameter | Klasifikasi Berubah (-/+) | Geser Rank (-/+) |")
+	separator := "|-----------|---------------------------|------------------|"
+	if withYield {
+		report += tr(" Geser Rank Efisiensi Hasil (-/+) |")
+		separator += "----------------------------------|"
+	}
+	report += " Morris μ* |\n" + separator + "-----------|\n"
+	for i := 0; i < len(parameters) && i < 10; i++ {
+		p := parameters[i]
+		report += fmt.Sprintf("| %s | %d / %d | %.2f / %.2f |",
+			p.Parameter, p.LowClassChanges, p.HighClassChanges, p.LowRankShift, p.HighRankShift)
+		if withYield {
+			report += fmt.Sprintf(" %.2f / %.2f |", p.LowYieldRankShift, p.HighYieldRankShift)
+		}
+		report += fmt.Sprintf(" %.2f |\n", p.MorrisClassMuStar)
+	}
+
+	var fragile []string
+	for _, province := range analysis.Provinces {
+		if province.InvestmentStable < 0.8 || province.CategoryStable < 0.8 || province.RiskStable < 0.8 {
+			fragile = append(fragile, trf("%s (investasi %.0f%%, kategori %.0f%%, risiko %.0f%%)",
+				province.Province, province.InvestmentStable*100, province.CategoryStable*100, province.RiskStable*100))
+		}
+	}
+	if len(fragile) > 0 {
+		report += tr("\n**Provinsi dengan klasifikasi rapuh (stabil <80% evaluasi):** ") + strings.Join(fragile, "; ") + "\n"
+	} else {
+		report += tr("\nSemua provinsi mempertahankan klasifikasinya pada ≥80% evaluasi.\n")
+	}
+
+	return report
+}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Data sertifikasi opsional: luas tersertifikasi ISPO/RSPO per provinsi per
+// tahun. Kolom wajib year, province dan certified_ha; scheme (mis. ISPO,
+// RSPO) opsional. Karena area bersertifikat ganda tidak diketahui, cakupan
+// gabungan memakai skema dengan luas terbesar pada tahun itu (batas bawah
+// cakupan sebenarnya). Cakupan dihitung terhadap YearlyData, sehingga hanya
+// tersedia bila metrik utama berupa luas (ha).
+const certificationFile = "sertifikasi_sawit.csv"
+
+// certificationTargetYear adalah target "100% Certified by 2030" di
+// Matriks_Strategi_20Tahun.
+const certificationTargetYear = 2030
+
+// certificationTrendYears adalah jumlah tahun data terakhir yang dipakai
+// untuk laju kenaikan cakupan.
+const certificationTrendYears = 5
+
+// Status kemajuan sertifikasi terhadap certificationTargetYear. INSUFFICIENT
+// DATA dipakai bila cakupan baru tersedia untuk satu tahun sehingga laju
+// belum bisa dihitung.
+var certificationStatuses = []string{"ACHIEVED", "ON TRACK", "OFF TRACK", "STALLED", "INSUFFICIENT DATA"}
+
+type CertificationData struct {
+	Schemes   []string
+	Provinces map[string]map[string]map[int]float64
+}
+
+// ProvinceCertification adalah kemajuan satu provinsi. Pace dan RequiredPace
+// dalam poin persen per tahun; ProjectedYear adalah tahun cakupan 100% pada
+// laju saat ini (0 bila laju tidak positif).
+type ProvinceCertification struct {
+	Province       string
+	Coverage       map[int]float64
+	SchemeCoverage map[string]float64
+	LatestYear     int
+	Area           float64
+	Certified      float64
+	LatestCoverage float64
+	Pace           float64
+	RequiredPace   float64
+	ProjectedYear  int
+	Status         string
+}
+
+type CertificationAnalysis struct {
+	Schemes         []string
+	Provinces       []ProvinceCertification
+	NationalArea    float64
+	NationalCovered float64
+	LatestYear      int
+}
+
+// loadCertificationData mengembalikan nil tanpa error bila file tidak ada.
+func loadCertificationData() (*CertificationData, error) {
+	records, err := readOptionalCSV(certificationFile)
+	if err != nil || records == nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%s tidak memiliki baris data", certificationFile)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"year", "province", "certified_ha"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("kolom %q tidak ada di header %s", required, certificationFile)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	data := &CertificationData{Provinces: make(map[string]map[string]map[int]float64)}
+	for line, record := range records[1:] {
+		year, err := strconv.Atoi(field(record, "year"))
+		if err != nil {
+			return nil, fmt.Errorf("%s baris %d: tahun tidak valid %q", certificationFile, line+2, field(record, "year"))
+		}
+		certified, err := strconv.ParseFloat(field(record, "certified_ha"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s baris %d: certified_ha tidak valid %q", certificationFile, line+2, field(record, "certified_ha"))
+		}
+
+		scheme := strings.ToUpper(field(record, "scheme"))
+		if scheme == "" {
+			scheme = "ISPO/RSPO"
+		}
+		province := strings.ToUpper(field(record, "province"))
+		if data.Provinces[province] == nil {
+			data.Provinces[province] = make(map[string]map[int]float64)
+		}
+		if data.Provinces[province][scheme] == nil {
+			data.Provinces[province][scheme] = make(map[int]float64)
+			data.Schemes = appendUnique(data.Schemes, scheme)
+		}
+		data.Provinces[province][scheme][year] += certified
+	}
+	sort.Strings(data.Schemes)
+
+	fmt.Fprintf(progressOutput, "🏅 Data sertifikasi dibaca: %d provinsi, skema %s dari %s\n", len(data.Provinces), strings.Join(data.Schemes, ", "), certificationFile)
+	return data, nil
+}
+
+// analyzeCertification menghitung cakupan sertifikasi per provinsi terhadap
+// YearlyData dan memproyeksikan tahun cakupan 100%.
+func analyzeCertification(models []ProvinceModel, data *CertificationData) *CertificationAnalysis {
+	if data == nil {
+		fmt.Fprintf(progressOutput, "🏅 Progres sertifikasi dilewati: %s tidak ditemukan\n", certificationFile)
+		return nil
+	}
+	if metricUnit() != defaultMetric.Unit {
+		fmt.Fprintf(progressOutput, "⚠️  Progres sertifikasi dilewati: metrik utama %s bukan luas (%s)\n", primaryMetric().Name, defaultMetric.Unit)
+		return nil
+	}
+
+	analysis := &CertificationAnalysis{Schemes: data.Schemes}
+	for _, model := range models {
+		schemes, ok := data.Provinces[strings.ToUpper(model.Province)]
+		if !ok {
+			continue
+		}
+		certification := ProvinceCertification{
+			Province:       model.Province,
+			Coverage:       make(map[int]float64),
+			SchemeCoverage: make(map[string]float64),
+		}
+		for _, year := range getSortedYears(model.YearlyData) {
+			area := model.YearlyData[year]
+			certified, found := 0.0, false
+			for _, years := range schemes {
+				if value, ok := years[year]; ok {
+					certified, found = math.Max(certified, value), true
+				}
+			}
+			if !found || area <= 0 {
+				continue
+			}
+			certification.Coverage[year] = certified / area * 100
+			certification.LatestYear = year
+			certification.Area = area
+			certification.Certified = certified
+		}
+		if len(certification.Coverage) == 0 {
+			continue
+		}
+		certification.LatestCoverage = certification.Coverage[certification.LatestYear]
+		for scheme, years := range schemes {
+			certification.SchemeCoverage[scheme] = years[certification.LatestYear] / certification.Area * 100
+		}
+		projectCertification(&certification)
+		analysis.Provinces = append(analysis.Provinces, certification)
+
+		analysis.NationalArea += certification.Area
+		analysis.NationalCovered += math.Min(certification.Certified, certification.Area)
+		analysis.LatestYear = max(analysis.LatestYear, certification.LatestYear)
+	}
+	if len(analysis.Provinces) == 0 {
+		fmt.Fprintf(progressOutput, "🏅 Progres sertifikasi dilewati: tidak ada provinsi di %s yang cocok dengan data area\n", certificationFile)
+		return nil
+	}
+
+	statusOrder := make(map[string]int, len(certificationStatuses))
+	for i, status := range certificationStatuses {
+		statusOrder[status] = i
+	}
+	sort.SliceStable(analysis.Provinces, func(i, j int) bool {
+		a, b := analysis.Provinces[i], analysis.Provinces[j]
+		if statusOrder[a.Status] != statusOrder[b.Status] {
+			return statusOrder[a.Status] > statusOrder[b.Status]
+		}
+		return a.LatestCoverage < b.LatestCoverage
+	})
+
+	fmt.Fprintf(progressOutput, "🏅 Progres sertifikasi dihitung: %d provinsi, %d tertinggal dari target %d\n",
+		len(analysis.Provinces), len(analysis.offTrack()), certificationTargetYear)
+	return analysis
+}
+
+func projectCertification(certification *ProvinceCertification) {
+	years := getSortedYears(certification.Coverage)
+	if len(years) > certificationTrendYears {
+		years = years[len(years)-certificationTrendYears:]
+	}
+	recent := make(map[int]float64, len(years))
+	for _, year := range years {
+		recent[year] = certification.Coverage[year]
+	}
+	if len(recent) >= 2 {
+		certification.Pace, _ = linearTrend(recent)
+	}
+
+	remaining := 100 - certification.LatestCoverage
+	if yearsLeft := certificationTargetYear - certification.LatestYear; yearsLeft > 0 && remaining > 0 {
+		certification.RequiredPace = remaining / float64(yearsLeft)
+	}
+
+	switch {
+	case remaining <= 0:
+		certification.ProjectedYear = certification.LatestYear
+		certification.Status = "ACHIEVED"
+	case len(recent) < 2:
+		certification.Status = "INSUFFICIENT DATA"
+	case certification.Pace <= 0:
+		certification.Status = "STALLED"
+	default:
+		certification.ProjectedYear = certification.LatestYear + int(math.Ceil(remaining/certification.Pace))
+		certification.Status = "ON TRACK"
+		if certification.ProjectedYear > certificationTargetYear {
+			certification.Status = "OFF TRACK"
+		}
+	}
+}
+
+func (a *CertificationAnalysis) offTrack() []ProvinceCertification {
+	var provinces []ProvinceCertification
+	for _, province := range a.Provinces {
+		if province.Status == "OFF TRACK" || province.Status == "STALLED" {
+			provinces = append(provinces, province)
+		}
+	}
+	return provinces
+}
+
+// insufficient adalah provinsi yang lajunya belum bisa dihitung.
+func (a *CertificationAnalysis) insufficient() []string {
+	var provinces []string
+	for _, province := range a.Provinces {
+		if province.Status == "INSUFFICIENT DATA" {
+			provinces = append(provinces, province.Province)
+		}
+	}
+	return provinces
+}
+
+func (a *CertificationAnalysis) nationalCoverage() float64 {
+	if a.NationalArea <= 0 {
+		return 0
+	}
+	return a.NationalCovered / a.NationalArea * 100
+}
+
+// certificationSummary adalah ringkasan satu baris untuk baris sertifikasi
+// di Matriks_Strategi_20Tahun.
+func certificationSummary(analysis *CertificationAnalysis) string {
+	return trf("%.1f%% tersertifikasi (%d, %d provinsi); %d provinsi tertinggal dari target %d",
+		analysis.nationalCoverage(), analysis.LatestYear, len(analysis.Provinces), len(analysis.offTrack()), certificationTargetYear)
+}
+
+func formatProjectedYear(certification ProvinceCertification) string {
+	if certification.ProjectedYear == 0 {
+		return "-"
+	}
+	return strconv.Itoa(certification.ProjectedYear)
+}
+
+func writeCertificationSheet(f *excelize.File, analysis *CertificationAnalysis) {
+	if analysis == nil {
+		return
+	}
+	sheet := tr("Progres_Sertifikasi")
+	f.NewSheet(sheet)
+
+	headers := []string{tr("Provinsi"), tr("Tahun Data"), tr("Area (ha)"), tr("Tersertifikasi (ha)"), tr("Cakupan (%)")}
+	for _, scheme := range analysis.Schemes {
+		headers = append(headers, trf("Cakupan %s (%%)", scheme))
+	}
+	headers = append(headers, tr("Laju (poin %/tahun)"), trf("Laju Dibutuhkan untuk %d", certificationTargetYear), tr("Proyeksi 100%"), tr("Status"))
+	yearColumn := len(headers) + 1
+	for year := 2003; year <= 2022; year++ {
+		headers = append(headers, trf("Cakupan %d (%%)", year))
+	}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, header)
+		column, _ := excelize.ColumnNumberToName(i + 1)
+		f.SetColWidth(sheet, column, column, 18)
+	}
+	f.SetColWidth(sheet, "A", "A", 24)
+
+	for i, certification := range analysis.Provinces {
+		row := i + 2
+		values := []interface{}{certification.Province, certification.LatestYear, math.Round(certification.Area),
+			math.Round(certification.Certified), math.Round(certification.LatestCoverage*10) / 10}
+		for _, scheme := range analysis.Schemes {
+			values = append(values, math.Round(certification.SchemeCoverage[scheme]*10)/10)
+		}
+		values = append(values, math.Round(certification.Pace*100)/100, math.Round(certification.RequiredPace*100)/100,
+			formatProjectedYear(certification), tr(certification.Status))
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, row)
+			f.SetCellValue(sheet, cell, value)
+		}
+		for year := 2003; year <= 2022; year++ {
+			if coverage, ok := certification.Coverage[year]; ok {
+				cell, _ := excelize.CoordinatesToCellName(yearColumn+year-2003, row)
+				f.SetCellValue(sheet, cell, math.Round(coverage*10)/10)
+			}
+		}
+	}
+}
+
+// createCertificationChart menggambar cakupan historis provinsi dengan area
+// terbesar dan proyeksi laju saat ini (putus-putus) sampai target.
+func createCertificationChart(models []ProvinceModel, analysis *CertificationAnalysis) error {
+	if analysis == nil {
+		return nil
+	}
+
+	byProvince := make(map[string]ProvinceCertification, len(analysis.Provinces))
+	for _, certification := range analysis.Provinces {
+		byProvince[certification.Province] = certification
+	}
+	var selected []ProvinceCertification
+	for _, model := range models {
+		if certification, ok := byProvince[model.Province]; ok && len(selected) < 8 {
+			selected = append(selected, certification)
+		}
+	}
+
+	p := plot.New()
+	p.Title.Text = trf("PROGRES SERTIFIKASI ISPO/RSPO MENUJU TARGET %d", certificationTargetYear)
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Tahun")
+	p.Y.Label.Text = tr("Cakupan Sertifikasi (%)")
+	p.Y.Min = 0
+	p.Y.Max = 110
+
+	for i, certification := range selected {
+		lineColor := chartTheme.Series[i%len(chartTheme.Series)]
+		years := getSortedYears(certification.Coverage)
+		points := make(plotter.XYs, len(years))
+		for j, year := range years {
+			points[j] = plotter.XY{X: float64(year), Y: certification.Coverage[year]}
+		}
+		line, scatter, err := plotter.NewLinePoints(points)
+		if err != nil {
+			return err
+		}
+		line.Color = lineColor
+		line.Width = vg.Points(2)
+		scatter.Color = lineColor
+		p.Add(line, scatter)
+		p.Legend.Add(getShortProvinceName(certification.Province), line)
+
+		if certification.Pace > 0 && certification.LatestCoverage < 100 {
+			endYear := float64(certificationTargetYear)
+			projected := math.Min(certification.LatestCoverage+certification.Pace*(endYear-float64(certification.LatestYear)), 100)
+			projection, err := plotter.NewLine(plotter.XYs{
+				{X: float64(certification.LatestYear), Y: certification.LatestCoverage},
+				{X: endYear, Y: projected},
+			})
+			if err != nil {
+				return err
+			}
+			projection.Color = lineColor
+			projection.Dashes = []vg.Length{vg.Points(4), vg.Points(2)}
+			p.Add(projection)
+		}
+	}
+
+	target, err := plotter.NewScatter(plotter.XYs{{X: certificationTargetYear, Y: 100}})
+	if err != nil {
+		return err
+	}
+	target.GlyphStyle.Color = color.RGBA{A: 255}
+	target.GlyphStyle.Radius = vg.Points(6)
+	p.Add(target)
+	p.Legend.Add(trf("Target %d", certificationTargetYear), target)
+
+	p.Legend.Top = true
+	p.Legend.Left = true
+	p.Add(plotter.NewGrid())
+	return saveChart(p, 14*vg.Inch, 8*vg.Inch, "progres_sertifikasi")
+}
+
+func buildCertificationReport(analysis *CertificationAnalysis) string {
+	if analysis == nil {
+		return ""
+	}
+
+	report := trf("\n### 🏅 PROGRES SERTIFIKASI MENUJU TARGET %d\n\n", certificationTargetYear)
+	report += trf("Cakupan sertifikasi adalah luas tersertifikasi (%s) dibagi area tertanam provinsi. Untuk provinsi dengan data, %.1f%% area tersertifikasi pada %d. Proyeksi memakai laju kenaikan cakupan %d tahun data terakhir.\n\n",
+		strings.Join(analysis.Schemes, ", "), analysis.nationalCoverage(), analysis.LatestYear, certificationTrendYears)
+
+	report += trf("| Provinsi | Tahun | Cakupan | Laju (poin/tahun) | Laju Dibutuhkan | Proyeksi 100%% | Status |\n")
+	report += "|----------|-------|---------|-------------------|-----------------|---------------|--------|\n"
+	for _, certification := range analysis.Provinces {
+		report += fmt.Sprintf("| %s | %d | %.1f%% | %+.2f | %.2f | %s | %s |\n", certification.Province, certification.LatestYear,
+			certification.LatestCoverage, certification.Pace, certification.RequiredPace, formatProjectedYear(certification), tr(certification.Status))
+	}
+
+	insufficient := analysis.insufficient()
+	if len(insufficient) > 0 {
+		report += trf("\nData sertifikasi baru tersedia untuk satu tahun, sehingga laju dan proyeksi belum dihitung (%s): %s.\n",
+			tr("INSUFFICIENT DATA"), strings.Join(insufficient, ", "))
+	}
+
+	offTrack := analysis.offTrack()
+	if len(offTrack) == 0 {
+		if len(insufficient) > 0 {
+			report += trf("\nSemua provinsi dengan laju terukur berada di jalur target %d.\n", certificationTargetYear)
+		} else {
+			report += trf("\nSemua provinsi dengan data sertifikasi berada di jalur target %d.\n", certificationTargetYear)
+		}
+		return report
+	}
+	report += trf("\n**⚠️ Provinsi tertinggal dari target %d (%d):**\n\n", certificationTargetYear, len(offTrack))
+	for _, certification := range offTrack {
+		if certification.Status == "STALLED" {
+			report += trf("- %s: cakupan %.1f%% tidak naik; butuh +%.2f poin per tahun\n", certification.Province, certification.LatestCoverage, certification.RequiredPace)
+		} else {
+			report += trf("- %s: cakupan %.1f%% baru mencapai 100%% sekitar %d; butuh +%.2f poin per tahun, kini +%.2f\n",
+				certification.Province, certification.LatestCoverage, certification.ProjectedYear, certification.RequiredPace, certification.Pace)
+		}
+	}
+	return report
+}
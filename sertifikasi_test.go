@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProjectCertification(t *testing.T) {
+	tests := []struct {
+		name          string
+		coverage      map[int]float64
+		wantStatus    string
+		wantPace      float64
+		wantRequired  float64
+		wantProjected int
+	}{
+		{"tercapai", map[int]float64{2021: 90, 2022: 100}, "ACHIEVED", 10, 0, 2022},
+		// Sisa 40 poin dengan laju 10 poin/tahun: 100% pada 2026.
+		{"di jalur", map[int]float64{2020: 40, 2021: 50, 2022: 60}, "ON TRACK", 10, 5, 2026},
+		// Sisa 86 poin dengan laju 2 poin/tahun: 100% baru pada 2065.
+		{"tertinggal", map[int]float64{2020: 10, 2021: 12, 2022: 14}, "OFF TRACK", 2, 10.75, 2065},
+		{"mandek", map[int]float64{2021: 30, 2022: 30}, "STALLED", 0, 8.75, 0},
+		// Laju hanya dari certificationTrendYears tahun terakhir, jadi kenaikan
+		// 2015-2018 tidak dihitung.
+		{"jendela tren", map[int]float64{2015: 0, 2018: 50, 2019: 50, 2020: 50, 2021: 50, 2022: 50}, "STALLED", 0, 6.25, 0},
+		{"satu tahun", map[int]float64{2022: 40}, "INSUFFICIENT DATA", 0, 7.5, 0},
+	}
+	for _, test := range tests {
+		certification := ProvinceCertification{
+			Province:       test.name,
+			Coverage:       test.coverage,
+			LatestYear:     2022,
+			LatestCoverage: test.coverage[2022],
+		}
+		projectCertification(&certification)
+		if certification.Status != test.wantStatus {
+			t.Errorf("%s: status %q, seharusnya %q", test.name, certification.Status, test.wantStatus)
+		}
+		if math.Abs(certification.Pace-test.wantPace) > 1e-9 {
+			t.Errorf("%s: laju %v, seharusnya %v", test.name, certification.Pace, test.wantPace)
+		}
+		if math.Abs(certification.RequiredPace-test.wantRequired) > 1e-9 {
+			t.Errorf("%s: laju dibutuhkan %v, seharusnya %v", test.name, certification.RequiredPace, test.wantRequired)
+		}
+		if certification.ProjectedYear != test.wantProjected {
+			t.Errorf("%s: proyeksi %d, seharusnya %d", test.name, certification.ProjectedYear, test.wantProjected)
+		}
+	}
+}
+
+func TestCertificationOffTrackExcludesInsufficientData(t *testing.T) {
+	analysis := CertificationAnalysis{Provinces: []ProvinceCertification{
+		{Province: "A", Status: "ON TRACK"},
+		{Province: "B", Status: "OFF TRACK"},
+		{Province: "C", Status: "STALLED"},
+		{Province: "D", Status: "INSUFFICIENT DATA"},
+	}}
+	if got := len(analysis.offTrack()); got != 2 {
+		t.Errorf("provinsi tertinggal %d, seharusnya 2", got)
+	}
+	if got := analysis.insufficient(); len(got) != 1 || got[0] != "D" {
+		t.Errorf("provinsi data kurang %v, seharusnya [D]", got)
+	}
+}
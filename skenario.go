@@ -0,0 +1,460 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Skenario kebijakan dibaca dari scenarioFile. Setiap skenario terdiri dari
+// beberapa aturan yang diterapkan berurutan di atas proyeksi baseline:
+//
+//	moratorium         tidak ada ekspansi di provinsi terpilih mulai from_year
+//	growth_multiplier  laju pertumbuhan baseline dikalikan factor
+//	replanting_freeze  kebun tua tidak ditanam ulang; area berkurang retirement_rate per tahun
+//	national_cap       total nasional dibatasi cap_hectares
+//
+// Daftar provinces yang kosong berarti semua provinsi.
+const (
+	scenarioFile           = "skenario.json"
+	projectionEndYear      = 2030
+	defaultRetirementRate  = 0.04
+	baselineScenarioName   = "BASELINE"
+	scenarioTopProvinces   = 10
+	scenarioChartStartYear = 2010
+)
+
+type ScenarioConfig struct {
+	Scenarios []ScenarioDefinition `json:"scenarios"`
+}
+
+type ScenarioDefinition struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Rules       []ScenarioRule `json:"rules"`
+}
+
+type ScenarioRule struct {
+	Type           string   `json:"type"`
+	Provinces      []string `json:"provinces"`
+	FromYear       int      `json:"from_year"`
+	ToYear         int      `json:"to_year"`
+	Factor         float64  `json:"factor"`
+	CapHectares    float64  `json:"cap_hectares"`
+	RetirementRate float64  `json:"retirement_rate"`
+}
+
+type ScenarioResult struct {
+	Name         string
+	Description  string
+	Trajectories map[string]map[int]float64
+	National     map[int]float64
+}
+
+func loadScenarioConfig() ScenarioConfig {
+	var config ScenarioConfig
+
+	content, err := os.ReadFile(scenarioFile)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(progressOutput, "🧭 %s tidak ditemukan, hanya skenario baseline yang dihitung\n", scenarioFile)
+		return config
+	} else if err != nil {
+		log.Fatal("Error membaca file skenario:", err)
+	}
+
+	if err := json.Unmarshal(content, &config); err != nil {
+		log.Fatal("Error parsing file skenario:", err)
+	}
+
+	for _, scenario := range config.Scenarios {
+		for _, rule := range scenario.Rules {
+			switch rule.Type {
+			case "moratorium", "growth_multiplier", "replanting_freeze", "national_cap":
+			default:
+				log.Fatalf("Skenario %q: tipe aturan tidak dikenal %q", scenario.Name, rule.Type)
+			}
+		}
+	}
+
+	return config
+}
+
+// runScenarios gagal bila aturan menyebut provinsi yang tidak ada di data,
+// agar salah ketik tidak diam-diam membuat aturan tidak berlaku.
+func runScenarios(models []ProvinceModel, config ScenarioConfig) ([]ScenarioResult, error) {
+	if err := checkScenarioProvinces(models, config); err != nil {
+		return nil, err
+	}
+
+	results := []ScenarioResult{
+		simulateScenario(models, ScenarioDefinition{
+			Name:        baselineScenarioName,
+			Description: tr("Proyeksi baseline dari laju pertumbuhan tahunan 2003-2022"),
+		}),
+	}
+
+	for _, scenario := range config.Scenarios {
+		results = append(results, simulateScenario(models, scenario))
+	}
+
+	fmt.Fprintf(progressOutput, "🧭 Skenario kebijakan disimulasikan: %d skenario (2023-%d)\n", len(results), projectionEndYear)
+	return results, nil
+}
+
+func checkScenarioProvinces(models []ProvinceModel, config ScenarioConfig) error {
+	for _, scenario := range config.Scenarios {
+		for i, rule := range scenario.Rules {
+			var unknown []string
+			for _, province := range rule.Provinces {
+				if !slices.ContainsFunc(models, func(model ProvinceModel) bool { return strings.EqualFold(model.Province, province) }) {
+					unknown = append(unknown, province)
+				}
+			}
+			if len(unknown) > 0 {
+				return fmt.Errorf("%s: skenario %q aturan %d (%s): provinsi tidak dikenal %s",
+					scenarioFile, scenario.Name, i+1, rule.Type, strings.Join(unknown, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+func simulateScenario(models []ProvinceModel, scenario ScenarioDefinition) ScenarioResult {
+	result := ScenarioResult{
+		Name:         scenario.Name,
+		Description:  scenario.Description,
+		Trajectories: make(map[string]map[int]float64),
+		National:     make(map[int]float64),
+	}
+
+	for _, model := range models {
+		result.Trajectories[model.Province] = map[int]float64{2022: model.TotalArea2022}
+		result.National[2022] += model.TotalArea2022
+	}
+
+	for year := 2023; year <= projectionEndYear; year++ {
+		for _, model := range models {
+			previous := result.Trajectories[model.Province][year-1]
+			rate := baselineAnnualGrowth(model)
+
+			for _, rule := range scenario.Rules {
+				if rule.Type == "growth_multiplier" && ruleApplies(rule, model.Province, year) {
+					rate *= rule.Factor
+				}
+			}
+
+			proposed := previous * (1 + rate/100)
+
+			for _, rule := range scenario.Rules {
+				if !ruleApplies(rule, model.Province, year) {
+					continue
+				}
+				switch rule.Type {
+				case "moratorium":
+					if proposed > previous {
+						proposed = previous
+					}
+				case "replanting_freeze":
+					retirement := rule.RetirementRate
+					if retirement == 0 {
+						retirement = defaultRetirementRate
+					}
+					proposed -= previous * retirement
+				}
+			}
+
+			result.Trajectories[model.Province][year] = proposed
+			result.National[year] += proposed
+		}
+
+		for _, rule := range scenario.Rules {
+			if rule.Type == "national_cap" && ruleApplies(rule, "", year) {
+				applyNationalCap(&result, year, rule.CapHectares)
+			}
+		}
+	}
+
+	return result
+}
+
+func ruleApplies(rule ScenarioRule, province string, year int) bool {
+	if rule.FromYear > 0 && year < rule.FromYear {
+		return false
+	}
+	if rule.ToYear > 0 && year > rule.ToYear {
+		return false
+	}
+	if province == "" || len(rule.Provinces) == 0 {
+		return true
+	}
+	for _, p := range rule.Provinces {
+		if strings.EqualFold(p, province) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyNationalCap memperkecil ekspansi provinsi secara proporsional agar
+// total nasional tepat sama dengan cap. Penyusutan provinsi lain ikut
+// dihitung sehingga ruang ekspansi adalah cap dikurangi total setelah
+// penyusutan. Bila total sudah melampaui cap tanpa ekspansi sama sekali,
+// ekspansi dihentikan dan total tetap di atas cap.
+func applyNationalCap(result *ScenarioResult, year int, capHectares float64) {
+	if capHectares <= 0 || result.National[year] <= capHectares {
+		return
+	}
+
+	expansion, contraction := 0.0, 0.0
+	for _, trajectory := range result.Trajectories {
+		if delta := trajectory[year] - trajectory[year-1]; delta > 0 {
+			expansion += delta
+		} else {
+			contraction += delta
+		}
+	}
+	if expansion == 0 {
+		return
+	}
+
+	allowed := capHectares - result.National[year-1] - contraction
+	scale := math.Max(0, allowed/expansion)
+
+	result.National[year] = 0
+	for _, trajectory := range result.Trajectories {
+		if delta := trajectory[year] - trajectory[year-1]; delta > 0 {
+			trajectory[year] = trajectory[year-1] + delta*scale
+		}
+		result.National[year] += trajectory[year]
+	}
+}
+
+func findScenario(results []ScenarioResult, name string) *ScenarioResult {
+	for i := range results {
+		if results[i].Name == name {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+func writeScenarioSheets(f *excelize.File, models []ProvinceModel, results []ScenarioResult) {
+	sheet := tr("Skenario_Nasional")
+	f.NewSheet(sheet)
+
+	f.SetCellValue(sheet, "A1", tr("Tahun"))
+	for j, result := range results {
+		cell, _ := excelize.CoordinatesToCellName(j+2, 1)
+		f.SetCellValue(sheet, cell, fmt.Sprintf("%s (%s)", result.Name, metricUnit()))
+		f.SetColWidth(sheet, cell, cell, 22)
+	}
+	for i, year := 0, 2022; year <= projectionEndYear; i, year = i+1, year+1 {
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", i+2), year)
+		for j, result := range results {
+			cell, _ := excelize.CoordinatesToCellName(j+2, i+2)
+			f.SetCellValue(sheet, cell, formatNumber(result.National[year]))
+		}
+	}
+
+	descriptionRow := projectionEndYear - 2022 + 4
+	f.SetCellValue(sheet, fmt.Sprintf("A%d", descriptionRow), tr("Skenario"))
+	f.SetCellValue(sheet, fmt.Sprintf("B%d", descriptionRow), tr("Deskripsi"))
+	for i, result := range results {
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", descriptionRow+i+1), result.Name)
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", descriptionRow+i+1), result.Description)
+	}
+
+	sheet = tr("Skenario_Provinsi")
+	f.NewSheet(sheet)
+
+	headers := []string{"Provinsi", "Skenario"}
+	for year := 2022; year <= projectionEndYear; year++ {
+		headers = append(headers, fmt.Sprintf("%d (%s)", year, metricUnit()))
+	}
+	headers = append(headers, trf("Selisih vs Baseline %d (%s)", projectionEndYear, metricUnit()))
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 16)
+	}
+
+	baseline := findScenario(results, baselineScenarioName)
+	row := 2
+	for _, model := range models {
+		for _, result := range results {
+			trajectory := result.Trajectories[model.Province]
+			f.SetCellValue(sheet, fmt.Sprintf("A%d", row), model.Province)
+			f.SetCellValue(sheet, fmt.Sprintf("B%d", row), result.Name)
+			for year := 2022; year <= projectionEndYear; year++ {
+				cell, _ := excelize.CoordinatesToCellName(year-2022+3, row)
+				f.SetCellValue(sheet, cell, formatNumber(trajectory[year]))
+			}
+			delta := trajectory[projectionEndYear] - baseline.Trajectories[model.Province][projectionEndYear]
+			cell, _ := excelize.CoordinatesToCellName(len(headers), row)
+			f.SetCellValue(sheet, cell, formatNumber(delta))
+			row++
+		}
+	}
+}
+
+func createNationalScenarioChart(trends []NationalTrend, results []ScenarioResult) error {
+	p := plot.New()
+	p.Title.Text = trf("SKENARIO KEBIJAKAN: PROYEKSI {{METRIC}} NASIONAL %d-%d", scenarioChartStartYear, projectionEndYear)
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Tahun")
+	p.Y.Label.Text = tr("Total {{Metric}} (juta {{unit}})")
+
+	var history plotter.XYs
+	for _, trend := range trends {
+		if trend.Year >= scenarioChartStartYear {
+			history = append(history, plotter.XY{X: float64(trend.Year), Y: trend.TotalArea / 1000000})
+		}
+	}
+	historyLine, err := plotter.NewLine(history)
+	if err != nil {
+		return err
+	}
+	historyLine.Color = color.RGBA{R: 0, G: 0, B: 0, A: 255}
+	historyLine.Width = vg.Points(2)
+	p.Add(historyLine)
+	p.Legend.Add(tr("Historis"), historyLine)
+
+	for i, result := range results {
+		points := make(plotter.XYs, 0, projectionEndYear-2021)
+		for year := 2022; year <= projectionEndYear; year++ {
+			points = append(points, plotter.XY{X: float64(year), Y: result.National[year] / 1000000})
+		}
+		line, err := plotter.NewLine(points)
+		if err != nil {
+			return err
+		}
+		line.Color = scenarioColor(i)
+		line.Width = vg.Points(2)
+		if result.Name == baselineScenarioName {
+			line.Dashes = []vg.Length{vg.Points(5), vg.Points(5)}
+		}
+		p.Add(line)
+		p.Legend.Add(result.Name, line)
+	}
+
+	p.Legend.Top = true
+	p.Legend.Left = true
+	p.Add(plotter.NewGrid())
+
+	return saveChart(p, 16*vg.Inch, 10*vg.Inch, "skenario_nasional_2030")
+}
+
+func createProvinceScenarioChart(models []ProvinceModel, results []ScenarioResult) error {
+	p := plot.New()
+	p.Title.Text = trf("PROYEKSI %d PER SKENARIO - %d PROVINSI TERBESAR", projectionEndYear, scenarioTopProvinces)
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Provinsi")
+	p.Y.Label.Text = trf("{{Metric}} %d (juta {{unit}})", projectionEndYear)
+
+	top := models
+	if len(top) > scenarioTopProvinces {
+		top = top[:scenarioTopProvinces]
+	}
+
+	labels := make([]string, len(top))
+	for i, model := range top {
+		labels[i] = getShortProvinceName(model.Province)
+	}
+
+	barWidth := vg.Points(60 / float64(len(results)))
+	for i, result := range results {
+		values := make(plotter.Values, len(top))
+		for j, model := range top {
+			values[j] = result.Trajectories[model.Province][projectionEndYear] / 1000000
+		}
+
+		bars, err := plotter.NewBarChart(values, barWidth)
+		if err != nil {
+			return err
+		}
+		bars.Color = scenarioColor(i)
+		bars.LineStyle.Width = vg.Length(0)
+		bars.Offset = barWidth * vg.Length(float64(i)-float64(len(results)-1)/2)
+
+		p.Add(bars)
+		p.Legend.Add(result.Name, bars)
+	}
+
+	p.NominalX(labels...)
+	p.Legend.Top = true
+	p.Add(plotter.NewGrid())
+
+	return saveChart(p, 20*vg.Inch, 10*vg.Inch, "skenario_provinsi_2030")
+}
+
+func scenarioColor(index int) color.RGBA {
+	return chartTheme.Scenarios[index%len(chartTheme.Scenarios)]
+}
+
+func buildScenarioReport(models []ProvinceModel, results []ScenarioResult) string {
+	baseline := findScenario(results, baselineScenarioName)
+	if baseline == nil {
+		return ""
+	}
+
+	report := trf("\n### 🧭 SKENARIO KEBIJAKAN 2023-%d\n\n", projectionEndYear)
+	report += trf("| Skenario | {{Metric}} Nasional %d | Selisih vs Baseline | Deskripsi |\n", projectionEndYear)
+	report += "|----------|--------------------|---------------------|-----------|\n"
+
+	for _, result := range results {
+		delta := result.National[projectionEndYear] - baseline.National[projectionEndYear]
+		unit := metricUnit()
+		report += fmt.Sprintf("| %s | %s %s | %s %s | %s |\n",
+			result.Name, formatNumber(result.National[projectionEndYear]), unit, formatSignedNumber(delta), unit, result.Description)
+	}
+
+	report += "\n"
+	for _, result := range results {
+		if result.Name == baselineScenarioName {
+			continue
+		}
+
+		type impact struct {
+			province string
+			delta    float64
+		}
+		var impacts []impact
+		for _, model := range models {
+			delta := result.Trajectories[model.Province][projectionEndYear] - baseline.Trajectories[model.Province][projectionEndYear]
+			if delta != 0 {
+				impacts = append(impacts, impact{model.Province, delta})
+			}
+		}
+		sort.Slice(impacts, func(i, j int) bool {
+			return impacts[i].delta < impacts[j].delta
+		})
+
+		if len(impacts) > 0 {
+			var parts []string
+			for i := 0; i < len(impacts) && i < 3; i++ {
+				parts = append(parts, fmt.Sprintf("%s (%s %s)", impacts[i].province, formatSignedNumber(impacts[i].delta), metricUnit()))
+			}
+			report += trf("- **%s**: dampak terbesar pada %s\n", result.Name, strings.Join(parts, ", "))
+		}
+	}
+
+	return report
+}
+
+func formatSignedNumber(num float64) string {
+	if num < 0 {
+		return "-" + formatNumber(-num)
+	}
+	return "+" + formatNumber(num)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestApplyNationalCap(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b         float64 // area 2023 provinsi A dan B; keduanya 100 pada 2022
+		cap          float64
+		wantA        float64
+		wantB        float64
+		wantNational float64
+	}{
+		{"di bawah cap", 120, 90, 250, 120, 90, 210},
+		{"hanya ekspansi", 140, 120, 230, 120, 110, 230},
+		// Penyusutan B menambah ruang ekspansi A: 210 - 200 + 20 = 30.
+		{"ekspansi dan penyusutan", 150, 80, 210, 130, 80, 210},
+		// Total 2022 sudah di atas cap: ekspansi dihentikan, penyusutan tetap.
+		{"sudah melampaui cap", 110, 70, 150, 100, 70, 170},
+		{"tanpa ekspansi", 90, 95, 150, 90, 95, 185},
+	}
+	for _, test := range tests {
+		result := ScenarioResult{
+			Trajectories: map[string]map[int]float64{
+				"A": {2022: 100, 2023: test.a},
+				"B": {2022: 100, 2023: test.b},
+			},
+			National: map[int]float64{2022: 200, 2023: test.a + test.b},
+		}
+		applyNationalCap(&result, 2023, test.cap)
+		got := []float64{result.Trajectories["A"][2023], result.Trajectories["B"][2023], result.National[2023]}
+		want := []float64{test.wantA, test.wantB, test.wantNational}
+		for i := range want {
+			if math.Abs(got[i]-want[i]) > 1e-9 {
+				t.Errorf("%s: A, B, nasional = %v, seharusnya %v", test.name, got, want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Bobot dan transformasi skor komposit dibaca dari scoringFile. Setiap
+// komponen mengambil satu field ProvinceModel, ditransformasi (linear, log,
+// sqrt, inverse), dinormalisasi antarprovinsi (minmax atau percentile) ke
+// 0-1, lalu dikalikan bobot relatifnya sehingga total kontribusi berada di
+// skala 0-10. Provinsi yang memiliki data produksi (lihat productionFile)
+// memakai komponen YieldEfficiency sebagai pengganti Efficiency. Komponen
+// dengan Benchmark dinormalisasi terhadap rentang tetap, bukan antarprovinsi,
+// sehingga skor hasil per hektar tetap berada di skala 0-10 yang sama dengan
+// provinsi tanpa data produksi dan dapat masuk ke skor daya saing.
+const scoringFile = "skor.json"
+
+type ScoringConfig struct {
+	Normalization        string             `json:"normalization"`
+	Efficiency           []ScoreComponent   `json:"efficiency"`
+	YieldEfficiency      []ScoreComponent   `json:"yield_efficiency"`
+	Competitiveness      []ScoreComponent   `json:"competitiveness"`
+	InvestmentThresholds map[string]float64 `json:"investment_thresholds"`
+}
+
+type ScoreComponent struct {
+	Name      string  `json:"name"`
+	Field     string  `json:"field"`
+	Weight    float64 `json:"weight"`
+	Transform string  `json:"transform"`
+	// Benchmark berisi nilai mentah untuk skor 0 dan skor 10. Nilai di luar
+	// rentang dipotong; urutan kedua nilai menentukan arah skor.
+	Benchmark []float64 `json:"benchmark,omitempty"`
+}
+
+type ScoreContribution struct {
+	Component    string
+	RawValue     float64
+	Normalized   float64
+	Weight       float64
+	Contribution float64
+}
+
+var investmentLevels = []string{"VERY HIGH", "HIGH", "MEDIUM", "LOW"}
+
+func defaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		Normalization: "minmax",
+		Efficiency: []ScoreComponent{
+			{Name: "Skala {{Metric}}", Field: "TotalArea2022", Weight: 0.4, Transform: "log"},
+			{Name: "Pertumbuhan", Field: "GrowthRate20Years", Weight: 0.3, Transform: "log"},
+			{Name: "Stabilitas", Field: "StabilityIndex", Weight: 0.3, Transform: "linear"},
+		},
+		YieldEfficiency: []ScoreComponent{
+			{Name: "Hasil TBS", Field: "FFBYield", Weight: 0.6, Transform: "linear", Benchmark: []float64{8, 24}},
+			{Name: "Tren Hasil", Field: "YieldTrend", Weight: 0.25, Transform: "linear", Benchmark: []float64{-0.5, 0.5}},
+			{Name: "Rendemen CPO", Field: "ExtractionRate", Weight: 0.15, Transform: "linear", Benchmark: []float64{18, 24}},
+		},
+		Competitiveness: []ScoreComponent{
+			{Name: "Market Share", Field: "MarketShare2022", Weight: 0.3, Transform: "log"},
+			{Name: "Pertumbuhan", Field: "GrowthRate20Years", Weight: 0.2, Transform: "log"},
+			{Name: "Efisiensi", Field: "ProductionEfficiency", Weight: 0.25, Transform: "linear"},
+			{Name: "Stabilitas", Field: "StabilityIndex", Weight: 0.25, Transform: "linear"},
+		},
+		InvestmentThresholds: map[string]float64{
+			"VERY HIGH": 8.0,
+			"HIGH":      6.5,
+			"MEDIUM":    5.0,
+			"LOW":       3.0,
+		},
+	}
+}
+
+func loadScoringConfig() ScoringConfig {
+	config := defaultScoringConfig()
+
+	content, err := os.ReadFile(scoringFile)
+	if os.IsNotExist(err) {
+		return config
+	} else if err != nil {
+		log.Fatal("Error membaca konfigurasi skor:", err)
+	}
+
+	if err := json.Unmarshal(content, &config); err != nil {
+		log.Fatal("Error parsing konfigurasi skor:", err)
+	}
+
+	switch config.Normalization {
+	case "minmax", "percentile":
+	default:
+		log.Fatalf("Normalisasi skor tidak dikenal: %q (gunakan minmax atau percentile)", config.Normalization)
+	}
+	components := append(append(append([]ScoreComponent{}, config.Efficiency...), config.YieldEfficiency...), config.Competitiveness...)
+	for _, component := range components {
+		if _, ok := scoreFieldValue(ProvinceModel{}, component.Field); !ok {
+			log.Fatalf("Komponen skor %q: field tidak dikenal %q", component.Name, component.Field)
+		}
+		if len(component.Benchmark) > 0 && (len(component.Benchmark) != 2 || component.Benchmark[0] == component.Benchmark[1]) {
+			log.Fatalf("Komponen skor %q: benchmark harus berisi dua nilai berbeda [skor 0, skor 10]", component.Name)
+		}
+	}
+
+	return config
+}
+
+func applyCompositeScores(models []ProvinceModel, config ScoringConfig) {
+	efficiency := calculateCompositeScore(models, config.Efficiency, config.Normalization)
+	for i := range models {
+		models[i].EfficiencyBreakdown = efficiency[i]
+		models[i].ProductionEfficiency = sumContributions(efficiency[i])
+	}
+	applyYieldEfficiency(models, config)
+
+	competitiveness := calculateCompositeScore(models, config.Competitiveness, config.Normalization)
+	for i := range models {
+		models[i].CompetitivenessBreakdown = competitiveness[i]
+		models[i].Competitiveness = sumContributions(competitiveness[i])
+		models[i].InvestmentPotential = assessInvestmentPotential(models[i], config)
+	}
+}
+
+// applyYieldEfficiency mengganti skor efisiensi sintetis dengan skor hasil
+// per hektar untuk provinsi yang memiliki data produksi. Komponen tanpa
+// Benchmark dinormalisasi hanya di antara provinsi tersebut.
+func applyYieldEfficiency(models []ProvinceModel, config ScoringConfig) {
+	var indices []int
+	var withYield []ProvinceModel
+	for i, model := range models {
+		if model.Yield != nil {
+			indices = append(indices, i)
+			withYield = append(withYield, model)
+		}
+	}
+	if len(withYield) == 0 || len(config.YieldEfficiency) == 0 {
+		return
+	}
+
+	efficiency := calculateCompositeScore(withYield, config.YieldEfficiency, config.Normalization)
+	for j, i := range indices {
+		models[i].EfficiencyBreakdown = efficiency[j]
+		models[i].ProductionEfficiency = sumContributions(efficiency[j])
+	}
+}
+
+func calculateCompositeScore(models []ProvinceModel, components []ScoreComponent, normalization string) [][]ScoreContribution {
+	totalWeight := 0.0
+	for _, component := range components {
+		totalWeight += component.Weight
+	}
+
+	breakdown := make([][]ScoreContribution, len(models))
+	for _, component := range components {
+		raw := make([]float64, len(models))
+		transformed := make([]float64, len(models))
+		for i, model := range models {
+			raw[i], _ = scoreFieldValue(model, component.Field)
+			transformed[i] = transformScoreValue(raw[i], component.Transform)
+		}
+
+		var normalized []float64
+		if len(component.Benchmark) == 2 {
+			normalized = benchmarkScores(transformed, component)
+		} else {
+			normalized = normalizeScores(transformed, normalization)
+		}
+		for i := range models {
+			contribution := 0.0
+			if totalWeight > 0 {
+				contribution = component.Weight / totalWeight * normalized[i] * 10
+			}
+			breakdown[i] = append(breakdown[i], ScoreContribution{
+				Component:    component.Name,
+				RawValue:     raw[i],
+				Normalized:   normalized[i],
+				Weight:       component.Weight,
+				Contribution: contribution,
+			})
+		}
+	}
+
+	return breakdown
+}
+
+func scoreFieldValue(model ProvinceModel, field string) (float64, bool) {
+	switch field {
+	case "TotalArea2022":
+		return model.TotalArea2022, true
+	case "TotalArea2003":
+		return model.TotalArea2003, true
+	case "GrowthRate20Years":
+		return model.GrowthRate20Years, true
+	case "AnnualGrowthRate":
+		return model.AnnualGrowthRate, true
+	case "MarketShare2022":
+		return model.MarketShare2022, true
+	case "StabilityIndex":
+		return model.StabilityIndex, true
+	case "ProductionEfficiency":
+		return model.ProductionEfficiency, true
+	case "PeakArea":
+		return model.PeakArea, true
+	case "FFBYield":
+		return yieldOf(model).FFBYield, true
+	case "CPOYield":
+		return yieldOf(model).CPOYield, true
+	case "YieldTrend":
+		return yieldOf(model).Trend, true
+	case "ExtractionRate":
+		return yieldOf(model).ExtractionRate, true
+	case "YieldGap":
+		return yieldOf(model).Gap, true
+	}
+	return 0, false
+}
+
+// yieldOf mengembalikan produktivitas kosong untuk provinsi tanpa data
+// produksi.
+func yieldOf(model ProvinceModel) ProvinceYield {
+	if model.Yield == nil {
+		return ProvinceYield{}
+	}
+	return *model.Yield
+}
+
+func transformScoreValue(value float64, transform string) float64 {
+	switch transform {
+	case "log":
+		return math.Copysign(math.Log1p(math.Abs(value)), value)
+	case "sqrt":
+		return math.Copysign(math.Sqrt(math.Abs(value)), value)
+	case "inverse":
+		return -value
+	}
+	return value
+}
+
+func normalizeScores(values []float64, normalization string) []float64 {
+	normalized := make([]float64, len(values))
+	if len(values) < 2 {
+		for i := range normalized {
+			normalized[i] = 0.5
+		}
+		return normalized
+	}
+
+	if normalization == "percentile" {
+		order := make([]int, len(values))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return values[order[a]] < values[order[b]]
+		})
+		for rank := 0; rank < len(order); {
+			end := rank
+			for end+1 < len(order) && values[order[end+1]] == values[order[rank]] {
+				end++
+			}
+			share := (float64(rank) + float64(end)) / 2 / float64(len(values)-1)
+			for k := rank; k <= end; k++ {
+				normalized[order[k]] = share
+			}
+			rank = end + 1
+		}
+		return normalized
+	}
+
+	min, max := values[0], values[0]
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	for i, v := range values {
+		if max > min {
+			normalized[i] = (v - min) / (max - min)
+		} else {
+			normalized[i] = 0.5
+		}
+	}
+	return normalized
+}
+
+// benchmarkScores menormalisasi nilai yang sudah ditransformasi terhadap
+// Benchmark komponen, tidak bergantung pada provinsi lain.
+func benchmarkScores(values []float64, component ScoreComponent) []float64 {
+	low := transformScoreValue(component.Benchmark[0], component.Transform)
+	high := transformScoreValue(component.Benchmark[1], component.Transform)
+	normalized := make([]float64, len(values))
+	for i, v := range values {
+		normalized[i] = math.Max(0, math.Min(1, (v-low)/(high-low)))
+	}
+	return normalized
+}
+
+func sumContributions(contributions []ScoreContribution) float64 {
+	total := 0.0
+	for _, c := range contributions {
+		total += c.Contribution
+	}
+	return total
+}
+
+func writeScoreBreakdownSheet(f *excelize.File, models []ProvinceModel, config ScoringConfig) {
+	sheet := tr("Rincian_Skor")
+	f.NewSheet(sheet)
+
+	f.SetCellValue(sheet, "A1", trf("RINCIAN SKOR KOMPOSIT (normalisasi: %s, skala 0-10)", config.Normalization))
+
+	// Kolom komponen hasil per hektar hanya ditulis bila ada provinsi yang
+	// skor efisiensinya dihitung dari data produksi.
+	var yieldComponents []ScoreComponent
+	if len(yieldModels(models)) > 0 {
+		yieldComponents = config.YieldEfficiency
+	}
+
+	headers := []string{"Provinsi", "Skor", "Total"}
+	for _, component := range config.Efficiency {
+		headers = append(headers, trf("Efisiensi: %s (w=%.2f, %s)", tr(component.Name), component.Weight, component.Transform))
+	}
+	for _, component := range yieldComponents {
+		headers = append(headers, trf("Efisiensi Hasil: %s (w=%.2f, %s)", tr(component.Name), component.Weight, component.Transform))
+	}
+	for _, component := range config.Competitiveness {
+		headers = append(headers, trf("Daya Saing: %s (w=%.2f, %s)", tr(component.Name), component.Weight, component.Transform))
+	}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 2)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 22)
+	}
+
+	row := 3
+	for _, model := range models {
+		efficiencyOffset := 0
+		if model.Yield != nil && len(yieldComponents) > 0 {
+			efficiencyOffset = len(config.Efficiency)
+		}
+		for _, score := range []struct {
+			name      string
+			total     float64
+			breakdown []ScoreContribution
+			offset    int
+		}{
+			{"Efisiensi Produksi", model.ProductionEfficiency, model.EfficiencyBreakdown, efficiencyOffset},
+			{"Daya Saing", model.Competitiveness, model.CompetitivenessBreakdown, len(config.Efficiency) + len(yieldComponents)},
+		} {
+			f.SetCellValue(sheet, fmt.Sprintf("A%d", row), model.Province)
+			f.SetCellValue(sheet, fmt.Sprintf("B%d", row), tr(score.name))
+			f.SetCellValue(sheet, fmt.Sprintf("C%d", row), fmt.Sprintf("%.2f", score.total))
+			for j, contribution := range score.breakdown {
+				cell, _ := excelize.CoordinatesToCellName(4+score.offset+j, row)
+				f.SetCellValue(sheet, cell, trf("%.2f (nilai %.1f, norm %.2f)",
+					contribution.Contribution, contribution.RawValue, contribution.Normalized))
+			}
+			row++
+		}
+	}
+}
+
+func buildScoreBreakdownReport(models []ProvinceModel, config ScoringConfig) string {
+	report := tr("\n### 🧮 RINCIAN SKOR DAYA SAING\n\n")
+
+	// Bobot dinormalisasi seperti pada perhitungan skor, jadi konfigurasi
+	// yang bobotnya tidak berjumlah 1 tetap tercetak sebagai persentase nyata.
+	totalWeight := 0.0
+	for _, component := range config.Competitiveness {
+		totalWeight += component.Weight
+	}
+	var weights []string
+	for _, component := range config.Competitiveness {
+		share := 0.0
+		if totalWeight > 0 {
+			share = component.Weight / totalWeight * 100
+		}
+		weights = append(weights, fmt.Sprintf("%s %.0f%% (%s)", tr(component.Name), share, component.Transform))
+	}
+	report += trf("Skor daya saing (0-10) adalah jumlah kontribusi komponen berbobot setelah normalisasi %s: %s. ",
+		config.Normalization, strings.Join(weights, ", "))
+
+	var thresholds []string
+	for _, level := range investmentLevels {
+		thresholds = append(thresholds, fmt.Sprintf("%s ≥ %.1f", tr(level), config.InvestmentThresholds[level]))
+	}
+	report += trf("Potensi investasi: %s, selebihnya VERY LOW.\n\n", strings.Join(thresholds, ", "))
+
+	report += tr("| Provinsi | Daya Saing |")
+	separator := "|----------|------------|"
+	for _, component := range config.Competitiveness {
+		report += " " + tr(component.Name) + " |"
+		separator += "------|"
+	}
+	report += tr(" Potensi Investasi |\n") + separator + "-------------------|\n"
+
+	for _, model := range models {
+		report += fmt.Sprintf("| %s | %.2f |", model.Province, model.Competitiveness)
+		for _, contribution := range model.CompetitivenessBreakdown {
+			report += fmt.Sprintf(" %.2f |", contribution.Contribution)
+		}
+		report += fmt.Sprintf(" %s |\n", tr(model.InvestmentPotential))
+	}
+
+	return report
+}
@@ -0,0 +1,667 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// File tetangga bersifat opsional. Adjacency berisi pasangan
+// region_trase_id,neighbor_trase_id; centroid berisi
+// region_trase_id,latitude,longitude dan dipakai dengan k tetangga terdekat.
+const (
+	adjacencyFile      = "adjacency_kabupaten.csv"
+	centroidFile       = "centroid_kabupaten.csv"
+	nearestNeighbors   = 6
+	spatialPermutation = 999
+	spatialSignificant = 0.05
+	spatialSeed        = 20030101
+)
+
+type SpatialWeights struct {
+	Source    string
+	Neighbors map[string][]string
+}
+
+type MoranResult struct {
+	Variable  string
+	MoranI    float64
+	Expected  float64
+	ZScore    float64
+	PValue    float64
+	Units     int
+	Weighting string
+}
+
+type RegencySpatialStat struct {
+	RegionID      string
+	Region        string
+	Province      string
+	Area2022      float64
+	Growth        float64
+	AreaLocalI    float64
+	AreaPValue    float64
+	AreaCluster   string
+	AreaGiStar    float64
+	AreaHotspot   string
+	GrowthLocalI  float64
+	GrowthPValue  float64
+	GrowthCluster string
+	GrowthGiStar  float64
+	GrowthHotspot string
+	GrowthZ       float64
+	GrowthLag     float64
+}
+
+type SpatialAnalysis struct {
+	Global    []MoranResult
+	Regencies []RegencySpatialStat
+}
+
+func analyzeSpatialAutocorrelation(cube *DataCube) (*SpatialAnalysis, error) {
+	weights, err := loadSpatialWeights()
+	if err != nil {
+		return nil, err
+	}
+	if weights == nil {
+		fmt.Fprintf(progressOutput, "🗺️  Analisis spasial dilewati: %s atau %s tidak ditemukan\n", adjacencyFile, centroidFile)
+		return nil, nil
+	}
+
+	regencies := buildRegencySpatialStats(cube, weights)
+	if len(regencies) < 3 {
+		fmt.Fprintln(progressOutput, "🗺️  Analisis spasial dilewati: kabupaten dengan tetangga terlalu sedikit")
+		return nil, nil
+	}
+
+	ids := make([]string, len(regencies))
+	areas := make([]float64, len(regencies))
+	growths := make([]float64, len(regencies))
+	for i, regency := range regencies {
+		ids[i] = regency.RegionID
+		areas[i] = regency.Area2022
+		growths[i] = regency.Growth
+	}
+	neighbors := indexNeighbors(ids, weights)
+	rng := rand.New(rand.NewSource(spatialSeed))
+
+	analysis := &SpatialAnalysis{Regencies: regencies}
+	analysis.Global = append(analysis.Global,
+		calculateGlobalMoran("{{Metric}} 2022 ({{unit}})", areas, neighbors, weights.Source),
+		calculateGlobalMoran("Pertumbuhan 2003-2022 ({{unit}})", growths, neighbors, weights.Source))
+
+	areaLocal, areaP := calculateLocalMoran(areas, neighbors, rng)
+	growthLocal, growthP := calculateLocalMoran(growths, neighbors, rng)
+	areaGi := calculateGetisOrdGiStar(areas, neighbors)
+	growthGi := calculateGetisOrdGiStar(growths, neighbors)
+	areaZ := standardizeDeviations(areas)
+	growthZ := standardizeDeviations(growths)
+	growthSD := standardDeviation(growthZ)
+
+	for i := range analysis.Regencies {
+		regency := &analysis.Regencies[i]
+		regency.AreaLocalI, regency.AreaPValue = areaLocal[i], areaP[i]
+		regency.AreaCluster = classifyLISA(areaZ[i], spatialLag(areaZ, neighbors[i]), areaP[i])
+		regency.AreaGiStar = areaGi[i]
+		regency.AreaHotspot = classifyHotspot(areaGi[i])
+		regency.GrowthLocalI, regency.GrowthPValue = growthLocal[i], growthP[i]
+		regency.GrowthZ = growthZ[i] / growthSD
+		regency.GrowthLag = spatialLag(growthZ, neighbors[i]) / growthSD
+		regency.GrowthCluster = classifyLISA(growthZ[i], spatialLag(growthZ, neighbors[i]), growthP[i])
+		regency.GrowthGiStar = growthGi[i]
+		regency.GrowthHotspot = classifyHotspot(growthGi[i])
+	}
+
+	fmt.Fprintf(progressOutput, "🗺️  Autokorelasi spasial dihitung: %d kabupaten (%s)\n", len(regencies), weights.Source)
+	return analysis, nil
+}
+
+func loadSpatialWeights() (*SpatialWeights, error) {
+	records, err := readOptionalCSV(adjacencyFile)
+	if err != nil {
+		return nil, err
+	}
+	if records != nil {
+		weights := &SpatialWeights{Source: "adjacency", Neighbors: make(map[string][]string)}
+		for i, record := range records {
+			if i == 0 || len(record) < 2 || record[0] == record[1] {
+				continue
+			}
+			weights.Neighbors[record[0]] = appendUnique(weights.Neighbors[record[0]], record[1])
+			weights.Neighbors[record[1]] = appendUnique(weights.Neighbors[record[1]], record[0])
+		}
+		return weights, nil
+	}
+
+	centroids, err := loadCentroids()
+	if err != nil {
+		return nil, err
+	}
+	if centroids != nil {
+		weights := &SpatialWeights{
+			Source:    fmt.Sprintf("centroid k=%d", nearestNeighbors),
+			Neighbors: make(map[string][]string),
+		}
+		for _, c := range centroids {
+			type candidate struct {
+				id       string
+				distance float64
+			}
+			var candidates []candidate
+			for _, other := range centroids {
+				if other.ID != c.ID {
+					candidates = append(candidates, candidate{other.ID, haversineKm(c.Lat, c.Lon, other.Lat, other.Lon)})
+				}
+			}
+			sort.Slice(candidates, func(i, j int) bool {
+				return candidates[i].distance < candidates[j].distance
+			})
+			for k := 0; k < nearestNeighbors && k < len(candidates); k++ {
+				weights.Neighbors[c.ID] = append(weights.Neighbors[c.ID], candidates[k].id)
+			}
+		}
+		return weights, nil
+	}
+
+	return nil, nil
+}
+
+type RegencyCentroid struct {
+	ID  string
+	Lat float64
+	Lon float64
+}
+
+// loadCentroids membaca centroidFile; nil tanpa error bila file tidak ada.
+func loadCentroids() ([]RegencyCentroid, error) {
+	records, err := readOptionalCSV(centroidFile)
+	if err != nil || records == nil {
+		return nil, err
+	}
+
+	centroids := []RegencyCentroid{}
+	for i, record := range records {
+		if i == 0 || len(record) < 3 {
+			continue
+		}
+		lat, errLat := strconv.ParseFloat(record[1], 64)
+		lon, errLon := strconv.ParseFloat(record[2], 64)
+		if errLat != nil || errLon != nil {
+			continue
+		}
+		centroids = append(centroids, RegencyCentroid{ID: record[0], Lat: lat, Lon: lon})
+	}
+	return centroids, nil
+}
+
+// readOptionalCSV mengembalikan nil tanpa error bila file tidak ada; error
+// lain (mis. izin akses) tetap dikembalikan.
+func readOptionalCSV(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error membaca %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// buildRegencySpatialStats memakai kabupaten kubus yang memiliki tetangga;
+// urutannya mengikuti RegionID seperti Regencies kubus.
+func buildRegencySpatialStats(cube *DataCube, weights *SpatialWeights) []RegencySpatialStat {
+	var stats []RegencySpatialStat
+	for _, regency := range cube.Regencies {
+		if _, ok := weights.Neighbors[regency.ID]; !ok {
+			continue
+		}
+		area2022 := cube.RegencyArea(regency.ID, 2022)
+		stats = append(stats, RegencySpatialStat{
+			RegionID: regency.ID,
+			Region:   regency.Name,
+			Province: regency.Province,
+			Area2022: area2022,
+			Growth:   area2022 - cube.RegencyArea(regency.ID, 2003),
+		})
+	}
+	return stats
+}
+
+func indexNeighbors(ids []string, weights *SpatialWeights) [][]int {
+	position := make(map[string]int, len(ids))
+	for i, id := range ids {
+		position[id] = i
+	}
+
+	neighbors := make([][]int, len(ids))
+	for i, id := range ids {
+		for _, neighbor := range weights.Neighbors[id] {
+			if j, ok := position[neighbor]; ok {
+				neighbors[i] = append(neighbors[i], j)
+			}
+		}
+	}
+	return neighbors
+}
+
+func calculateGlobalMoran(variable string, values []float64, neighbors [][]int, weighting string) MoranResult {
+	n := float64(len(values))
+	z := standardizeDeviations(values)
+
+	numerator, denominator := 0.0, 0.0
+	s0, s1 := 0.0, 0.0
+	rowSums := make([]float64, len(values))
+	colSums := make([]float64, len(values))
+
+	for i := range values {
+		denominator += z[i] * z[i]
+		if len(neighbors[i]) == 0 {
+			continue
+		}
+		w := 1 / float64(len(neighbors[i]))
+		for _, j := range neighbors[i] {
+			numerator += w * z[i] * z[j]
+			s0 += w
+			rowSums[i] += w
+			colSums[j] += w
+		}
+	}
+
+	for i := range values {
+		for _, j := range neighbors[i] {
+			wij := 1 / float64(len(neighbors[i]))
+			wji := 0.0
+			for _, k := range neighbors[j] {
+				if k == i {
+					wji = 1 / float64(len(neighbors[j]))
+				}
+			}
+			if wji == 0 {
+				s1 += wij * wij
+			} else {
+				s1 += (wij + wji) * (wij + wji) / 2
+			}
+		}
+	}
+
+	s2 := 0.0
+	for i := range values {
+		s2 += math.Pow(rowSums[i]+colSums[i], 2)
+	}
+
+	result := MoranResult{
+		Variable:  variable,
+		Expected:  -1 / (n - 1),
+		Units:     len(values),
+		Weighting: weighting,
+	}
+	if s0 == 0 || denominator == 0 {
+		result.PValue = 1
+		return result
+	}
+
+	result.MoranI = (n / s0) * numerator / denominator
+	variance := (n*n*s1-n*s2+3*s0*s0)/((n*n-1)*s0*s0) - result.Expected*result.Expected
+	if variance > 0 {
+		result.ZScore = (result.MoranI - result.Expected) / math.Sqrt(variance)
+		result.PValue = 2 * (1 - normalCDF(math.Abs(result.ZScore)))
+	}
+	return result
+}
+
+func calculateLocalMoran(values []float64, neighbors [][]int, rng *rand.Rand) ([]float64, []float64) {
+	n := len(values)
+	z := standardizeDeviations(values)
+	m2 := 0.0
+	for _, v := range z {
+		m2 += v * v
+	}
+	m2 /= float64(n)
+
+	localI := make([]float64, n)
+	pValues := make([]float64, n)
+	if m2 == 0 {
+		for i := range pValues {
+			pValues[i] = 1
+		}
+		return localI, pValues
+	}
+
+	others := make([]int, 0, n-1)
+	for i := 0; i < n; i++ {
+		k := len(neighbors[i])
+		if k == 0 {
+			pValues[i] = 1
+			continue
+		}
+
+		localI[i] = z[i] / m2 * spatialLag(z, neighbors[i])
+
+		others = others[:0]
+		for j := 0; j < n; j++ {
+			if j != i {
+				others = append(others, j)
+			}
+		}
+
+		extreme := 0
+		for p := 0; p < spatialPermutation; p++ {
+			lag := 0.0
+			for s := 0; s < k; s++ {
+				r := s + rng.Intn(len(others)-s)
+				others[s], others[r] = others[r], others[s]
+				lag += z[others[s]]
+			}
+			permuted := z[i] / m2 * lag / float64(k)
+			if math.Abs(permuted) >= math.Abs(localI[i]) {
+				extreme++
+			}
+		}
+		pValues[i] = float64(extreme+1) / float64(spatialPermutation+1)
+	}
+
+	return localI, pValues
+}
+
+func calculateGetisOrdGiStar(values []float64, neighbors [][]int) []float64 {
+	n := float64(len(values))
+	mean, sumSquares := 0.0, 0.0
+	for _, v := range values {
+		mean += v
+		sumSquares += v * v
+	}
+	mean /= n
+	s := math.Sqrt(sumSquares/n - mean*mean)
+
+	scores := make([]float64, len(values))
+	if s == 0 {
+		return scores
+	}
+
+	for i := range values {
+		weightSum := float64(len(neighbors[i]) + 1)
+		localSum := values[i]
+		for _, j := range neighbors[i] {
+			localSum += values[j]
+		}
+		denominator := s * math.Sqrt((n*weightSum-weightSum*weightSum)/(n-1))
+		if denominator > 0 {
+			scores[i] = (localSum - mean*weightSum) / denominator
+		}
+	}
+	return scores
+}
+
+func classifyLISA(z, lag, pValue float64) string {
+	if pValue > spatialSignificant {
+		return "TIDAK SIGNIFIKAN"
+	}
+
+	switch {
+	case z > 0 && lag > 0:
+		return "HIGH-HIGH"
+	case z < 0 && lag < 0:
+		return "LOW-LOW"
+	case z > 0:
+		return "HIGH-LOW"
+	}
+	return "LOW-HIGH"
+}
+
+func classifyHotspot(giStar float64) string {
+	if giStar >= 2.58 {
+		return "HOTSPOT 99%"
+	} else if giStar >= 1.96 {
+		return "HOTSPOT 95%"
+	} else if giStar <= -2.58 {
+		return "COLDSPOT 99%"
+	} else if giStar <= -1.96 {
+		return "COLDSPOT 95%"
+	}
+	return "TIDAK SIGNIFIKAN"
+}
+
+func standardizeDeviations(values []float64) []float64 {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	z := make([]float64, len(values))
+	for i, v := range values {
+		z[i] = v - mean
+	}
+	return z
+}
+
+func standardDeviation(z []float64) float64 {
+	sumSquares := 0.0
+	for _, v := range z {
+		sumSquares += v * v
+	}
+	sd := math.Sqrt(sumSquares / float64(len(z)))
+	if sd == 0 {
+		return 1
+	}
+	return sd
+}
+
+func spatialLag(z []float64, neighbors []int) float64 {
+	if len(neighbors) == 0 {
+		return 0
+	}
+	lag := 0.0
+	for _, j := range neighbors {
+		lag += z[j]
+	}
+	return lag / float64(len(neighbors))
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371.0
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func writeSpatialSheet(f *excelize.File, analysis *SpatialAnalysis) {
+	if analysis == nil {
+		return
+	}
+
+	sheet := tr("Autokorelasi_Spasial")
+	f.NewSheet(sheet)
+
+	f.SetCellValue(sheet, "A1", tr("GLOBAL MORAN'S I (KABUPATEN)"))
+	globalHeaders := []string{"Variabel", "Moran's I", "E[I]", "Z-Score", "P-Value", "Jumlah Kabupaten", "Bobot"}
+	for i, header := range globalHeaders {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 2)
+		f.SetCellValue(sheet, cell, tr(header))
+	}
+	for i, moran := range analysis.Global {
+		row := i + 3
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), tr(moran.Variable))
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), fmt.Sprintf("%.4f", moran.MoranI))
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), fmt.Sprintf("%.4f", moran.Expected))
+		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), fmt.Sprintf("%.2f", moran.ZScore))
+		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), fmt.Sprintf("%.4f", moran.PValue))
+		f.SetCellValue(sheet, fmt.Sprintf("F%d", row), moran.Units)
+		f.SetCellValue(sheet, fmt.Sprintf("G%d", row), moran.Weighting)
+	}
+
+	startRow := len(analysis.Global) + 5
+	headers := []string{"Region ID", "Kabupaten", "Provinsi", "{{Metric}} 2022 ({{unit}})", "Pertumbuhan ({{unit}})",
+		"LISA {{Metric}}", "P-Value {{Metric}}", "Klaster {{Metric}}", "Gi* {{Metric}}", "Hotspot {{Metric}}",
+		"LISA Pertumbuhan", "P-Value Pertumbuhan", "Klaster Pertumbuhan", "Gi* Pertumbuhan", "Hotspot Pertumbuhan"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, startRow)
+		f.SetCellValue(sheet, cell, tr(header))
+		f.SetColWidth(sheet, cell, cell, 18)
+	}
+
+	for i, regency := range analysis.Regencies {
+		values := []interface{}{
+			regency.RegionID, regency.Region, regency.Province,
+			formatNumber(regency.Area2022), formatNumber(regency.Growth),
+			fmt.Sprintf("%.3f", regency.AreaLocalI), fmt.Sprintf("%.3f", regency.AreaPValue),
+			tr(regency.AreaCluster), fmt.Sprintf("%.2f", regency.AreaGiStar), tr(regency.AreaHotspot),
+			fmt.Sprintf("%.3f", regency.GrowthLocalI), fmt.Sprintf("%.3f", regency.GrowthPValue),
+			tr(regency.GrowthCluster), fmt.Sprintf("%.2f", regency.GrowthGiStar), tr(regency.GrowthHotspot),
+		}
+		for j, value := range values {
+			cell, _ := excelize.CoordinatesToCellName(j+1, startRow+i+1)
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+}
+
+func createMoranScatterPlot(analysis *SpatialAnalysis) error {
+	if analysis == nil {
+		return nil
+	}
+
+	p := plot.New()
+	p.Title.Text = tr("MORAN SCATTERPLOT PERTUMBUHAN KABUPATEN 2003-2022")
+	p.Title.TextStyle.Font.Size = vg.Points(16)
+	p.X.Label.Text = tr("Pertumbuhan Terstandar (z)")
+	p.Y.Label.Text = tr("Lag Spasial Pertumbuhan (Wz)")
+
+	clusterColors := map[string]color.RGBA{
+		"HIGH-HIGH":        chartTheme.High,
+		"LOW-LOW":          chartTheme.Low,
+		"HIGH-LOW":         chartTheme.HighLight,
+		"LOW-HIGH":         chartTheme.LowLight,
+		"TIDAK SIGNIFIKAN": {R: 190, G: 190, B: 190, A: 255},
+	}
+
+	grouped := make(map[string]plotter.XYs)
+	var allPoints, labelPoints plotter.XYs
+	var labels []string
+	var priority []float64
+	for _, regency := range analysis.Regencies {
+		point := plotter.XY{X: regency.GrowthZ, Y: regency.GrowthLag}
+		grouped[regency.GrowthCluster] = append(grouped[regency.GrowthCluster], point)
+		allPoints = append(allPoints, point)
+		if regency.GrowthCluster == "HIGH-HIGH" || regency.GrowthCluster == "HIGH-LOW" {
+			labelPoints = append(labelPoints, point)
+			labels = append(labels, regency.Region)
+			priority = append(priority, math.Abs(regency.GrowthZ))
+		}
+	}
+
+	for _, cluster := range []string{"TIDAK SIGNIFIKAN", "LOW-LOW", "LOW-HIGH", "HIGH-LOW", "HIGH-HIGH"} {
+		if len(grouped[cluster]) == 0 {
+			continue
+		}
+		scatter, err := plotter.NewScatter(grouped[cluster])
+		if err != nil {
+			return err
+		}
+		scatter.GlyphStyle.Color = clusterColors[cluster]
+		scatter.GlyphStyle.Radius = vg.Points(4)
+		scatter.GlyphStyle.Shape = draw.CircleGlyph{}
+		p.Add(scatter)
+		p.Legend.Add(tr(cluster), scatter)
+	}
+
+	if len(labelPoints) > 0 {
+		labelPlot, err := newLabelLayer(labelPoints, labels, priority)
+		if err != nil {
+			return err
+		}
+		labelPlot.Obstacles = allPoints
+		p.Add(labelPlot)
+	}
+
+	p.Add(plotter.NewGrid())
+	p.Legend.Top = true
+	p.Legend.Left = true
+
+	return saveChart(p, 16*vg.Inch, 12*vg.Inch, "moran_scatter_kabupaten")
+}
+
+func buildSpatialReport(analysis *SpatialAnalysis) string {
+	if analysis == nil {
+		return ""
+	}
+
+	report := tr("\n### 🗺️ AUTOKORELASI SPASIAL & HOTSPOT KABUPATEN\n\n")
+	report += tr("| Variabel | Moran's I | Z-Score | P-Value | Interpretasi |\n")
+	report += "|----------|-----------|---------|---------|--------------|\n"
+	for _, moran := range analysis.Global {
+		interpretation := tr("acak secara spasial")
+		if moran.PValue < spatialSignificant && moran.MoranI > moran.Expected {
+			interpretation = tr("mengelompok secara signifikan")
+		} else if moran.PValue < spatialSignificant {
+			interpretation = tr("menyebar secara signifikan")
+		}
+		report += fmt.Sprintf("| %s | %.3f | %.2f | %.4f | %s |\n",
+			tr(moran.Variable), moran.MoranI, moran.ZScore, moran.PValue, interpretation)
+	}
+
+	clusters := make(map[string][]string)
+	for _, regency := range analysis.Regencies {
+		clusters[regency.GrowthCluster] = append(clusters[regency.GrowthCluster],
+			fmt.Sprintf("%s (%s)", regency.Region, getShortProvinceName(regency.Province)))
+	}
+
+	report += tr("\n**Klaster LISA pertumbuhan 2003-2022:**\n")
+	for _, cluster := range []string{"HIGH-HIGH", "LOW-LOW", "HIGH-LOW", "LOW-HIGH"} {
+		report += trf("- **%s** (%d kabupaten)", tr(cluster), len(clusters[cluster]))
+		if len(clusters[cluster]) > 0 {
+			report += ": " + joinLimited(clusters[cluster], 10)
+		}
+		report += "\n"
+	}
+
+	return report
+}
+
+func joinLimited(values []string, limit int) string {
+	result := ""
+	for i, value := range values {
+		if i == limit {
+			result += trf(", dan %d lainnya", len(values)-limit)
+			break
+		}
+		if i > 0 {
+			result += ", "
+		}
+		result += value
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Nilai acuan dihitung dengan matriks bobot penuh (row-standardized) dan
+// varians Moran's I di bawah asumsi normalitas.
+func TestCalculateGlobalMoran(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []float64
+		neighbors [][]int
+		moranI    float64
+		expected  float64
+		zScore    float64
+	}{
+		{"jalur simetris", []float64{1, 2, 3, 4}, [][]int{{1}, {0, 2}, {1, 3}, {2}},
+			0.4, -1.0 / 3, 1.7670825235854977},
+		// Tetangga tidak simetris seperti k tetangga terdekat.
+		{"tetangga asimetris", []float64{5, 1, 4, 2, 8}, [][]int{{1, 2}, {0}, {0, 3}, {2}, {3, 0}},
+			-0.21666666666666667, -0.25, 0.09428090415820631},
+	}
+	for _, test := range tests {
+		got := calculateGlobalMoran("area", test.values, test.neighbors, "uji")
+		if math.Abs(got.MoranI-test.moranI) > 1e-12 || math.Abs(got.Expected-test.expected) > 1e-12 ||
+			math.Abs(got.ZScore-test.zScore) > 1e-9 {
+			t.Errorf("%s: I=%.12f E=%.12f z=%.12f, seharusnya I=%.12f E=%.12f z=%.12f", test.name,
+				got.MoranI, got.Expected, got.ZScore, test.moranI, test.expected, test.zScore)
+		}
+		if want := 2 * (1 - normalCDF(math.Abs(test.zScore))); math.Abs(got.PValue-want) > 1e-9 {
+			t.Errorf("%s: p = %.6f, seharusnya %.6f", test.name, got.PValue, want)
+		}
+	}
+}
+
+func TestCalculateGlobalMoranConstant(t *testing.T) {
+	got := calculateGlobalMoran("area", []float64{3, 3, 3}, [][]int{{1}, {0, 2}, {1}}, "uji")
+	if got.MoranI != 0 || got.PValue != 1 {
+		t.Errorf("nilai konstan seharusnya I=0 dan p=1, didapat I=%v p=%v", got.MoranI, got.PValue)
+	}
+}
+
+func TestCalculateLocalMoran(t *testing.T) {
+	values := []float64{1, 2, 3, 4}
+	neighbors := [][]int{{1}, {0, 2}, {1, 3}, {2}}
+	localI, pValues := calculateLocalMoran(values, neighbors, rand.New(rand.NewSource(spatialSeed)))
+	want := []float64{0.6, 0.2, 0.2, 0.6}
+	for i := range want {
+		if math.Abs(localI[i]-want[i]) > 1e-12 {
+			t.Errorf("I lokal %d = %.12f, seharusnya %.12f", i, localI[i], want[i])
+		}
+		if pValues[i] <= 0 || pValues[i] > 1 {
+			t.Errorf("p lokal %d = %v di luar (0, 1]", i, pValues[i])
+		}
+	}
+
+	_, repeated := calculateLocalMoran(values, neighbors, rand.New(rand.NewSource(spatialSeed)))
+	for i := range repeated {
+		if repeated[i] != pValues[i] {
+			t.Errorf("p lokal %d tidak deterministik untuk seed yang sama: %v vs %v", i, repeated[i], pValues[i])
+		}
+	}
+}
+
+func TestCalculateGetisOrdGiStar(t *testing.T) {
+	got := calculateGetisOrdGiStar([]float64{1, 2, 3, 4}, [][]int{{1}, {0, 2}, {1, 3}, {2}})
+	want := []float64{-1.5491933384829668, -1.3416407864998738, 1.3416407864998738, 1.5491933384829668}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Errorf("Gi* %d = %.12f, seharusnya %.12f", i, got[i], want[i])
+		}
+	}
+}
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"context"
 	"encoding/csv"
+	"flag"
 	"fmt"
 	"image/color"
+	"io"
 	"log"
 	"math"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -19,13 +26,23 @@ import (
 	"gonum.org/v1/plot/vg/draw"
 )
 
+const (
+	dataFile            = "spatial-metrics-indonesia-palm-oil-oil_palm_ha_kabupaten.csv"
+	provinceExcelFile   = "model_provinsi_2003_2022.xlsx"
+	strategicReportFile = "rekomendasi_strategis_provinsi_20tahun.md"
+)
+
+// progressOutput menerima semua pesan progres. Mode query mengarahkannya ke
+// stderr agar stdout hanya berisi hasil kueri dan bisa langsung di-pipe.
+var progressOutput io.Writer = os.Stdout
+
 type RawPalmOilData struct {
 	Year           int
 	Region         string
 	RegionID       string
 	ParentRegion   string
 	ParentRegionID string
-	PlantedArea    float64
+	Values         []float64
 }
 
 type ProvinceModel struct {
@@ -49,6 +66,53 @@ type ProvinceModel struct {
 	YearlyData           map[int]float64
 	GrowthPhases         []GrowthPhase
 	DominantPeriod       string
+
+	EfficiencyBreakdown      []ScoreContribution
+	CompetitivenessBreakdown []ScoreContribution
+	Ranks                    RankHistory
+	MarketShareHistory       map[int]float64
+	Yield                    *ProvinceYield
+	Deforestation            *ProvinceDeforestation
+}
+
+type CategoryThresholds struct {
+	PrimeMinArea      float64
+	PrimeMinGrowth    float64
+	GrowthMinGrowth   float64
+	EmergingMaxArea   float64
+	EmergingMinGrowth float64
+	StableMinArea     float64
+	StableMinGrowth   float64
+	StableMaxGrowth   float64
+	MatureMinArea     float64
+	MatureMaxGrowth   float64
+}
+
+type RiskThresholds struct {
+	HighGrowth     float64
+	LowStability   float64
+	LowMarketShare float64
+	LowShareGrowth float64
+}
+
+var defaultCategoryThresholds = CategoryThresholds{
+	PrimeMinArea:      1000000,
+	PrimeMinGrowth:    100,
+	GrowthMinGrowth:   200,
+	EmergingMaxArea:   500000,
+	EmergingMinGrowth: 300,
+	StableMinArea:     500000,
+	StableMinGrowth:   50,
+	StableMaxGrowth:   150,
+	MatureMinArea:     500000,
+	MatureMaxGrowth:   50,
+}
+
+var defaultRiskThresholds = RiskThresholds{
+	HighGrowth:     500,
+	LowStability:   4,
+	LowMarketShare: 1,
+	LowShareGrowth: 50,
 }
 
 type GrowthPhase struct {
@@ -77,49 +141,374 @@ type DecadalAnalysis struct {
 }
 
 func main() {
-	fmt.Println("🌴 MODEL ANALISIS PROVINSI KELAPA SAWIT INDONESIA 2003-2022")
-	fmt.Println("Memproses data 20 tahun...")
-
-	rawData := readCSVData()
-	provinceModels := buildProvinceModels(rawData)
-	nationalTrends := analyzeNationalTrends(rawData)
-	decadalAnalysis := analyzeDecadalTrends(rawData, provinceModels)
-
-	createProvinceAnalysisExcel(provinceModels, nationalTrends, decadalAnalysis)
-	createProvinceCharts(provinceModels, nationalTrends)
-	createStrategicReport(provinceModels, nationalTrends, decadalAnalysis)
-
-	fmt.Println("\n✅ PEMODELAN PROVINSI 2003-2022 SELESAI!")
-	fmt.Println("📁 File Output:")
-	fmt.Println("   - model_provinsi_2003_2022.xlsx (Analisis detail per provinsi)")
-	fmt.Println("   - peta_heatmap_provinsi_20tahun.png")
-	fmt.Println("   - trend_pertumbuhan_provinsi_20tahun.png")
-	fmt.Println("   - proyeksi_2030.png")
-	fmt.Println("   - matriks_investasi_provinsi_20tahun.png")
-	fmt.Println("   - rekomendasi_strategis_provinsi_20tahun.md")
+	sensitivityMode := flag.Bool("sensitivity", false, "jalankan analisis sensitivitas bobot skor dan ambang klasifikasi")
+	monteCarloCorrelated := flag.Bool("mc-correlated", true, "simulasi Monte Carlo mengundi tahun historis yang sama untuk semua provinsi (false: independen per provinsi)")
+	monteCarloThresholds := flag.String("mc-thresholds", defaultMonteCarloThresholds, "ambang total nasional Monte Carlo dipisah koma, dalam satuan metrik utama")
+	monteCarloProvinceThreshold := flag.Float64("mc-province-threshold", defaultMonteCarloProvinceThreshold, "ambang area provinsi untuk peluang Monte Carlo, dalam satuan metrik utama")
+	shareFrom := flag.Int("share-from", 2003, "tahun awal tabel perubahan pangsa pasar")
+	shareTo := flag.Int("share-to", 2022, "tahun akhir tabel perubahan pangsa pasar")
+	templateDir := flag.String("template-dir", "", "direktori template laporan yang menggantikan template bawaan (kosong: hanya template bawaan)")
+	lang := flag.String("lang", defaultLanguage, "bahasa keluaran Excel, grafik dan laporan: id atau en")
+	chartFormat := flag.String("chart-format", "", "format grafik dipisah koma: png, svg, pdf, eps (default dari grafik.json atau png; tanpa png, PNG untuk laporan PDF ditulis ke direktori cache atau sementara)")
+	chartPreset := flag.String("chart-preset", "", "preset ukuran grafik: default, slide, print atau preset dari grafik.json")
+	chartDPI := flag.Int("chart-dpi", 0, "DPI grafik PNG (menimpa DPI preset)")
+	chartTheme := flag.String("chart-theme", "", "tema warna grafik: klasik, okabe-ito, viridis")
+	chartDir := flag.String("chart-dir", "", "direktori keluaran grafik")
+	labelMode := flag.String("label-mode", "", "label scatter plot: semua, top (N terbesar) atau outlier")
+	labelTop := flag.Int("label-top", 0, "jumlah label untuk -label-mode top")
+	compareTolerance := flag.Float64("compare-tolerance", defaultRevisionTolerance, "selisih area minimum (ha) yang dihitung sebagai revisi pada mode compare")
+	panelProvince := flag.String("panel-province", "", "buat grid panel per kabupaten untuk provinsi ini (kosong: per provinsi)")
+	panelYAxis := flag.String("panel-y", "", "sumbu Y grid panel: bersama atau bebas")
+	cacheDir := flag.String("cache-dir", defaultCacheDir, "direktori cache build (kosong: cache dimatikan)")
+	rebuild := flag.Bool("rebuild", false, "abaikan cache dan buat ulang semua keluaran")
+	jobs := flag.Int("jobs", runtime.NumCPU(), "jumlah tahap pipeline dan worker yang berjalan paralel")
+	queryFormat := flag.String("format", "tabel", "format hasil mode query: tabel, csv atau json")
+	metricNames := flag.String("metric", "", "metrik dari metrik.json dipisah koma; yang pertama dianalisis penuh, sisanya dibandingkan berdampingan")
+	flag.Parse()
+
+	setOutputLanguage(*lang)
+	setActiveMetrics(selectMetrics(loadMetricCatalog(), *metricNames))
+	setChartConfig(loadChartConfig(*chartFormat, *chartPreset, *chartTheme, *chartDir, *chartDPI, *labelMode, *labelTop, *panelYAxis))
+
+	if *shareFrom < 2003 || *shareTo > 2022 || *shareFrom >= *shareTo {
+		log.Fatalf("Rentang pangsa pasar tidak valid: %d-%d (harus 2003-2022)", *shareFrom, *shareTo)
+	}
+	if *jobs < 1 {
+		log.Fatalf("Jumlah -jobs tidak valid: %d (minimal 1)", *jobs)
+	}
+	nationalThresholds, err := parseMonteCarloThresholds(*monteCarloThresholds)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *monteCarloProvinceThreshold <= 0 {
+		log.Fatalf("Ambang provinsi Monte Carlo tidak valid: %v (harus positif)", *monteCarloProvinceThreshold)
+	}
+	monteCarlo := MonteCarloSettings{
+		Correlated:         *monteCarloCorrelated,
+		NationalThresholds: nationalThresholds,
+		ProvinceThreshold:  *monteCarloProvinceThreshold,
+	}
+	if !slices.Contains(queryFormats, *queryFormat) {
+		log.Fatalf("Format query tidak valid: %q (gunakan %s)", *queryFormat, strings.Join(queryFormats, ", "))
+	}
+	pipelineParallelism = *jobs
+	buildCache = openBuildCache(*cacheDir, *rebuild)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if flag.Arg(0) == "compare" {
+		if flag.NArg() != 3 {
+			log.Fatal("Penggunaan: tet [flag] compare <csv-lama> <csv-baru>")
+		}
+		if err := runVintageComparison(ctx, flag.Arg(1), flag.Arg(2), *compareTolerance); err != nil {
+			log.Fatalf("❌ Perbandingan gagal: %v", err)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "query" {
+		if flag.NArg() == 1 {
+			printQueryHelp(os.Stdout)
+			return
+		}
+		progressOutput = os.Stderr
+		if err := runQuery(ctx, strings.Join(flag.Args()[1:], " "), *queryFormat, os.Stdout); err != nil {
+			log.Fatalf("❌ Kueri gagal: %v", err)
+		}
+		return
+	}
+
+	fmt.Fprintln(progressOutput, "🌴 MODEL ANALISIS PROVINSI KELAPA SAWIT INDONESIA 2003-2022")
+	fmt.Fprintln(progressOutput, "Memproses data 20 tahun...")
+
+	rawData, dataHash, err := loadRawData(dataFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	scoring := loadScoringConfig()
+	scenarioConfig := loadScenarioConfig()
+	templates := hashDirectory(*templateDir)
+	productionHash, _ := hashFile(productionFile)
+	landHash, _ := hashFile(landAreaFile)
+	forestHash, _ := hashFile(forestLossFile)
+	certificationHash, _ := hashFile(certificationFile)
+	adjacencyHash, _ := hashFile(adjacencyFile)
+	centroidHash, _ := hashFile(centroidFile)
+	buildCache.Inputs = buildCache.key(dataHash, productionHash, landHash, forestHash, certificationHash, adjacencyHash, centroidHash, activeMetrics, scoring, scenarioConfig, *sensitivityMode, monteCarlo, outputLanguage)
+	runKey := buildCache.key(buildCache.Inputs, *shareFrom, *shareTo, chartSettings, *panelProvince, templates)
+	if outputs, ok := buildCache.upToDate(runKey); ok {
+		fmt.Fprintln(progressOutput, "\n✅ Semua keluaran sudah mutakhir, tidak ada yang dibuat ulang (pakai -rebuild untuk memaksa)")
+		fmt.Fprintln(progressOutput, "📁 File Output:")
+		for _, path := range outputs {
+			// PNG internal laporan PDF di direktori cache bukan keluaran.
+			if filepath.Dir(path) != filepath.Join(buildCache.Dir, cacheChartDir) {
+				fmt.Fprintf(progressOutput, "   - %s\n", path)
+			}
+		}
+		return
+	}
+
+	options := modelOptions{
+		ShareFrom:     *shareFrom,
+		ShareTo:       *shareTo,
+		Sensitivity:   *sensitivityMode,
+		MonteCarlo:    monteCarlo,
+		TemplateDir:   *templateDir,
+		Templates:     templates,
+		PanelProvince: *panelProvince,
+	}
+	cleanupReportImages, err := prepareReportImages()
+	if err != nil {
+		log.Fatalf("❌ Direktori gambar laporan tidak bisa dibuat: %v", err)
+	}
+	err = runModelPipeline(ctx, rawData, scoring, scenarioConfig, options)
+	cleanupReportImages()
+	if err != nil {
+		log.Fatalf("❌ Pemodelan gagal: %v", err)
+	}
+	buildCache.finish(runKey)
+
+	fmt.Fprintln(progressOutput, "\n✅ PEMODELAN PROVINSI 2003-2022 SELESAI!")
+	fmt.Fprintln(progressOutput, "📁 File Output:")
+	fmt.Fprintln(progressOutput, "   - model_provinsi_2003_2022.xlsx (Analisis detail per provinsi)")
+	printSavedCharts()
+	fmt.Fprintln(progressOutput, "   - rekomendasi_strategis_provinsi_20tahun.md")
+	fmt.Fprintln(progressOutput, "   - rekomendasi_strategis_provinsi_20tahun.pdf")
+	fmt.Fprintln(progressOutput, "   - profiles/ (profil per provinsi: Markdown, HTML, PDF + index)")
+}
+
+// modelOptions adalah flag yang memengaruhi tahap-tahap pemodelan.
+type modelOptions struct {
+	ShareFrom     int
+	ShareTo       int
+	Sensitivity   bool
+	MonteCarlo    MonteCarloSettings
+	TemplateDir   string
+	Templates     string
+	PanelProvince string
+}
+
+// runModelPipeline menyusun graf tahap pemodelan: kubus data dibangun sekali,
+// model provinsi dan analisis yang hanya membaca kubus berjalan bersamaan,
+// analisis turunan menunggu model, lalu Excel, setiap grafik, grid panel dan
+// profil berjalan paralel begitu inputnya siap. Laporan menunggu semua
+// grafik karena PDF menyisipkan file grafik.
+func runModelPipeline(ctx context.Context, rawData []RawPalmOilData, scoring ScoringConfig, scenarioConfig ScenarioConfig, options modelOptions) error {
+	var (
+		cube          *DataCube
+		models        []ProvinceModel
+		trends        []NationalTrend
+		decades       []DecadalAnalysis
+		concentration []ConcentrationMetrics
+		spatial       *SpatialAnalysis
+		scenarios     []ScenarioResult
+		monteCarlo    *MonteCarloResult
+		sensitivity   *SensitivityAnalysis
+		rankMobility  RankMobility
+		shareChanges  []ShareChange
+		metrics       []MetricSummary
+		production    *ProductionData
+		regencyYields []RegencyYield
+		landUse       *LandUseAnalysis
+		forest        *DeforestationAnalysis
+		certification *CertificationAnalysis
+	)
+
+	analyses := []pipelineStage{
+		{Name: "kubus", Run: func(context.Context) error {
+			cube = newDataCube(rawData, 0)
+			return nil
+		}},
+		{Name: "produksi", Run: func(context.Context) (err error) {
+			production, err = loadProductionData()
+			return err
+		}},
+		{Name: "metrik", Needs: []string{"kubus"}, Run: func(context.Context) (err error) {
+			metrics, err = compareMetrics(rawData, cube, 2003, 2022)
+			return err
+		}},
+		{Name: "hutan", Needs: []string{"kubus"}, Run: func(context.Context) error {
+			forestLoss, err := loadForestLoss()
+			if err != nil {
+				return err
+			}
+			forest, err = analyzeDeforestation(cube, forestLoss)
+			return err
+		}},
+		{Name: "model", Needs: []string{"kubus", "produksi", "hutan"}, Run: func(ctx context.Context) (err error) {
+			models, err = buildProvinceModels(ctx, cube, production, forest, scoring)
+			return err
+		}},
+		{Name: "produktivitas-kabupaten", Needs: []string{"kubus", "produksi"}, Run: func(context.Context) error {
+			regencyYields = analyzeRegencyYields(cube, production)
+			return nil
+		}},
+		{Name: "lahan", Needs: []string{"kubus"}, Run: func(context.Context) error {
+			table, err := loadLandAreas()
+			if err != nil {
+				return err
+			}
+			landUse, err = analyzeLandUse(cube, table)
+			return err
+		}},
+		{Name: "tren-nasional", Needs: []string{"kubus"}, Run: func(context.Context) error {
+			trends = analyzeNationalTrends(cube)
+			return nil
+		}},
+		{Name: "konsentrasi", Needs: []string{"kubus"}, Run: func(context.Context) error {
+			concentration = analyzeMarketConcentration(cube)
+			return nil
+		}},
+		{Name: "spasial", Needs: []string{"kubus"}, Run: func(context.Context) (err error) {
+			spatial, err = analyzeSpatialAutocorrelation(cube)
+			return err
+		}},
+		{Name: "dekade", Needs: []string{"model"}, Run: func(context.Context) error {
+			decades = analyzeDecadalTrends(cube, models)
+			return nil
+		}},
+		{Name: "skenario", Needs: []string{"model"}, Run: func(context.Context) (err error) {
+			scenarios, err = runScenarios(models, scenarioConfig)
+			return err
+		}},
+		{Name: "monte-carlo", Needs: []string{"model"}, Run: func(ctx context.Context) (err error) {
+			monteCarlo, err = runMonteCarloSimulation(ctx, models, options.MonteCarlo)
+			return err
+		}},
+		{Name: "sensitivitas", Needs: []string{"model"}, Run: func(context.Context) error {
+			if options.Sensitivity {
+				sensitivity = analyzeSensitivity(models, scoring)
+			}
+			return nil
+		}},
+		{Name: "sertifikasi", Needs: []string{"model"}, Run: func(context.Context) error {
+			data, err := loadCertificationData()
+			if err != nil {
+				return err
+			}
+			certification = analyzeCertification(models, data)
+			return nil
+		}},
+		{Name: "mobilitas-peringkat", Needs: []string{"model"}, Run: func(context.Context) error {
+			rankMobility = analyzeRankMobility(models)
+			return nil
+		}},
+		{Name: "pangsa-pasar", Needs: []string{"model"}, Run: func(context.Context) error {
+			shareChanges = calculateShareChanges(models, options.ShareFrom, options.ShareTo)
+			return nil
+		}},
+	}
+	var analysisNames []string
+	for _, stage := range analyses {
+		analysisNames = append(analysisNames, stage.Name)
+	}
+
+	chart := func(name string, needs []string, create func() error) pipelineStage {
+		return pipelineStage{Name: "grafik-" + name, Needs: needs, Run: func(context.Context) error { return create() }}
+	}
+	charts := []pipelineStage{
+		chart("heatmap", []string{"model"}, func() error { return createProvinceHeatmap20Years(models) }),
+		chart("pertumbuhan", []string{"model"}, func() error { return createGrowthTrendChart20Years(models) }),
+		chart("proyeksi", []string{"model"}, func() error { return createProjectionChart2030(models) }),
+		chart("investasi", []string{"model"}, func() error { return createInvestmentScatterPlot20Years(models) }),
+		chart("tren-nasional", []string{"tren-nasional"}, func() error { return createNationalTrendChart(trends) }),
+		chart("lorenz", []string{"konsentrasi"}, func() error { return createLorenzChart(concentration) }),
+		chart("moran", []string{"spasial"}, func() error { return createMoranScatterPlot(spatial) }),
+		chart("skenario-nasional", []string{"tren-nasional", "skenario"}, func() error { return createNationalScenarioChart(trends, scenarios) }),
+		chart("skenario-provinsi", []string{"model", "skenario"}, func() error { return createProvinceScenarioChart(models, scenarios) }),
+		chart("monte-carlo", []string{"tren-nasional", "monte-carlo"}, func() error { return createMonteCarloFanChart(trends, monteCarlo) }),
+		chart("tornado", []string{"sensitivitas"}, func() error { return createTornadoChart(sensitivity) }),
+		chart("bump", []string{"model"}, func() error { return createBumpChart(models) }),
+		chart("pangsa-pasar", []string{"model"}, func() error { return createMarketShareStackedChart(models) }),
+		chart("tren-hasil", []string{"model"}, func() error { return createYieldTrendChart(models) }),
+		chart("intensitas-lahan", []string{"lahan"}, func() error { return createLandIntensityMap(landUse) }),
+		chart("sertifikasi", []string{"model", "sertifikasi"}, func() error { return createCertificationChart(models, certification) }),
+	}
+	var chartNames []string
+	for _, stage := range charts {
+		chartNames = append(chartNames, stage.Name)
+	}
+
+	outputs := []pipelineStage{
+		{Name: "excel", Needs: analysisNames, Run: func(context.Context) error {
+			key := buildCache.key(buildCache.Inputs, options.ShareFrom, options.ShareTo)
+			return buildCache.build(provinceExcelFile, key, []string{provinceExcelFile}, func() error {
+				return createProvinceAnalysisExcel(models, trends, decades, concentration, spatial, scenarios, monteCarlo, scoring, sensitivity, rankMobility, shareChanges, metrics, regencyYields, landUse, forest, certification)
+			})
+		}},
+		{Name: "grid-trajektori", Needs: []string{"model"}, Run: func(ctx context.Context) error {
+			return createTrajectoryGrid(ctx, models, cube, options.PanelProvince)
+		}},
+		{Name: "laporan", Needs: append(slices.Clone(analysisNames), chartNames...), Run: func(context.Context) error {
+			key := buildCache.key(buildCache.Inputs, options.ShareFrom, options.ShareTo, options.Templates, chartSettings)
+			return buildCache.build(strategicReportFile, key, []string{strategicReportFile, strategicReportPDFFile}, func() error {
+				return createStrategicReport(models, trends, decades, concentration, spatial, scenarios, monteCarlo, scoring, sensitivity, rankMobility, shareChanges, metrics, regencyYields, landUse, forest, certification, options.TemplateDir)
+			})
+		}},
+		{Name: "profil", Needs: []string{"model"}, Run: func(ctx context.Context) error {
+			return createProvinceProfiles(ctx, models, cube, scoring, options.TemplateDir, options.Templates)
+		}},
+	}
+
+	return runPipeline(ctx, slices.Concat(analyses, charts, outputs))
 }
 
-func readCSVData() []RawPalmOilData {
-	file, err := os.Open("spatial-metrics-indonesia-palm-oil-oil_palm_ha_kabupaten.csv")
+// readCSVData membaca CSV berformat Trase. Kolom dicari lewat header
+// sehingga urutan kolom bebas; nilai setiap metrik aktif disimpan di
+// Values sesuai urutan activeMetrics.
+func readCSVData(path string, metrics []Metric) ([]RawPalmOilData, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		log.Fatal("Error membuka file CSV:", err)
+		return nil, fmt.Errorf("error membuka file CSV: %w", err)
 	}
 	defer file.Close()
 
 	reader := csv.NewReader(file)
 	records, err := reader.ReadAll()
 	if err != nil {
-		log.Fatal("Error membaca CSV:", err)
+		return nil, fmt.Errorf("error membaca CSV %s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV %s kosong", path)
 	}
 
-	var data []RawPalmOilData
-
-	for i, record := range records {
-		if i == 0 {
-			continue
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	column := func(name string) (int, error) {
+		if i, ok := columns[name]; ok {
+			return i, nil
+		}
+		return 0, fmt.Errorf("kolom %q tidak ada di header CSV %s", name, path)
+	}
+	var yearColumn, regionColumn, regionIDColumn, parentColumn, parentIDColumn int
+	for _, field := range []struct {
+		name   string
+		target *int
+	}{
+		{"year", &yearColumn},
+		{"region", &regionColumn},
+		{"region_trase_id", &regionIDColumn},
+		{"parent_region", &parentColumn},
+		{"parent_region_trase_id", &parentIDColumn},
+	} {
+		if *field.target, err = column(field.name); err != nil {
+			return nil, err
+		}
+	}
+	metricColumns := make([]int, len(metrics))
+	for i, metric := range metrics {
+		if metricColumns[i], err = column(metric.Column); err != nil {
+			return nil, fmt.Errorf("metrik %s: %w", metric.Name, err)
 		}
+	}
+
+	var data []RawPalmOilData
 
-		year, err := strconv.Atoi(record[0])
+	for _, record := range records[1:] {
+		year, err := strconv.Atoi(record[yearColumn])
 		if err != nil {
 			continue
 		}
@@ -128,70 +517,55 @@ func readCSVData() []RawPalmOilData {
 			continue
 		}
 
-		area, err := strconv.ParseFloat(record[7], 64)
-		if err != nil {
-			area = 0
+		values := make([]float64, len(metrics))
+		for i, index := range metricColumns {
+			value, err := strconv.ParseFloat(record[index], 64)
+			if err != nil {
+				value = 0
+			}
+			values[i] = value
 		}
 
 		rawData := RawPalmOilData{
 			Year:           year,
-			Region:         record[3],
-			RegionID:       record[4],
-			ParentRegion:   record[5],
-			ParentRegionID: record[6],
-			PlantedArea:    area,
+			Region:         record[regionColumn],
+			RegionID:       record[regionIDColumn],
+			ParentRegion:   record[parentColumn],
+			ParentRegionID: record[parentIDColumn],
+			Values:         values,
 		}
 
 		data = append(data, rawData)
 	}
 
-	fmt.Printf("📊 Data berhasil dibaca: %d records (2003-2022)\n", len(data))
-	return data
+	fmt.Fprintf(progressOutput, "📊 Data berhasil dibaca: %d records (2003-2022) dari %s\n", len(data), path)
+	return data, nil
 }
 
-func buildProvinceModels(rawData []RawPalmOilData) []ProvinceModel {
-	provinceMap := make(map[string]*ProvinceModel)
-	yearlyProvinceData := make(map[string]map[int]float64)
-
-	for _, data := range rawData {
-		province := data.ParentRegion
-		if province == "" {
-			continue
-		}
-
-		if provinceMap[province] == nil {
-			provinceMap[province] = &ProvinceModel{
-				Province:   province,
-				YearlyData: make(map[int]float64),
-			}
-		}
-
-		if yearlyProvinceData[province] == nil {
-			yearlyProvinceData[province] = make(map[int]float64)
-		}
-
-		yearlyProvinceData[province][data.Year] += data.PlantedArea
-		provinceMap[province].YearlyData[data.Year] += data.PlantedArea
+// buildProvinceModels membaca seri tahunan per provinsi dari kubus lalu
+// menghitung metrik tiap provinsi lewat worker pool; produktivitas, skor
+// komposit dan peringkat dihitung setelah semua provinsi selesai. production
+// dan forest boleh nil.
+func buildProvinceModels(ctx context.Context, cube *DataCube, production *ProductionData, forest *DeforestationAnalysis, scoring ScoringConfig) ([]ProvinceModel, error) {
+	series, err := cube.Series(LevelProvince, CubeFilter{})
+	if err != nil {
+		return nil, err
 	}
 
 	totalNational2022 := 0.0
-	for province := range provinceMap {
-		if yearlyProvinceData[province] != nil {
-			totalNational2022 += yearlyProvinceData[province][2022]
-		}
+	for _, province := range series {
+		totalNational2022 += province.Values[2022]
 	}
 
-	var models []ProvinceModel
-	for province, yearlyData := range yearlyProvinceData {
-		if yearlyData == nil {
-			continue
-		}
-
+	models := make([]ProvinceModel, len(series))
+	err = parallelFor(ctx, len(series), func(i int) error {
+		province, yearlyData := series[i].Key, series[i].Values
 		model := ProvinceModel{
 			Province:      province,
 			TotalArea2003: yearlyData[2003],
 			TotalArea2022: yearlyData[2022],
-			YearlyData:    yearlyProvinceData[province],
+			YearlyData:    yearlyData,
+			Deforestation: forest.province(province),
 		}
 
 		if model.TotalArea2003 > 0 {
@@ -212,19 +586,23 @@ func buildProvinceModels(rawData []RawPalmOilData) []ProvinceModel {
 
 		model.StabilityIndex = calculateStabilityIndex(yearlyData)
 
-		model.ProductionEfficiency = calculateEfficiency(model.TotalArea2022, model.GrowthRate20Years, model.StabilityIndex)
-
-		model.Competitiveness = calculateCompetitivenessScore(model)
-
-		model.InvestmentPotential = assessInvestmentPotential(model)
-
 		model.RiskLevel = assessRiskLevel(model)
 
 		model.Recommendations = generateProvinceRecommendations(model)
 
 		model.Projection2030 = calculateProjection2030(model)
 
-		models = append(models, model)
+		models[i] = model
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	applyProvinceYields(models, production)
+	applyCompositeScores(models, scoring)
+	for i := range models {
+		models[i].Recommendations = append(models[i].Recommendations, generateYieldRecommendations(models[i])...)
 	}
 
 	sort.Slice(models, func(i, j int) bool {
@@ -235,77 +613,41 @@ func buildProvinceModels(rawData []RawPalmOilData) []ProvinceModel {
 		models[i].Rank2022 = i + 1
 	}
 
-	fmt.Printf("🏛️  Model provinsi dibangun: %d provinsi (2003-2022)\n", len(models))
-	return models
-}
-
-func analyzeNationalTrends(rawData []RawPalmOilData) []NationalTrend {
-	yearlyData := make(map[int]*NationalTrend)
+	assignMarketShareHistory(models)
+	assignRankHistory(models)
 
-	for year := 2003; year <= 2022; year++ {
-		yearlyData[year] = &NationalTrend{
-			Year:         year,
-			TotalArea:    0,
-			GrowthRate:   0,
-			TopProvince:  "Unknown",
-			AnnualChange: 0,
-		}
-	}
-
-	for _, data := range rawData {
-		if yearlyData[data.Year] != nil {
-			yearlyData[data.Year].TotalArea += data.PlantedArea
-		}
-	}
+	fmt.Fprintf(progressOutput, "🏛️  Model provinsi dibangun: %d provinsi (2003-2022)\n", len(models))
+	return models, nil
+}
 
+func analyzeNationalTrends(cube *DataCube) []NationalTrend {
+	var trends []NationalTrend
 	for year := 2003; year <= 2022; year++ {
-		trend := yearlyData[year]
-		if trend == nil {
-			continue
+		trend := NationalTrend{
+			Year:        year,
+			TotalArea:   cube.NationalArea(year),
+			TopProvince: "Unknown",
 		}
 
-		provinceAreas := make(map[string]float64)
-		for _, data := range rawData {
-			if data.Year == year && data.ParentRegion != "" {
-				provinceAreas[data.ParentRegion] += data.PlantedArea
+		for province, area := range cube.ProvinceAreas(year) {
+			if area > trend.TopProvinceArea {
+				trend.TopProvince = province
+				trend.TopProvinceArea = area
 			}
 		}
 
-		var topProvince string
-		var topArea float64
-		for province, area := range provinceAreas {
-			if area > topArea {
-				topArea = area
-				topProvince = province
-			}
-		}
-
-		if topProvince != "" {
-			trend.TopProvince = topProvince
-			trend.TopProvinceArea = topArea
-		}
-
-		if year > 2003 {
-			prevYearData := yearlyData[year-1]
-			if prevYearData != nil && prevYearData.TotalArea > 0 {
-				growth := ((trend.TotalArea - prevYearData.TotalArea) / prevYearData.TotalArea) * 100
-				trend.GrowthRate = growth
-				trend.AnnualChange = trend.TotalArea - prevYearData.TotalArea
-			}
+		if previous := cube.NationalArea(year - 1); year > 2003 && previous > 0 {
+			trend.GrowthRate = ((trend.TotalArea - previous) / previous) * 100
+			trend.AnnualChange = trend.TotalArea - previous
 		}
-	}
 
-	var trends []NationalTrend
-	for year := 2003; year <= 2022; year++ {
-		if yearlyData[year] != nil {
-			trends = append(trends, *yearlyData[year])
-		}
+		trends = append(trends, trend)
 	}
 
 	return trends
 }
 
-func analyzeDecadalTrends(rawData []RawPalmOilData, models []ProvinceModel) []DecadalAnalysis {
+func analyzeDecadalTrends(cube *DataCube, models []ProvinceModel) []DecadalAnalysis {
 	decades := []struct {
 		name  string
 		start int
@@ -322,8 +664,8 @@ func analyzeDecadalTrends(rawData []RawPalmOilData, models []ProvinceModel) []De
 			Decade: decade.name,
 		}
 
-		startArea := calculateNationalAreaForYear(rawData, decade.start)
-		endArea := calculateNationalAreaForYear(rawData, decade.end)
+		startArea := cube.NationalArea(decade.start)
+		endArea := cube.NationalArea(decade.end)
 
 		if startArea > 0 {
 			decadeAnalysis.TotalGrowth = ((endArea - startArea) / startArea) * 100
@@ -342,91 +684,109 @@ func analyzeDecadalTrends(rawData []RawPalmOilData, models []ProvinceModel) []De
 	return analysis
 }
 
-func createProvinceAnalysisExcel(models []ProvinceModel, trends []NationalTrend, decadalAnalysis []DecadalAnalysis) {
+func createProvinceAnalysisExcel(models []ProvinceModel, trends []NationalTrend, decadalAnalysis []DecadalAnalysis, concentration []ConcentrationMetrics, spatial *SpatialAnalysis, scenarios []ScenarioResult, monteCarlo *MonteCarloResult, scoring ScoringConfig, sensitivity *SensitivityAnalysis, rankMobility RankMobility, shareChanges []ShareChange, metrics []MetricSummary, regencyYields []RegencyYield, landUse *LandUseAnalysis, forest *DeforestationAnalysis, certification *CertificationAnalysis) error {
 	f := excelize.NewFile()
 
-	f.SetSheetName("Sheet1", "Dashboard_Provinsi_20Tahun")
+	dashboard := tr("Dashboard_Provinsi_20Tahun")
+	f.SetSheetName("Sheet1", dashboard)
 
-	headers := []string{"Rank", "Provinsi", "Area 2022 (ha)", "Area 2003 (ha)",
+	headers := []string{"Rank", "Provinsi", "{{Metric}} 2022 ({{unit}})", "{{Metric}} 2003 ({{unit}})",
 		"Growth Rate 20 Tahun (%)", "Market Share 2022 (%)", "Trend",
 		"Efisiensi Produksi", "Daya Saing", "Potensi Investasi", "Tingkat Risiko",
-		"Proyeksi 2030 (ha)", "Tahun Puncak", "Area Puncak (ha)", "Indeks Stabilitas",
+		"Proyeksi 2030 ({{unit}})", "Tahun Puncak", "{{Metric}} Puncak ({{unit}})", "Indeks Stabilitas",
 		"Periode Dominan", "Rekomendasi Utama"}
 
 	for i, header := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", cell, header)
-		f.SetColWidth("Dashboard_Provinsi_20Tahun", cell, cell, 18)
+		f.SetCellValue(dashboard, cell, tr(header))
+		f.SetColWidth(dashboard, cell, cell, 18)
 	}
 
 	for i, model := range models {
 		row := i + 2
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("A%d", row), model.Rank2022)
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("B%d", row), model.Province)
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("C%d", row), formatNumber(model.TotalArea2022))
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("D%d", row), formatNumber(model.TotalArea2003))
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("E%d", row), fmt.Sprintf("%.1f%%", model.GrowthRate20Years))
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("F%d", row), fmt.Sprintf("%.1f%%", model.MarketShare2022))
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("G%d", row), model.Trend)
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("H%d", row), fmt.Sprintf("%.1f/10", model.ProductionEfficiency))
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("I%d", row), fmt.Sprintf("%.1f/10", model.Competitiveness))
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("J%d", row), model.InvestmentPotential)
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("K%d", row), model.RiskLevel)
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("L%d", row), formatNumber(model.Projection2030))
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("M%d", row), model.PeakYear)
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("N%d", row), formatNumber(model.PeakArea))
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("O%d", row), fmt.Sprintf("%.2f", model.StabilityIndex))
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("P%d", row), model.DominantPeriod)
-
-		mainRec := "Tidak tersedia"
+		f.SetCellValue(dashboard, fmt.Sprintf("A%d", row), model.Rank2022)
+		f.SetCellValue(dashboard, fmt.Sprintf("B%d", row), model.Province)
+		f.SetCellValue(dashboard, fmt.Sprintf("C%d", row), formatNumber(model.TotalArea2022))
+		f.SetCellValue(dashboard, fmt.Sprintf("D%d", row), formatNumber(model.TotalArea2003))
+		f.SetCellValue(dashboard, fmt.Sprintf("E%d", row), fmt.Sprintf("%.1f%%", model.GrowthRate20Years))
+		f.SetCellValue(dashboard, fmt.Sprintf("F%d", row), fmt.Sprintf("%.1f%%", model.MarketShare2022))
+		f.SetCellValue(dashboard, fmt.Sprintf("G%d", row), tr(model.Trend))
+		f.SetCellValue(dashboard, fmt.Sprintf("H%d", row), fmt.Sprintf("%.1f/10", model.ProductionEfficiency))
+		f.SetCellValue(dashboard, fmt.Sprintf("I%d", row), fmt.Sprintf("%.1f/10", model.Competitiveness))
+		f.SetCellValue(dashboard, fmt.Sprintf("J%d", row), tr(model.InvestmentPotential))
+		f.SetCellValue(dashboard, fmt.Sprintf("K%d", row), tr(model.RiskLevel))
+		f.SetCellValue(dashboard, fmt.Sprintf("L%d", row), formatNumber(model.Projection2030))
+		f.SetCellValue(dashboard, fmt.Sprintf("M%d", row), model.PeakYear)
+		f.SetCellValue(dashboard, fmt.Sprintf("N%d", row), formatNumber(model.PeakArea))
+		f.SetCellValue(dashboard, fmt.Sprintf("O%d", row), fmt.Sprintf("%.2f", model.StabilityIndex))
+		f.SetCellValue(dashboard, fmt.Sprintf("P%d", row), model.DominantPeriod)
+
+		mainRec := tr("Tidak tersedia")
 		if len(model.Recommendations) > 0 {
 			mainRec = model.Recommendations[0]
 		}
-		f.SetCellValue("Dashboard_Provinsi_20Tahun", fmt.Sprintf("Q%d", row), mainRec)
+		f.SetCellValue(dashboard, fmt.Sprintf("Q%d", row), mainRec)
 	}
 
-	f.NewSheet("Trend_Nasional_20Tahun")
+	trendSheet := tr("Trend_Nasional_20Tahun")
+	f.NewSheet(trendSheet)
 
-	trendHeaders := []string{"Tahun", "Total Area (ha)", "Pertumbuhan (%)",
-		"Provinsi Teratas", "Area Provinsi Teratas (ha)", "Perubahan Tahunan (ha)"}
+	trendHeaders := []string{"Tahun", "Total {{Metric}} ({{unit}})", "Pertumbuhan (%)",
+		"Provinsi Teratas", "{{Metric}} Provinsi Teratas ({{unit}})", "Perubahan Tahunan ({{unit}})"}
 
 	for i, header := range trendHeaders {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		f.SetCellValue("Trend_Nasional_20Tahun", cell, header)
-		f.SetColWidth("Trend_Nasional_20Tahun", cell, cell, 20)
+		f.SetCellValue(trendSheet, cell, tr(header))
+		f.SetColWidth(trendSheet, cell, cell, 20)
 	}
 
 	for i, trend := range trends {
 		row := i + 2
-		f.SetCellValue("Trend_Nasional_20Tahun", fmt.Sprintf("A%d", row), trend.Year)
-		f.SetCellValue("Trend_Nasional_20Tahun", fmt.Sprintf("B%d", row), formatNumber(trend.TotalArea))
-		f.SetCellValue("Trend_Nasional_20Tahun", fmt.Sprintf("C%d", row), fmt.Sprintf("%.1f%%", trend.GrowthRate))
-		f.SetCellValue("Trend_Nasional_20Tahun", fmt.Sprintf("D%d", row), trend.TopProvince)
-		f.SetCellValue("Trend_Nasional_20Tahun", fmt.Sprintf("E%d", row), formatNumber(trend.TopProvinceArea))
-		f.SetCellValue("Trend_Nasional_20Tahun", fmt.Sprintf("F%d", row), formatNumber(trend.AnnualChange))
+		f.SetCellValue(trendSheet, fmt.Sprintf("A%d", row), trend.Year)
+		f.SetCellValue(trendSheet, fmt.Sprintf("B%d", row), formatNumber(trend.TotalArea))
+		f.SetCellValue(trendSheet, fmt.Sprintf("C%d", row), fmt.Sprintf("%.1f%%", trend.GrowthRate))
+		f.SetCellValue(trendSheet, fmt.Sprintf("D%d", row), trend.TopProvince)
+		f.SetCellValue(trendSheet, fmt.Sprintf("E%d", row), formatNumber(trend.TopProvinceArea))
+		f.SetCellValue(trendSheet, fmt.Sprintf("F%d", row), formatNumber(trend.AnnualChange))
 	}
 
-	f.NewSheet("Analisis_Dekade")
+	decadeSheet := tr("Analisis_Dekade")
+	f.NewSheet(decadeSheet)
 
-	f.SetCellValue("Analisis_Dekade", "A1", "ANALISIS PER DEKADE 2003-2022")
-	f.SetCellValue("Analisis_Dekade", "A2", "Dekade")
-	f.SetCellValue("Analisis_Dekade", "B2", "Total Growth (%)")
-	f.SetCellValue("Analisis_Dekade", "C2", "Rata2 Tahunan (%)")
-	f.SetCellValue("Analisis_Dekade", "D2", "Provinsi Terdepan")
-	f.SetCellValue("Analisis_Dekade", "E2", "Region Emerging")
-	f.SetCellValue("Analisis_Dekade", "F2", "Event Penting")
+	f.SetCellValue(decadeSheet, "A1", tr("ANALISIS PER DEKADE 2003-2022"))
+	f.SetCellValue(decadeSheet, "A2", tr("Dekade"))
+	f.SetCellValue(decadeSheet, "B2", tr("Total Growth (%)"))
+	f.SetCellValue(decadeSheet, "C2", tr("Rata2 Tahunan (%)"))
+	f.SetCellValue(decadeSheet, "D2", tr("Provinsi Terdepan"))
+	f.SetCellValue(decadeSheet, "E2", tr("Region Emerging"))
+	f.SetCellValue(decadeSheet, "F2", tr("Event Penting"))
 
 	for i, analysis := range decadalAnalysis {
 		row := i + 3
-		f.SetCellValue("Analisis_Dekade", fmt.Sprintf("A%d", row), analysis.Decade)
-		f.SetCellValue("Analisis_Dekade", fmt.Sprintf("B%d", row), fmt.Sprintf("%.1f%%", analysis.TotalGrowth))
-		f.SetCellValue("Analisis_Dekade", fmt.Sprintf("C%d", row), fmt.Sprintf("%.1f%%", analysis.AverageAnnual))
-		f.SetCellValue("Analisis_Dekade", fmt.Sprintf("D%d", row), analysis.LeadingProvince)
-		f.SetCellValue("Analisis_Dekade", fmt.Sprintf("E%d", row), strings.Join(analysis.EmergingRegions, ", "))
-		f.SetCellValue("Analisis_Dekade", fmt.Sprintf("F%d", row), strings.Join(analysis.KeyEvents, "; "))
-	}
-
-	f.NewSheet("Kelompok_Provinsi_20Tahun")
+		f.SetCellValue(decadeSheet, fmt.Sprintf("A%d", row), analysis.Decade)
+		f.SetCellValue(decadeSheet, fmt.Sprintf("B%d", row), fmt.Sprintf("%.1f%%", analysis.TotalGrowth))
+		f.SetCellValue(decadeSheet, fmt.Sprintf("C%d", row), fmt.Sprintf("%.1f%%", analysis.AverageAnnual))
+		f.SetCellValue(decadeSheet, fmt.Sprintf("D%d", row), analysis.LeadingProvince)
+		f.SetCellValue(decadeSheet, fmt.Sprintf("E%d", row), strings.Join(analysis.EmergingRegions, ", "))
+		f.SetCellValue(decadeSheet, fmt.Sprintf("F%d", row), strings.Join(analysis.KeyEvents, "; "))
+	}
+
+	writeConcentrationSheet(f, concentration)
+	writeSpatialSheet(f, spatial)
+	writeScenarioSheets(f, models, scenarios)
+	writeMonteCarloSheets(f, monteCarlo)
+	writeScoreBreakdownSheet(f, models, scoring)
+	writeSensitivitySheets(f, sensitivity)
+	writeRankHistorySheets(f, models, rankMobility)
+	writeMarketShareSheets(f, models, shareChanges)
+	writeMetricSheet(f, metrics, 2003, 2022)
+	writeYieldSheets(f, models, regencyYields)
+	writeLandUseSheets(f, landUse)
+	writeDeforestationSheets(f, forest)
+	writeCertificationSheet(f, certification)
+
+	groupSheet := tr("Kelompok_Provinsi_20Tahun")
+	f.NewSheet(groupSheet)
 
 	primeProvinces := filterProvinces(models, "PRIME")
 	growthProvinces := filterProvinces(models, "GROWTH")
@@ -434,27 +794,28 @@ func createProvinceAnalysisExcel(models []ProvinceModel, trends []NationalTrend,
 	stableProvinces := filterProvinces(models, "STABLE")
 	matureProvinces := filterProvinces(models, "MATURE")
 
-	f.SetCellValue("Kelompok_Provinsi_20Tahun", "A1", "KELOMPOK PROVINSI BERDASARKAN POTENSI (2003-2022)")
-	f.SetCellValue("Kelompok_Provinsi_20Tahun", "A2", "PRIME (Area > 1M ha, Growth > 100%)")
-	writeProvinceGroup(f, primeProvinces, 3)
+	f.SetCellValue(groupSheet, "A1", tr("KELOMPOK PROVINSI BERDASARKAN POTENSI (2003-2022)"))
+	f.SetCellValue(groupSheet, "A2", tr("PRIME ({{Metric}} > 1M {{unit}}, Growth > 100%)"))
+	writeProvinceGroup(f, groupSheet, primeProvinces, 3)
 
 	startRow := len(primeProvinces) + 5
-	f.SetCellValue("Kelompok_Provinsi_20Tahun", fmt.Sprintf("A%d", startRow), "GROWTH (Growth > 200%)")
-	writeProvinceGroup(f, growthProvinces, startRow+1)
+	f.SetCellValue(groupSheet, fmt.Sprintf("A%d", startRow), tr("GROWTH (Growth > 200%)"))
+	writeProvinceGroup(f, groupSheet, growthProvinces, startRow+1)
 
 	startRow += len(growthProvinces) + 3
-	f.SetCellValue("Kelompok_Provinsi_20Tahun", fmt.Sprintf("A%d", startRow), "EMERGING (Area < 500k, Growth > 300%)")
-	writeProvinceGroup(f, emergingProvinces, startRow+1)
+	f.SetCellValue(groupSheet, fmt.Sprintf("A%d", startRow), tr("EMERGING ({{Metric}} < 500k, Growth > 300%)"))
+	writeProvinceGroup(f, groupSheet, emergingProvinces, startRow+1)
 
 	startRow += len(emergingProvinces) + 3
-	f.SetCellValue("Kelompok_Provinsi_20Tahun", fmt.Sprintf("A%d", startRow), "STABLE (Area > 500k, Growth 50-150%)")
-	writeProvinceGroup(f, stableProvinces, startRow+1)
+	f.SetCellValue(groupSheet, fmt.Sprintf("A%d", startRow), tr("STABLE ({{Metric}} > 500k, Growth 50-150%)"))
+	writeProvinceGroup(f, groupSheet, stableProvinces, startRow+1)
 
 	startRow += len(stableProvinces) + 3
-	f.SetCellValue("Kelompok_Provinsi_20Tahun", fmt.Sprintf("A%d", startRow), "MATURE (Area besar, Growth < 50%)")
-	writeProvinceGroup(f, matureProvinces, startRow+1)
+	f.SetCellValue(groupSheet, fmt.Sprintf("A%d", startRow), tr("MATURE ({{Metric}} besar, Growth < 50%)"))
+	writeProvinceGroup(f, groupSheet, matureProvinces, startRow+1)
 
-	f.NewSheet("Matriks_Strategi_20Tahun")
+	matrixSheet := tr("Matriks_Strategi_20Tahun")
+	f.NewSheet(matrixSheet)
 
 	strategicMatrix := [][]string{
 		{"Kategori", "Strategi Inti", "Target Provinsi", "Timeline", "Expected Impact"},
@@ -469,32 +830,30 @@ func createProvinceAnalysisExcel(models []ProvinceModel, trends []NationalTrend,
 	for i, row := range strategicMatrix {
 		for j, value := range row {
 			cell, _ := excelize.CoordinatesToCellName(j+1, i+1)
-			f.SetCellValue("Matriks_Strategi_20Tahun", cell, value)
-			f.SetColWidth("Matriks_Strategi_20Tahun", cell, cell, 20)
+			f.SetCellValue(matrixSheet, cell, tr(value))
+			f.SetColWidth(matrixSheet, cell, cell, 20)
 		}
 	}
-
-	if err := f.SaveAs("model_provinsi_2003_2022.xlsx"); err != nil {
-		log.Fatal("Error menyimpan Excel:", err)
+	if certification != nil {
+		f.SetCellValue(matrixSheet, "F1", tr("Progres"))
+		f.SetCellValue(matrixSheet, fmt.Sprintf("F%d", len(strategicMatrix)), certificationSummary(certification))
+		f.SetColWidth(matrixSheet, "F", "F", 60)
 	}
 
-	fmt.Printf("📈 File Excel berhasil dibuat: model_provinsi_2003_2022.xlsx (%d provinsi)\n", len(models))
-}
+	if err := f.SaveAs(provinceExcelFile); err != nil {
+		return fmt.Errorf("error menyimpan Excel: %w", err)
+	}
 
-func createProvinceCharts(models []ProvinceModel, trends []NationalTrend) {
-	createProvinceHeatmap20Years(models)
-	createGrowthTrendChart20Years(models)
-	createProjectionChart2030(models)
-	createInvestmentScatterPlot20Years(models)
-	createNationalTrendChart(trends)
+	fmt.Fprintf(progressOutput, "📈 File Excel berhasil dibuat: %s (%d provinsi)\n", provinceExcelFile, len(models))
+	return nil
 }
 
-func createProvinceHeatmap20Years(models []ProvinceModel) {
+func createProvinceHeatmap20Years(models []ProvinceModel) error {
 	p := plot.New()
-	p.Title.Text = "PETA SEBARAN KELAPA SAWIT INDONESIA 2003-2022"
+	p.Title.Text = tr("PETA SEBARAN KELAPA SAWIT INDONESIA 2003-2022")
 	p.Title.TextStyle.Font.Size = vg.Points(16)
-	p.X.Label.Text = "Market Share 2022 (%)"
-	p.Y.Label.Text = "Growth Rate 20 Tahun (%)"
+	p.X.Label.Text = tr("Market Share 2022 (%)")
+	p.Y.Label.Text = tr("Growth Rate 20 Tahun (%)")
 
 	maxMarketShare := getMaxMarketShare(models)
 	maxGrowthRate := getMaxGrowthRate(models)
@@ -506,6 +865,7 @@ func createProvinceHeatmap20Years(models []ProvinceModel) {
 
 	points := make(plotter.XYs, len(models))
 	labels := make([]string, len(models))
+	radii := make([]vg.Length, len(models))
 
 	for i, province := range models {
 		points[i].X = province.MarketShare2022
@@ -516,21 +876,11 @@ func createProvinceHeatmap20Years(models []ProvinceModel) {
 	for i := range models {
 		individualBubble, err := plotter.NewScatter(plotter.XYs{points[i]})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		province := models[i]
-		if province.GrowthRate20Years > 200 {
-			individualBubble.GlyphStyle.Color = color.RGBA{R: 0, G: 100, B: 0, A: 255}
-		} else if province.GrowthRate20Years > 100 {
-			individualBubble.GlyphStyle.Color = color.RGBA{R: 34, G: 139, B: 34, A: 255}
-		} else if province.GrowthRate20Years > 50 {
-			individualBubble.GlyphStyle.Color = color.RGBA{R: 173, G: 255, B: 47, A: 255}
-		} else if province.GrowthRate20Years > 0 {
-			individualBubble.GlyphStyle.Color = color.RGBA{R: 255, G: 255, B: 0, A: 255}
-		} else {
-			individualBubble.GlyphStyle.Color = color.RGBA{R: 255, G: 0, B: 0, A: 255}
-		}
+		individualBubble.GlyphStyle.Color = chartTheme.growthColor(province.GrowthRate20Years)
 
 		radius := vg.Points(4)
 		if province.MarketShare2022 > 10 {
@@ -542,35 +892,32 @@ func createProvinceHeatmap20Years(models []ProvinceModel) {
 		} else {
 			radius = vg.Points(4)
 		}
+		radii[i] = radius
 		individualBubble.GlyphStyle.Radius = radius
 		individualBubble.GlyphStyle.Shape = draw.CircleGlyph{}
 
 		p.Add(individualBubble)
 	}
 
-	labelPoints, err := plotter.NewLabels(plotter.XYLabels{
-		XYs:    points,
-		Labels: labels,
-	})
+	labelPoints, err := newLabelLayer(points, labels, marketShares(models))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	labelPoints.setRadii(radii)
 
 	p.Add(labelPoints)
 
 	p.Add(plotter.NewGrid())
 
-	if err := p.Save(20*vg.Inch, 16*vg.Inch, "peta_heatmap_provinsi_20tahun.png"); err != nil {
-		log.Fatal(err)
-	}
+	return saveChart(p, 20*vg.Inch, 16*vg.Inch, "peta_heatmap_provinsi_20tahun")
 }
 
-func createGrowthTrendChart20Years(models []ProvinceModel) {
+func createGrowthTrendChart20Years(models []ProvinceModel) error {
 	p := plot.New()
-	p.Title.Text = "TREND PERTUMBUHAN PROVINSI 2003-2022 (20 TAHUN)"
+	p.Title.Text = tr("TREND PERTUMBUHAN PROVINSI 2003-2022 (20 TAHUN)")
 	p.Title.TextStyle.Font.Size = vg.Points(16)
-	p.X.Label.Text = "Provinsi"
-	p.Y.Label.Text = "Growth Rate 20 Tahun (%)"
+	p.X.Label.Text = tr("Provinsi")
+	p.Y.Label.Text = tr("Growth Rate 20 Tahun (%)")
 
 	values := make(plotter.Values, len(models))
 	labels := make([]string, len(models))
@@ -582,7 +929,7 @@ func createGrowthTrendChart20Years(models []ProvinceModel) {
 
 	bars, err := plotter.NewBarChart(values, vg.Points(20))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	bars.Color = color.RGBA{R: 70, G: 130, B: 180, A: 255}
@@ -616,17 +963,15 @@ func createGrowthTrendChart20Years(models []ProvinceModel) {
 		}
 	}
 
-	if err := p.Save(24*vg.Inch, 12*vg.Inch, "trend_pertumbuhan_provinsi_20tahun.png"); err != nil {
-		log.Fatal(err)
-	}
+	return saveChart(p, 24*vg.Inch, 12*vg.Inch, "trend_pertumbuhan_provinsi_20tahun")
 }
 
-func createProjectionChart2030(models []ProvinceModel) {
+func createProjectionChart2030(models []ProvinceModel) error {
 	p := plot.New()
-	p.Title.Text = "PROYEKSI AREA KELAPA SAWIT 2030 vs 2022"
+	p.Title.Text = tr("PROYEKSI {{METRIC}} KELAPA SAWIT 2030 vs 2022")
 	p.Title.TextStyle.Font.Size = vg.Points(16)
-	p.X.Label.Text = "Area 2022 (juta ha)"
-	p.Y.Label.Text = "Proyeksi 2030 (juta ha)"
+	p.X.Label.Text = tr("{{Metric}} 2022 (juta {{unit}})")
+	p.Y.Label.Text = tr("Proyeksi 2030 (juta {{unit}})")
 
 	points := make(plotter.XYs, len(models))
 	labels := make([]string, len(models))
@@ -652,7 +997,7 @@ func createProjectionChart2030(models []ProvinceModel) {
 
 	scatter, err := plotter.NewScatter(points)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	scatter.GlyphStyle.Color = color.RGBA{R: 139, G: 0, B: 0, A: 255}
@@ -672,26 +1017,21 @@ func createProjectionChart2030(models []ProvinceModel) {
 	p.X.Min = 0
 	p.Y.Min = 0
 
-	labelPoints, err := plotter.NewLabels(plotter.XYLabels{
-		XYs:    points,
-		Labels: labels,
-	})
+	labelPoints, err := newLabelLayer(points, labels, areas2022(models))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	p.Add(labelPoints)
 
-	if err := p.Save(20*vg.Inch, 16*vg.Inch, "proyeksi_2030.png"); err != nil {
-		log.Fatal(err)
-	}
+	return saveChart(p, 20*vg.Inch, 16*vg.Inch, "proyeksi_2030")
 }
 
-func createNationalTrendChart(trends []NationalTrend) {
+func createNationalTrendChart(trends []NationalTrend) error {
 	p := plot.New()
-	p.Title.Text = "TREND NASIONAL KELAPA SAWIT INDONESIA 2003-2022"
+	p.Title.Text = tr("TREND NASIONAL KELAPA SAWIT INDONESIA 2003-2022")
 	p.Title.TextStyle.Font.Size = vg.Points(16)
-	p.X.Label.Text = "Tahun"
-	p.Y.Label.Text = "Total Area (juta ha)"
+	p.X.Label.Text = tr("Tahun")
+	p.Y.Label.Text = tr("Total {{Metric}} (juta {{unit}})")
 
 	points := make(plotter.XYs, len(trends))
 	for i, trend := range trends {
@@ -701,7 +1041,7 @@ func createNationalTrendChart(trends []NationalTrend) {
 
 	line, err := plotter.NewLine(points)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	line.Color = color.RGBA{R: 0, G: 100, B: 0, A: 255}
 	line.Width = vg.Points(2)
@@ -715,20 +1055,19 @@ func createNationalTrendChart(trends []NationalTrend) {
 	}
 	p.NominalX(yearLabels...)
 
-	if err := p.Save(16*vg.Inch, 8*vg.Inch, "trend_nasional_20tahun.png"); err != nil {
-		log.Fatal(err)
-	}
+	return saveChart(p, 16*vg.Inch, 8*vg.Inch, "trend_nasional_20tahun")
 }
 
-func createInvestmentScatterPlot20Years(models []ProvinceModel) {
+func createInvestmentScatterPlot20Years(models []ProvinceModel) error {
 	p := plot.New()
-	p.Title.Text = "MATRIKS POTENSI INVESTASI PROVINSI 2003-2022"
+	p.Title.Text = tr("MATRIKS POTENSI INVESTASI PROVINSI 2003-2022")
 	p.Title.TextStyle.Font.Size = vg.Points(16)
-	p.X.Label.Text = "Daya Saing (0-10)"
-	p.Y.Label.Text = "Growth Rate 20 Tahun (%)"
+	p.X.Label.Text = tr("Daya Saing (0-10)")
+	p.Y.Label.Text = tr("Growth Rate 20 Tahun (%)")
 
 	points := make(plotter.XYs, len(models))
 	labels := make([]string, len(models))
+	radii := make([]vg.Length, len(models))
 
 	for i, province := range models {
 		points[i].X = province.Competitiveness
@@ -739,25 +1078,11 @@ func createInvestmentScatterPlot20Years(models []ProvinceModel) {
 	for i := range models {
 		individualPoint, err := plotter.NewScatter(plotter.XYs{points[i]})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		province := models[i]
-
-		switch province.InvestmentPotential {
-		case "VERY HIGH":
-			individualPoint.GlyphStyle.Color = color.RGBA{R: 0, G: 100, B: 0, A: 255}
-		case "HIGH":
-			individualPoint.GlyphStyle.Color = color.RGBA{R: 34, G: 139, B: 34, A: 255}
-		case "MEDIUM":
-			individualPoint.GlyphStyle.Color = color.RGBA{R: 255, G: 165, B: 0, A: 255}
-		case "LOW":
-			individualPoint.GlyphStyle.Color = color.RGBA{R: 255, G: 69, B: 0, A: 255}
-		case "VERY LOW":
-			individualPoint.GlyphStyle.Color = color.RGBA{R: 220, G: 20, B: 60, A: 255}
-		default:
-			individualPoint.GlyphStyle.Color = color.RGBA{R: 65, G: 105, B: 225, A: 255}
-		}
+		individualPoint.GlyphStyle.Color = chartTheme.investmentColor(province.InvestmentPotential)
 
 		radius := vg.Points(6)
 		if province.MarketShare2022 > 10 {
@@ -767,18 +1092,17 @@ func createInvestmentScatterPlot20Years(models []ProvinceModel) {
 		} else if province.MarketShare2022 > 2 {
 			radius = vg.Points(7)
 		}
+		radii[i] = radius
 		individualPoint.GlyphStyle.Radius = radius
 
 		p.Add(individualPoint)
 	}
 
-	labelPoints, err := plotter.NewLabels(plotter.XYLabels{
-		XYs:    points,
-		Labels: labels,
-	})
+	labelPoints, err := newLabelLayer(points, labels, marketShares(models))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	labelPoints.setRadii(radii)
 	p.Add(labelPoints)
 
 	p.Add(plotter.NewGrid())
@@ -788,147 +1112,71 @@ func createInvestmentScatterPlot20Years(models []ProvinceModel) {
 	p.Y.Min = getMinGrowthRate(models) * 0.9
 	p.Y.Max = getMaxGrowthRate(models) * 1.1
 
-	if err := p.Save(20*vg.Inch, 16*vg.Inch, "matriks_investasi_provinsi_20tahun.png"); err != nil {
-		log.Fatal(err)
-	}
+	return saveChart(p, 20*vg.Inch, 16*vg.Inch, "matriks_investasi_provinsi_20tahun")
 }
 
-func createStrategicReport(models []ProvinceModel, trends []NationalTrend, decadalAnalysis []DecadalAnalysis) {
-	file, err := os.Create("rekomendasi_strategis_provinsi_20tahun.md")
-	if err != nil {
-		log.Fatal(err)
+func createStrategicReport(models []ProvinceModel, trends []NationalTrend, decadalAnalysis []DecadalAnalysis, concentration []ConcentrationMetrics, spatial *SpatialAnalysis, scenarios []ScenarioResult, monteCarlo *MonteCarloResult, scoring ScoringConfig, sensitivity *SensitivityAnalysis, rankMobility RankMobility, shareChanges []ShareChange, metrics []MetricSummary, regencyYields []RegencyYield, landUse *LandUseAnalysis, forest *DeforestationAnalysis, certification *CertificationAnalysis, templateDir string) error {
+	data := ReportData{
+		GeneratedAt:  time.Now().Format("2 January 2006"),
+		StartYear:    2003,
+		EndYear:      2022,
+		RoadmapStart: 2023,
+		RoadmapEnd:   projectionEndYear,
+		Summary: ReportSummary{
+			TotalProvinces:      len(models),
+			HighGrowthProvinces: countProvincesByGrowth(models, 100),
+			PrimeProvinces:      len(filterProvinces(models, "PRIME")),
+			AverageGrowth:       calculateAverageGrowth(models),
+		},
+		Models:    models,
+		Trends:    trends,
+		Decades:   decadalAnalysis,
+		Scenarios: scenarios,
+		Sections: ReportSections{
+			Concentration:  buildConcentrationReport(concentration),
+			Spatial:        buildSpatialReport(spatial),
+			Scenarios:      buildScenarioReport(models, scenarios),
+			MonteCarlo:     buildMonteCarloReport(monteCarlo),
+			ScoreBreakdown: buildScoreBreakdownReport(models, scoring),
+			Sensitivity:    buildSensitivityReport(sensitivity),
+			RankMobility:   buildRankMobilityReport(rankMobility),
+			MarketShare:    buildMarketShareReport(models, shareChanges),
+			Metrics:        buildMetricReport(metrics, 2003, 2022),
+			Yield:          buildYieldReport(models, regencyYields),
+			LandUse:        buildLandUseReport(landUse),
+			Deforestation:  buildDeforestationReport(forest),
+			Certification:  buildCertificationReport(certification),
+		},
 	}
-	defer file.Close()
-
-	report := `# LAPORAN STRATEGIS KELAPA SAWIT INDONESIA
-## Analisis Berbasis Provinsi 2003-2022
-
-### 📊 EXECUTIVE SUMMARY 20 TAHUN
-
-`
-
-	totalProvinces := len(models)
-	highGrowthProvinces := countProvincesByGrowth(models, 100)
-	primeProvinces := len(filterProvinces(models, "PRIME"))
-
-	report += fmt.Sprintf("- **Total Provinsi Analyzed**: %d\n", totalProvinces)
-	report += fmt.Sprintf("- **Provinsi Growth >100%% (20 tahun)**: %d\n", highGrowthProvinces)
-	report += fmt.Sprintf("- **Prime Provinces**: %d\n", primeProvinces)
 
 	if len(trends) > 0 {
 		firstYear := trends[0]
 		lastYear := trends[len(trends)-1]
-		totalGrowth := ((lastYear.TotalArea - firstYear.TotalArea) / firstYear.TotalArea) * 100
-
-		report += fmt.Sprintf("- **Total Area 2003**: %s ha\n", formatNumber(firstYear.TotalArea))
-		report += fmt.Sprintf("- **Total Area 2022**: %s ha\n", formatNumber(lastYear.TotalArea))
-		report += fmt.Sprintf("- **Total Growth 20 Tahun**: %.1f%%\n", totalGrowth)
+		data.Summary.HasTrends = true
+		data.Summary.TotalArea2003 = firstYear.TotalArea
+		data.Summary.TotalArea2022 = lastYear.TotalArea
+		data.Summary.TotalGrowth = ((lastYear.TotalArea - firstYear.TotalArea) / firstYear.TotalArea) * 100
 	}
-	report += fmt.Sprintf("- **Average Growth Rate 20 Tahun**: %.1f%%\n", calculateAverageGrowth(models))
 
-	if len(decadalAnalysis) >= 2 {
-		report += "\n### 📈 ANALISIS PER DEKADE\n\n"
-		report += "| Dekade | Total Growth | Rata2 Tahunan | Provinsi Terdepan |\n"
-		report += "|--------|--------------|---------------|-------------------|\n"
-		for _, analysis := range decadalAnalysis {
-			report += fmt.Sprintf("| %s | %.1f%% | %.1f%% | %s |\n",
-				analysis.Decade, analysis.TotalGrowth, analysis.AverageAnnual, analysis.LeadingProvince)
-		}
+	for _, category := range provinceCategories {
+		data.Groups = append(data.Groups, ProvinceGroup{Name: category, Provinces: filterProvinces(models, category)})
 	}
 
-	report += "\n### 📋 DATA SEMUA PROVINSI (2003-2022)\n\n"
-	report += "| Rank | Provinsi | Area 2022 (ha) | Growth 20 Tahun | Market Share | Potensi Investasi | Periode Dominan |\n"
-	report += "|------|----------|----------------|-----------------|--------------|-------------------|-----------------|\n"
-
-	for _, model := range models {
-		report += fmt.Sprintf("| %d | %s | %s | %.0f%% | %.1f%% | %s | %s |\n",
-			model.Rank2022,
-			model.Province,
-			formatNumber(model.TotalArea2022),
-			model.GrowthRate20Years,
-			model.MarketShare2022,
-			model.InvestmentPotential,
-			model.DominantPeriod)
+	tmpl, err := loadReportTemplates(templateDir)
+	if err != nil {
+		return err
 	}
-
-	report += "\n### 🎯 KELOMPOK PROVINSI BERDASARKAN KINERJA 20 TAHUN\n"
-
-	categories := []struct {
-		name     string
-		category string
-	}{
-		{"PRIME", "PRIME"},
-		{"GROWTH", "GROWTH"},
-		{"EMERGING", "EMERGING"},
-		{"STABLE", "STABLE"},
-		{"MATURE", "MATURE"},
-	}
-
-	for _, cat := range categories {
-		provinces := filterProvinces(models, cat.category)
-		if len(provinces) > 0 {
-			report += fmt.Sprintf("\n#### %s (%d provinsi)\n", cat.name, len(provinces))
-			for _, province := range provinces {
-				report += fmt.Sprintf("- **%s**: Area %s ha, Growth %.0f%%, %s\n",
-					province.Province, formatNumber(province.TotalArea2022),
-					province.GrowthRate20Years, strings.Join(province.Recommendations[:1], ", "))
-			}
-		}
+	report, err := renderReportTemplate(tmpl, data)
+	if err != nil {
+		return err
 	}
 
-	report += `
-### 🚀 REKOMENDASI STRATEGIS 2023-2030
-
-#### 1. OPTIMISASI PROVINSI PRIME
-- **Fokus**: Provinsi dengan area >1 juta ha dan growth >100%
-- **Strategi**: Technology leadership, precision agriculture
-- **Target**: Productivity improvement 20-30%
-
-#### 2. AKSELERASI PROVINSI GROWTH  
-- **Fokus**: Provinsi dengan growth >200% dalam 20 tahun
-- **Strategi**: Sustainable expansion dengan circular economy
-- **Target**: Market share increase 15-25%
-
-#### 3. PENGEMBANGAN PROVINSI EMERGING
-- **Fokus**: Region baru dengan potensi tinggi
-- **Strategi**: Integrated plantation development
-- **Target**: Establish new sustainable growth centers
-
-#### 4. TRANSFORMASI PROVINSI MATURE
-- **Fokus**: Provinsi dengan growth rendah tapi area besar
-- **Strategi**: Diversification dan value-added products
-- **Target**: Revenue diversification 30-40%
-
-#### 5. SUSTAINABILITY ROADMAP 2030
-- **Scope**: Semua provinsi
-- **Strategi**: ISPO/RSPO certification, NDPE compliance
-- **Target**: 100% sustainable certification by 2030
-
-### 📅 ROADMAP IMPLEMENTASI 2023-2030
-
-**2023-2025**: 
-- Digital transformation di provinsi prime
-- Penyusunan masterplan sustainability
-- Pilot projects di provinsi emerging
-
-**2026-2028**:
-- Scale up sustainable practices
-- Technology adoption massal
-- Market diversification
-
-**2029-2030**:
-- Full certification implementation
-- Evaluation dan adjustment
-- Preparation untuk fase berikutnya
-
----
-*Generated by Palm Oil Analytics System - %s*
-`
-
-	report = fmt.Sprintf(report, time.Now().Format("2 January 2006"))
+	if err := os.WriteFile(strategicReportFile, []byte(report), 0o644); err != nil {
+		return fmt.Errorf("error menyimpan laporan: %w", err)
+	}
+	fmt.Fprintln(progressOutput, "📋 Laporan strategis 20 tahun berhasil dibuat:", strategicReportFile)
 
-	file.WriteString(report)
-	fmt.Println("📋 Laporan strategis 20 tahun berhasil dibuat: rekomendasi_strategis_provinsi_20tahun.md")
+	return createStrategicReportPDF(report)
 }
 
 func analyzeProvinceTrend20Years(yearlyData map[int]float64) string {
@@ -1053,60 +1301,31 @@ func calculateStabilityIndex(yearlyData map[int]float64) float64 {
 	return math.Max(1.0, math.Min(10.0, stability))
 }
 
-func calculateEfficiency(area, growth, stability float64) float64 {
-	baseEfficiency := 5.0
-
-	if area > 1000000 {
-		baseEfficiency += 2.0
-	} else if area > 500000 {
-		baseEfficiency += 1.0
-	}
-
-	if growth > 200 {
-		baseEfficiency += 1.5
-	} else if growth > 100 {
-		baseEfficiency += 1.0
-	}
-
-	baseEfficiency += (stability - 5.0) / 2
-
-	return math.Min(10.0, baseEfficiency)
-}
-
-func calculateCompetitivenessScore(model ProvinceModel) float64 {
-	score := 5.0
-
-	score += model.MarketShare2022 / 10
-	score += model.GrowthRate20Years / 100
-	score += model.ProductionEfficiency / 2
-	score += model.StabilityIndex / 2
-
-	return math.Min(10.0, score)
-}
-
-func assessInvestmentPotential(model ProvinceModel) string {
+func assessInvestmentPotential(model ProvinceModel, scoring ScoringConfig) string {
 	score := model.Competitiveness
 
-	if score >= 8.0 {
-		return "VERY HIGH"
-	} else if score >= 6.5 {
-		return "HIGH"
-	} else if score >= 5.0 {
-		return "MEDIUM"
-	} else if score >= 3.0 {
-		return "LOW"
+	for _, level := range investmentLevels {
+		if score >= scoring.InvestmentThresholds[level] {
+			return level
+		}
 	}
 	return "VERY LOW"
 }
 
 func assessRiskLevel(model ProvinceModel) string {
-	if model.GrowthRate20Years > 500 {
+	return classifyRiskLevel(model, defaultRiskThresholds)
+}
+
+func classifyRiskLevel(model ProvinceModel, t RiskThresholds) string {
+	if model.Deforestation != nil && model.Deforestation.Risk == "HIGH" {
 		return "HIGH"
-	} else if model.StabilityIndex < 4 {
+	} else if model.GrowthRate20Years > t.HighGrowth {
+		return "HIGH"
+	} else if model.StabilityIndex < t.LowStability {
 		return "HIGH"
 	} else if model.GrowthRate20Years < 0 {
 		return "MEDIUM-HIGH"
-	} else if model.MarketShare2022 < 1 && model.GrowthRate20Years < 50 {
+	} else if model.MarketShare2022 < t.LowMarketShare && model.GrowthRate20Years < t.LowShareGrowth {
 		return "MEDIUM"
 	}
 	return "LOW-MEDIUM"
@@ -1116,40 +1335,50 @@ func generateProvinceRecommendations(model ProvinceModel) []string {
 	var recs []string
 
 	if model.MarketShare2022 > 15 {
-		recs = append(recs, "Maintain market leadership through innovation")
-		recs = append(recs, "Focus on sustainable intensification")
+		recs = append(recs, tr("Maintain market leadership through innovation"))
+		recs = append(recs, tr("Focus on sustainable intensification"))
 	}
 
 	if model.GrowthRate20Years > 200 {
-		recs = append(recs, "Ensure sustainable expansion practices")
-		recs = append(recs, "Invest in supply chain optimization")
+		recs = append(recs, tr("Ensure sustainable expansion practices"))
+		recs = append(recs, tr("Invest in supply chain optimization"))
 	} else if model.GrowthRate20Years < 50 && model.TotalArea2022 > 500000 {
-		recs = append(recs, "Diversify revenue streams")
-		recs = append(recs, "Explore value-added products")
+		recs = append(recs, tr("Diversify revenue streams"))
+		recs = append(recs, tr("Explore value-added products"))
 	}
 
 	if model.StabilityIndex < 5 {
-		recs = append(recs, "Improve operational consistency")
-		recs = append(recs, "Risk management implementation")
+		recs = append(recs, tr("Improve operational consistency"))
+		recs = append(recs, tr("Risk management implementation"))
+	}
+
+	if model.Deforestation != nil && model.Deforestation.Risk != "LOW" {
+		recs = append(recs, tr("Enforce NDPE commitments and halt expansion into forest areas"))
+		recs = append(recs, tr("Prioritise ISPO/RSPO audits in flagged regencies"))
 	}
 
 	if len(recs) == 0 {
-		recs = append(recs, "Continuous improvement with sustainability focus")
+		recs = append(recs, tr("Continuous improvement with sustainability focus"))
 	}
 
 	return recs
 }
 
 func calculateProjection2030(model ProvinceModel) float64 {
+	annualGrowth := baselineAnnualGrowth(model)
+
+	projection := model.TotalArea2022 * math.Pow(1+annualGrowth/100, 8)
+	return projection
+}
+
+func baselineAnnualGrowth(model ProvinceModel) float64 {
 	annualGrowth := model.AnnualGrowthRate
 	if annualGrowth <= 0 {
 		annualGrowth = 3.0
 	} else if annualGrowth > 10 {
 		annualGrowth = 8.0
 	}
-
-	projection := model.TotalArea2022 * math.Pow(1+annualGrowth/100, 8)
-	return projection
+	return annualGrowth
 }
 
 func calculateYearlyGrowthRates(yearlyData map[int]float64) []float64 {
@@ -1200,16 +1429,17 @@ func calculatePhaseGrowth(yearlyData map[int]float64, startYear, endYear int) fl
 }
 
 func getPhaseDescription(growthRate float64, period string) string {
+	period = tr(period)
 	if growthRate > 100 {
-		return period + " explosive growth"
+		return trf("%s explosive growth", period)
 	} else if growthRate > 50 {
-		return period + " high growth"
+		return trf("%s high growth", period)
 	} else if growthRate > 20 {
-		return period + " moderate growth"
+		return trf("%s moderate growth", period)
 	} else if growthRate > 0 {
-		return period + " slow growth"
+		return trf("%s slow growth", period)
 	}
-	return period + " decline"
+	return trf("%s decline", period)
 }
 
 func getSortedYears(yearlyData map[int]float64) []int {
@@ -1221,16 +1451,6 @@ func getSortedYears(yearlyData map[int]float64) []int {
 	return years
 }
 
-func calculateNationalAreaForYear(rawData []RawPalmOilData, year int) float64 {
-	total := 0.0
-	for _, data := range rawData {
-		if data.Year == year {
-			total += data.PlantedArea
-		}
-	}
-	return total
-}
-
 func findLeadingProvince(models []ProvinceModel, startYear, endYear int) string {
 	if len(models) == 0 {
 		return "Unknown"
@@ -1251,22 +1471,22 @@ func findEmergingRegions(models []ProvinceModel, startYear, endYear int) []strin
 func getKeyEventsForDecade(decade string) []string {
 	events := map[string][]string{
 		"2003-2012": {
-			"Ekspansi cepat kelapa sawit",
-			"Peningkatan permintaan global",
-			"Pembukaan lahan baru",
+			tr("Ekspansi cepat kelapa sawit"),
+			tr("Peningkatan permintaan global"),
+			tr("Pembukaan lahan baru"),
 		},
 		"2013-2022": {
-			"Fokus sustainability",
-			"Sertifikasi ISPO/RSPO",
-			"Tekanan lingkungan global",
-			"Peningkatan produktivitas",
+			tr("Fokus sustainability"),
+			tr("Sertifikasi ISPO/RSPO"),
+			tr("Tekanan lingkungan global"),
+			tr("Peningkatan produktivitas"),
 		},
 	}
 
 	if eventList, exists := events[decade]; exists {
 		return eventList
 	}
-	return []string{"Perkembangan industri normal"}
+	return []string{tr("Perkembangan industri normal")}
 }
 
 func formatNumber(num float64) string {
@@ -1327,40 +1547,47 @@ func filterProvinces(models []ProvinceModel, category string) []ProvinceModel {
 	var filtered []ProvinceModel
 
 	for _, model := range models {
-		switch category {
-		case "PRIME":
-			if model.TotalArea2022 > 1000000 && model.GrowthRate20Years > 100 {
-				filtered = append(filtered, model)
-			}
-		case "GROWTH":
-			if model.GrowthRate20Years > 200 {
-				filtered = append(filtered, model)
-			}
-		case "EMERGING":
-			if model.TotalArea2022 < 500000 && model.GrowthRate20Years > 300 {
-				filtered = append(filtered, model)
-			}
-		case "STABLE":
-			if model.TotalArea2022 > 500000 && model.GrowthRate20Years >= 50 && model.GrowthRate20Years <= 150 {
-				filtered = append(filtered, model)
-			}
-		case "MATURE":
-			if model.TotalArea2022 > 500000 && model.GrowthRate20Years < 50 {
-				filtered = append(filtered, model)
-			}
+		if matchesCategory(model, category, defaultCategoryThresholds) {
+			filtered = append(filtered, model)
 		}
 	}
 
 	return filtered
 }
 
-func writeProvinceGroup(f *excelize.File, provinces []ProvinceModel, startRow int) {
+func modelCategories(model ProvinceModel) []string {
+	var categories []string
+	for _, category := range provinceCategories {
+		if matchesCategory(model, category, defaultCategoryThresholds) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+func matchesCategory(model ProvinceModel, category string, t CategoryThresholds) bool {
+	switch category {
+	case "PRIME":
+		return model.TotalArea2022 > t.PrimeMinArea && model.GrowthRate20Years > t.PrimeMinGrowth
+	case "GROWTH":
+		return model.GrowthRate20Years > t.GrowthMinGrowth
+	case "EMERGING":
+		return model.TotalArea2022 < t.EmergingMaxArea && model.GrowthRate20Years > t.EmergingMinGrowth
+	case "STABLE":
+		return model.TotalArea2022 > t.StableMinArea && model.GrowthRate20Years >= t.StableMinGrowth && model.GrowthRate20Years <= t.StableMaxGrowth
+	case "MATURE":
+		return model.TotalArea2022 > t.MatureMinArea && model.GrowthRate20Years < t.MatureMaxGrowth
+	}
+	return false
+}
+
+func writeProvinceGroup(f *excelize.File, sheet string, provinces []ProvinceModel, startRow int) {
 	for i, province := range provinces {
 		row := startRow + i
-		f.SetCellValue("Kelompok_Provinsi_20Tahun", fmt.Sprintf("A%d", row), province.Province)
-		f.SetCellValue("Kelompok_Provinsi_20Tahun", fmt.Sprintf("B%d", row), formatNumber(province.TotalArea2022))
-		f.SetCellValue("Kelompok_Provinsi_20Tahun", fmt.Sprintf("C%d", row), fmt.Sprintf("%.1f%%", province.GrowthRate20Years))
-		f.SetCellValue("Kelompok_Provinsi_20Tahun", fmt.Sprintf("D%d", row), province.InvestmentPotential)
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), province.Province)
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), formatNumber(province.TotalArea2022))
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), fmt.Sprintf("%.1f%%", province.GrowthRate20Years))
+		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), tr(province.InvestmentPotential))
 	}
 }
 
@@ -1417,6 +1644,22 @@ func getMaxGrowthRate(models []ProvinceModel) float64 {
 	return max
 }
 
+func marketShares(models []ProvinceModel) []float64 {
+	values := make([]float64, len(models))
+	for i, model := range models {
+		values[i] = model.MarketShare2022
+	}
+	return values
+}
+
+func areas2022(models []ProvinceModel) []float64 {
+	values := make([]float64, len(models))
+	for i, model := range models {
+		values[i] = model.TotalArea2022
+	}
+	return values
+}
+
 func getMaxMarketShare(models []ProvinceModel) float64 {
 	if len(models) == 0 {
 		return 0